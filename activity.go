@@ -0,0 +1,72 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ActivityEntry is a snapshot of one non-idle backend from pg_stat_activity.
+type ActivityEntry struct {
+	PID           int
+	State         string
+	Query         string
+	WaitEventType string
+	WaitEvent     string
+	Duration      time.Duration
+}
+
+// ActiveQueries returns every backend in pg_stat_activity that is not idle,
+// excluding the connection running the check. Duration is measured from the
+// start of the backend's current query.
+func (a *PostgreSQLAdapter) ActiveQueries(ctx context.Context) ([]ActivityEntry, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	query := `SELECT pid, COALESCE(state, ''), COALESCE(query, ''),
+		COALESCE(wait_event_type, ''), COALESCE(wait_event, ''),
+		COALESCE(EXTRACT(EPOCH FROM now() - query_start), 0)::float8
+		FROM pg_stat_activity
+		WHERE state != 'idle' AND pid != pg_backend_pid()
+		ORDER BY query_start`
+
+	rows, err := a.queryContext(ctx, "active_queries", query)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to read pg_stat_activity: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []ActivityEntry
+	for rows.Next() {
+		var e ActivityEntry
+		var seconds float64
+		if err := rows.Scan(&e.PID, &e.State, &e.Query, &e.WaitEventType, &e.WaitEvent, &seconds); err != nil {
+			return nil, fmt.Errorf("postgresql: scan failed: %w", err)
+		}
+		e.Duration = time.Duration(seconds * float64(time.Second))
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgresql: rows iteration failed: %w", err)
+	}
+
+	return entries, nil
+}
+
+// TerminateBackend ends the backend with the given pid using
+// pg_terminate_backend. It reports false when no such backend exists or the
+// signal could not be sent.
+func (a *PostgreSQLAdapter) TerminateBackend(ctx context.Context, pid int) (bool, error) {
+	if a.db == nil {
+		return false, ErrNotConnected
+	}
+
+	var terminated bool
+	if err := a.queryRowContext(ctx, "terminate_backend", "SELECT pg_terminate_backend($1)", pid).Scan(&terminated); err != nil {
+		return false, fmt.Errorf("postgresql: failed to terminate backend %d: %w", pid, err)
+	}
+
+	return terminated, nil
+}
@@ -0,0 +1,24 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPostgreSQLAdapter_ActiveQueriesWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if _, err := a.ActiveQueries(context.Background()); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_TerminateBackendWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	ok, err := a.TerminateBackend(context.Background(), 1234)
+	if err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+	if ok {
+		t.Error("expected terminated=false when not connected")
+	}
+}
@@ -2,7 +2,14 @@ package postgresql
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/toutaio/toutago-datamapper/adapter"
 )
@@ -30,6 +37,37 @@ func TestPostgreSQLAdapter_NewAdapter(t *testing.T) {
 	}
 }
 
+func TestWithDB(t *testing.T) {
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1")
+	if err != nil {
+		t.Fatalf("failed to open pool: %v", err)
+	}
+
+	a := WithDB(db, WithDriver(DriverPGX))
+	if a.DB() != db {
+		t.Error("expected DB to return the injected pool")
+	}
+	if a.driverName != DriverPGX {
+		t.Errorf("expected options to be applied, got driver %q", a.driverName)
+	}
+	if err := a.Reconnect(context.Background()); err == nil {
+		t.Error("expected Reconnect to fail without a config, got nil")
+	}
+
+	if err := a.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	if err := db.Ping(); err == nil || !strings.Contains(err.Error(), "closed") {
+		t.Errorf("expected Close to close the injected pool, got %v", err)
+	}
+}
+
+func TestPostgreSQLAdapter_DBWithoutConnect(t *testing.T) {
+	if db := NewPostgreSQLAdapter().DB(); db != nil {
+		t.Errorf("expected nil pool before Connect, got %v", db)
+	}
+}
+
 func TestPostgreSQLAdapter_CloseWithoutConnect(t *testing.T) {
 	a := NewPostgreSQLAdapter()
 	if err := a.Close(); err != nil {
@@ -37,6 +75,35 @@ func TestPostgreSQLAdapter_CloseWithoutConnect(t *testing.T) {
 	}
 }
 
+func TestPostgreSQLAdapter_ConnectWithFallbackNoConfigs(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if err := a.ConnectWithFallback(context.Background(), nil); err == nil {
+		t.Error("expected error with no configs, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_ConnectWithFallbackAllFail(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	configs := []map[string]interface{}{
+		{"host": "127.0.0.1", "port": 1},
+		{"host": "127.0.0.1", "port": 2},
+	}
+
+	if err := a.ConnectWithFallback(context.Background(), configs); err == nil {
+		t.Error("expected error when every host is unreachable, got nil")
+	}
+	if a.db != nil {
+		t.Error("expected no connection to be kept after all attempts failed")
+	}
+}
+
+func TestPostgreSQLAdapter_ReconnectWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if err := a.Reconnect(context.Background()); err == nil {
+		t.Error("expected error when reconnecting without a prior connect, got nil")
+	}
+}
+
 func TestReplaceNamedParams(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -63,6 +130,11 @@ func TestReplaceNamedParams(t *testing.T) {
 			input:    "INSERT INTO users (id, name) VALUES ({id}, {name})",
 			expected: "INSERT INTO users (id, name) VALUES ($1, $2)",
 		},
+		{
+			name:     "repeated parameter",
+			input:    "SELECT * FROM docs WHERE id = {id} OR owner_id = {id} AND kind = {kind}",
+			expected: "SELECT * FROM docs WHERE id = $1 OR owner_id = $1 AND kind = $2",
+		},
 	}
 
 	for _, tt := range tests {
@@ -111,6 +183,13 @@ func TestExtractArgs(t *testing.T) {
 			expected:  []interface{}{},
 			expectErr: false,
 		},
+		{
+			name:      "repeated parameter",
+			query:     "SELECT * FROM docs WHERE id = {id} OR owner_id = {id} AND kind = {kind}",
+			params:    map[string]interface{}{"id": 7, "kind": "note"},
+			expected:  []interface{}{7, "note"},
+			expectErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -299,3 +378,387 @@ func TestGetIntConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestGetIntConfig_PoolTimeouts(t *testing.T) {
+	config := map[string]interface{}{
+		ConfigConnMaxLifetime: 1800,
+		ConfigConnMaxIdleTime: 300.0,
+	}
+
+	if v := getIntConfig(config, ConfigConnMaxLifetime, 0); v != 1800 {
+		t.Errorf("expected lifetime 1800, got %d", v)
+	}
+	if v := getIntConfig(config, ConfigConnMaxIdleTime, 0); v != 300 {
+		t.Errorf("expected idle time 300, got %d", v)
+	}
+}
+
+func TestPostgreSQLAdapter_ApplyPoolConfig(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           map[string]interface{}
+		expectedLifetime int
+		expectedIdleTime int
+	}{
+		{
+			name:             "defaults",
+			config:           map[string]interface{}{},
+			expectedLifetime: 3600,
+			expectedIdleTime: 0,
+		},
+		{
+			name:             "legacy max age",
+			config:           map[string]interface{}{ConfigConnAge: 600},
+			expectedLifetime: 600,
+		},
+		{
+			name: "lifetime overrides max age",
+			config: map[string]interface{}{
+				ConfigConnAge:         600,
+				ConfigConnMaxLifetime: 900,
+				ConfigConnMaxIdleTime: 120,
+			},
+			expectedLifetime: 900,
+			expectedIdleTime: 120,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewPostgreSQLAdapter()
+			a.applyPoolConfig(tt.config)
+			if a.connMaxAge != tt.expectedLifetime {
+				t.Errorf("expected lifetime %d, got %d", tt.expectedLifetime, a.connMaxAge)
+			}
+			if a.connMaxIdleTime != tt.expectedIdleTime {
+				t.Errorf("expected idle time %d, got %d", tt.expectedIdleTime, a.connMaxIdleTime)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_ConfigurePool(t *testing.T) {
+	// sql.Open does not connect, so the pool can be inspected offline.
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	a := NewPostgreSQLAdapter()
+	a.applyPoolConfig(map[string]interface{}{
+		ConfigMaxConn:         7,
+		ConfigConnMaxLifetime: 1800,
+		ConfigConnMaxIdleTime: 300,
+	})
+	a.configurePool(db)
+
+	if stats := db.Stats(); stats.MaxOpenConnections != 7 {
+		t.Errorf("expected max open connections 7, got %d", stats.MaxOpenConnections)
+	}
+
+	// database/sql has no getters for the timeouts, so read them directly.
+	pool := reflect.ValueOf(db).Elem()
+	for field, expected := range map[string]time.Duration{
+		"maxLifetime": 1800 * time.Second,
+		"maxIdleTime": 300 * time.Second,
+	} {
+		v := pool.FieldByName(field)
+		if !v.IsValid() {
+			t.Skipf("sql.DB has no %s field in this Go version", field)
+		}
+		if got := time.Duration(v.Int()); got != expected {
+			t.Errorf("expected %s %v, got %v", field, expected, got)
+		}
+	}
+}
+
+func TestPostgreSQLAdapter_BuildDSN(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		config   map[string]interface{}
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "key value",
+			config:   map[string]interface{}{ConfigHost: "db", ConfigDatabase: "app"},
+			expected: "host=db port=5432 user=postgres password='' dbname=app sslmode=disable",
+		},
+		{
+			name:     "postgres url",
+			config:   map[string]interface{}{ConfigConnectionURL: "postgres://u:p@db:5432/app?sslmode=require"},
+			expected: "postgres://u:p@db:5432/app?sslmode=require",
+		},
+		{
+			name:     "postgresql url",
+			config:   map[string]interface{}{ConfigConnectionURL: "postgresql://db/app"},
+			expected: "postgresql://db/app",
+		},
+		{
+			name:     "url with application name",
+			opts:     []Option{WithApplicationName("billing")},
+			config:   map[string]interface{}{ConfigConnectionURL: "postgres://db/app?sslmode=require"},
+			expected: "postgres://db/app?application_name=billing&sslmode=require",
+		},
+		{
+			name:     "url keeps its own application name",
+			opts:     []Option{WithApplicationName("billing")},
+			config:   map[string]interface{}{ConfigConnectionURL: "postgres://db/app?application_name=web"},
+			expected: "postgres://db/app?application_name=web",
+		},
+		{
+			name:     "key value with statement timeout",
+			opts:     []Option{WithStatementTimeout(5 * time.Second)},
+			config:   map[string]interface{}{ConfigHost: "db"},
+			expected: "host=db port=5432 user=postgres password='' dbname='' sslmode=disable statement_timeout=5000",
+		},
+		{
+			name:     "url with statement timeout",
+			opts:     []Option{WithStatementTimeout(1500 * time.Millisecond)},
+			config:   map[string]interface{}{ConfigConnectionURL: "postgres://db/app"},
+			expected: "postgres://db/app?statement_timeout=1500",
+		},
+		{
+			name:    "wrong scheme",
+			config:  map[string]interface{}{ConfigConnectionURL: "mysql://db/app"},
+			wantErr: true,
+		},
+		{
+			name:     "quoted password",
+			config:   map[string]interface{}{ConfigHost: "db", ConfigPassword: `it's a\pass`},
+			expected: `host=db port=5432 user=postgres password='it\'s a\\pass' dbname='' sslmode=disable`,
+		},
+		{
+			name:    "host injection",
+			config:  map[string]interface{}{ConfigHost: "localhost dbname=postgres password=hacked"},
+			wantErr: true,
+		},
+		{
+			name:    "url combined with host",
+			config:  map[string]interface{}{ConfigConnectionURL: "postgres://db/app", ConfigHost: "other"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewPostgreSQLAdapter(tt.opts...)
+			dsn, err := a.buildDSN(tt.config)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got DSN %q", dsn)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if dsn != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, dsn)
+			}
+		})
+	}
+}
+
+func TestSanitizeDSNValue(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"db.example.com", false},
+		{"app@tenant", false},
+		{"", false},
+		{"localhost dbname=postgres", true},
+		{"a=b", true},
+		{"it's", true},
+		{`back\slash`, true},
+		{"tab\there", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			err := sanitizeDSNValue(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("sanitizeDSNValue(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_BuildDSN_SSLFiles(t *testing.T) {
+	dir := t.TempDir()
+	cert := filepath.Join(dir, "client cert.pem")
+	key := filepath.Join(dir, "client.key")
+	for _, path := range []string{cert, key} {
+		if err := os.WriteFile(path, []byte("pem"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a := NewPostgreSQLAdapter()
+	dsn, err := a.buildDSN(map[string]interface{}{ConfigHost: "db", ConfigSSLMode: "verify-full", ConfigSSLCert: cert, ConfigSSLKey: key})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := fmt.Sprintf("host=db port=5432 user=postgres password='' dbname='' sslmode=verify-full sslcert='%s' sslkey='%s'", cert, key)
+	if dsn != expected {
+		t.Errorf("expected %q, got %q", expected, dsn)
+	}
+
+	_, err = a.buildDSN(map[string]interface{}{ConfigHost: "db", ConfigSSLRootCert: filepath.Join(dir, "missing.pem")})
+	if err == nil || !strings.Contains(err.Error(), "sslrootcert") {
+		t.Errorf("expected sslrootcert error, got %v", err)
+	}
+}
+
+func TestMultiRowValues(t *testing.T) {
+	props := []adapter.PropertyMapping{
+		{ObjectField: "Name", DataField: "name"},
+		{ObjectField: "Email", DataField: "email"},
+	}
+	objects := []interface{}{
+		map[string]interface{}{"Name": "a", "Email": "a@x"},
+		map[string]interface{}{"Name": "b", "Email": "b@x"},
+	}
+
+	rows, values, err := multiRowValues(props, objects)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "($1, $2), ($3, $4)"; rows != expected {
+		t.Errorf("expected %q, got %q", expected, rows)
+	}
+	if !reflect.DeepEqual(values, []interface{}{"a", "a@x", "b", "b@x"}) {
+		t.Errorf("unexpected values: %v", values)
+	}
+}
+
+func TestPostgreSQLAdapter_InsertWithReturningMultiRow(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, "CREATE TABLE returning_test (id bigserial PRIMARY KEY, seq int NOT NULL)"); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE returning_test") })
+
+	op := &adapter.Operation{
+		Statement:  "returning_test",
+		Properties: []adapter.PropertyMapping{{ObjectField: "Seq", DataField: "seq"}},
+		Generated:  []adapter.PropertyMapping{{ObjectField: "ID", DataField: "id"}},
+	}
+
+	const n = 150
+	objects := make([]interface{}, n)
+	for i := range objects {
+		objects[i] = map[string]interface{}{"Seq": i}
+	}
+	if err := a.Insert(ctx, op, objects); err != nil {
+		t.Fatalf("insert failed: %v", err)
+	}
+
+	stored := make(map[int64]int64, n)
+	rows, err := a.db.QueryContext(ctx, "SELECT id, seq FROM returning_test")
+	if err != nil {
+		t.Fatalf("select failed: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, seq int64
+		if err := rows.Scan(&id, &seq); err != nil {
+			t.Fatalf("scan failed: %v", err)
+		}
+		stored[id] = seq
+	}
+	if len(stored) != n {
+		t.Fatalf("expected %d rows, got %d", n, len(stored))
+	}
+
+	for i, obj := range objects {
+		id, ok := obj.(map[string]interface{})["ID"].(int64)
+		if !ok {
+			t.Fatalf("object %d: no generated ID assigned", i)
+		}
+		if seq, ok := stored[id]; !ok || seq != int64(i) {
+			t.Errorf("object %d: ID %d belongs to row with seq %d", i, id, seq)
+		}
+	}
+}
+
+func TestPostgreSQLAdapter_StatementTimeoutConfig(t *testing.T) {
+	a := NewPostgreSQLAdapter(WithStatementTimeout(time.Second))
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// The ping fails, but the DSN is built first.
+	_, _ = a.open(ctx, map[string]interface{}{
+		ConfigHost:             "127.0.0.1",
+		ConfigPort:             1,
+		ConfigStatementTimeout: 250,
+	})
+	if a.statementTimeout != 250*time.Millisecond {
+		t.Errorf("expected config to override the option, got %v", a.statementTimeout)
+	}
+	if !strings.HasSuffix(a.dsn, " statement_timeout=250") {
+		t.Errorf("expected statement_timeout in DSN, got %q", a.dsn)
+	}
+}
+
+func TestPostgreSQLAdapter_ApplicationNameConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		config   map[string]interface{}
+		expected string
+	}{
+		{"default", nil, map[string]interface{}{}, filepath.Base(os.Args[0])},
+		{"option", []Option{WithApplicationName("billing")}, map[string]interface{}{}, "billing"},
+		{"config wins", []Option{WithApplicationName("billing")}, map[string]interface{}{ConfigApplicationName: "reports"}, "reports"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config[ConfigHost] = "127.0.0.1"
+			tt.config[ConfigPort] = 1
+
+			// The ping fails, but the DSN is built first.
+			a := NewPostgreSQLAdapter(tt.opts...)
+			if _, err := a.open(context.Background(), tt.config); err == nil {
+				t.Fatal("expected ping to fail")
+			}
+			if a.applicationName != tt.expected {
+				t.Errorf("expected application name %q, got %q", tt.expected, a.applicationName)
+			}
+			if !strings.Contains(a.dsn, "application_name='"+tt.expected+"'") {
+				t.Errorf("expected DSN to carry the application name, got %q", a.dsn)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_ApplicationNameSetting(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if !isURLDSN(dsn) {
+		t.Skip("POSTGRES_TEST_DSN not set to a postgres:// URL; skipping integration test")
+	}
+
+	a := NewPostgreSQLAdapter()
+	err := a.Connect(context.Background(), map[string]interface{}{
+		ConfigConnectionURL:   dsn,
+		ConfigApplicationName: "datamapper-test",
+	})
+	if err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	defer a.Close()
+
+	var name string
+	if err := a.db.QueryRow("SELECT current_setting('application_name')").Scan(&name); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if name != "datamapper-test" {
+		t.Errorf("expected application_name 'datamapper-test', got %q", name)
+	}
+}
@@ -0,0 +1,156 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+)
+
+// advisoryLocks tracks the pinned connections holding session-level advisory
+// locks, so each lock is released on the session that took it.
+type advisoryLocks struct {
+	mu    sync.Mutex
+	conns map[int64][]*sql.Conn
+}
+
+func (l *advisoryLocks) push(key int64, conn *sql.Conn) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conns == nil {
+		l.conns = make(map[int64][]*sql.Conn)
+	}
+	l.conns[key] = append(l.conns[key], conn)
+}
+
+func (l *advisoryLocks) pop(key int64) *sql.Conn {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	held := l.conns[key]
+	if len(held) == 0 {
+		return nil
+	}
+	conn := held[len(held)-1]
+	if len(held) == 1 {
+		delete(l.conns, key)
+	} else {
+		l.conns[key] = held[:len(held)-1]
+	}
+	return conn
+}
+
+// TryAdvisoryLock takes the session-level advisory lock key if it is free and
+// reports whether it did. The lock is held on a connection pinned out of the
+// pool until AdvisoryUnlock releases it.
+func (a *PostgreSQLAdapter) TryAdvisoryLock(ctx context.Context, key int64) (bool, error) {
+	if a.db == nil {
+		return false, ErrNotConnected
+	}
+
+	conn, err := a.conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("postgresql: failed to pin connection: %w", err)
+	}
+
+	var acquired bool
+	query := a.annotate("SELECT pg_try_advisory_lock($1)", "try_advisory_lock")
+	if err := conn.QueryRowContext(ctx, query, key).Scan(&acquired); err != nil {
+		_ = a.releaseConn(conn)
+		return false, fmt.Errorf("postgresql: failed to try advisory lock %d: %w", key, err)
+	}
+	if !acquired {
+		_ = a.releaseConn(conn)
+		return false, nil
+	}
+
+	a.advisoryLocks.push(key, conn)
+	return true, nil
+}
+
+// AdvisoryLock takes the session-level advisory lock key, waiting until it is
+// free or ctx is done. Like TryAdvisoryLock it pins a connection until
+// AdvisoryUnlock. Each call takes its own connection, so two goroutines
+// locking the same key through one adapter exclude each other.
+func (a *PostgreSQLAdapter) AdvisoryLock(ctx context.Context, key int64) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	conn, err := a.conn(ctx)
+	if err != nil {
+		return fmt.Errorf("postgresql: failed to pin connection: %w", err)
+	}
+
+	query := a.annotate("SELECT pg_advisory_lock($1)", "advisory_lock")
+	if _, err := conn.ExecContext(ctx, query, key); err != nil {
+		_ = a.releaseConn(conn)
+		return fmt.Errorf("postgresql: failed to take advisory lock %d: %w", key, err)
+	}
+
+	a.advisoryLocks.push(key, conn)
+	return nil
+}
+
+// AdvisoryUnlock releases a session-level advisory lock taken by
+// TryAdvisoryLock or AdvisoryLock and returns its connection to the pool.
+// It returns ErrLockNotHeld if this adapter does not hold key.
+func (a *PostgreSQLAdapter) AdvisoryUnlock(ctx context.Context, key int64) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	conn := a.advisoryLocks.pop(key)
+	if conn == nil {
+		return fmt.Errorf("%w: %d", ErrLockNotHeld, key)
+	}
+
+	var released bool
+	query := a.annotate("SELECT pg_advisory_unlock($1)", "advisory_unlock")
+	if err := conn.QueryRowContext(ctx, query, key).Scan(&released); err != nil {
+		// The session may still hold the lock; discard the connection so
+		// it is not handed out again with the lock attached.
+		_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		return fmt.Errorf("postgresql: failed to release advisory lock %d: %w", key, err)
+	}
+	_ = a.releaseConn(conn)
+	if !released {
+		return fmt.Errorf("%w: %d", ErrLockNotHeld, key)
+	}
+	return nil
+}
+
+// TryAdvisoryLockTx takes the transaction-level advisory lock key if it is
+// free and reports whether it did. ctx must carry a transaction (see WithTx);
+// the lock is released when that transaction ends.
+func (a *PostgreSQLAdapter) TryAdvisoryLockTx(ctx context.Context, key int64) (bool, error) {
+	if a.db == nil {
+		return false, ErrNotConnected
+	}
+	if a.txFromContext(ctx) == nil {
+		return false, fmt.Errorf("postgresql: transaction-level advisory lock requires a transaction in the context")
+	}
+
+	var acquired bool
+	if err := a.queryRowContext(ctx, "try_advisory_lock_tx", "SELECT pg_try_advisory_xact_lock($1)", key).Scan(&acquired); err != nil {
+		return false, fmt.Errorf("postgresql: failed to try advisory lock %d: %w", key, err)
+	}
+	return acquired, nil
+}
+
+// AdvisoryLockTx takes the transaction-level advisory lock key, waiting until
+// it is free or ctx is done. ctx must carry a transaction (see WithTx); the
+// lock is released when that transaction ends.
+func (a *PostgreSQLAdapter) AdvisoryLockTx(ctx context.Context, key int64) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+	if a.txFromContext(ctx) == nil {
+		return fmt.Errorf("postgresql: transaction-level advisory lock requires a transaction in the context")
+	}
+
+	if _, err := a.execContext(ctx, "advisory_lock_tx", "SELECT pg_advisory_xact_lock($1)", key); err != nil {
+		return fmt.Errorf("postgresql: failed to take advisory lock %d: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAdvisoryLocks_PushPop(t *testing.T) {
+	var l advisoryLocks
+	c1, c2 := &sql.Conn{}, &sql.Conn{}
+
+	l.push(7, c1)
+	l.push(7, c2)
+	if conn := l.pop(7); conn != c2 {
+		t.Error("expected the most recent connection first")
+	}
+	if conn := l.pop(7); conn != c1 {
+		t.Error("expected the first connection second")
+	}
+	if conn := l.pop(7); conn != nil {
+		t.Error("expected no connection once all are popped")
+	}
+	if _, ok := l.conns[7]; ok {
+		t.Error("expected the key to be removed once empty")
+	}
+}
+
+func TestPostgreSQLAdapter_AdvisoryLockWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	ctx := context.Background()
+
+	if _, err := a.TryAdvisoryLock(ctx, 1); err == nil {
+		t.Error("TryAdvisoryLock: expected error when not connected, got nil")
+	}
+	if err := a.AdvisoryLock(ctx, 1); err == nil {
+		t.Error("AdvisoryLock: expected error when not connected, got nil")
+	}
+	if err := a.AdvisoryUnlock(ctx, 1); err == nil {
+		t.Error("AdvisoryUnlock: expected error when not connected, got nil")
+	}
+	if _, err := a.TryAdvisoryLockTx(ctx, 1); err == nil {
+		t.Error("TryAdvisoryLockTx: expected error when not connected, got nil")
+	}
+	if err := a.AdvisoryLockTx(ctx, 1); err == nil {
+		t.Error("AdvisoryLockTx: expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_AdvisoryLockRace(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	b := newIntegrationAdapter(t)
+	ctx := context.Background()
+	const key = 424242
+
+	if err := a.AdvisoryUnlock(ctx, key); !errors.Is(err, ErrLockNotHeld) {
+		t.Errorf("expected ErrLockNotHeld, got %v", err)
+	}
+
+	ok, err := a.TryAdvisoryLock(ctx, key)
+	if err != nil || !ok {
+		t.Fatalf("first adapter should take the lock: ok=%v err=%v", ok, err)
+	}
+	if ok, err := b.TryAdvisoryLock(ctx, key); err != nil || ok {
+		t.Fatalf("second adapter should not take a held lock: ok=%v err=%v", ok, err)
+	}
+
+	acquired := make(chan error, 1)
+	go func() { acquired <- b.AdvisoryLock(ctx, key) }()
+
+	select {
+	case err := <-acquired:
+		t.Fatalf("AdvisoryLock returned while the lock was held: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	if err := a.AdvisoryUnlock(ctx, key); err != nil {
+		t.Fatalf("unlock failed: %v", err)
+	}
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatalf("AdvisoryLock failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("AdvisoryLock did not return after the lock was released")
+	}
+	if err := b.AdvisoryUnlock(ctx, key); err != nil {
+		t.Fatalf("unlock failed: %v", err)
+	}
+}
+
+func TestPostgreSQLAdapter_AdvisoryLockTx(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	b := newIntegrationAdapter(t)
+	ctx := context.Background()
+	const key = 434343
+
+	if err := a.AdvisoryLockTx(ctx, key); err == nil {
+		t.Error("expected error without a transaction, got nil")
+	}
+
+	tx, err := a.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin: %v", err)
+	}
+	if err := a.AdvisoryLockTx(WithTx(ctx, tx), key); err != nil {
+		t.Fatalf("lock failed: %v", err)
+	}
+	if ok, err := b.TryAdvisoryLock(ctx, key); err != nil || ok {
+		t.Fatalf("lock should be held by the transaction: ok=%v err=%v", ok, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+	ok, err := b.TryAdvisoryLock(ctx, key)
+	if err != nil || !ok {
+		t.Fatalf("lock should be free after commit: ok=%v err=%v", ok, err)
+	}
+	_ = b.AdvisoryUnlock(ctx, key)
+}
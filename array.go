@@ -0,0 +1,67 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// ArrayScanner is an sql.Scanner that decodes a one-dimensional PostgreSQL
+// array into a Go slice chosen by the array's type name, as reported by
+// sql.ColumnType.DatabaseTypeName:
+//
+//	_INT2, _INT4, _INT8      []int64
+//	_FLOAT4, _FLOAT8         []float64
+//	_BOOL                    []bool
+//	anything else            []string
+//
+// A NULL array scans to a nil Value. Arrays containing NULL elements cannot
+// be represented by these slice types and fail to scan.
+type ArrayScanner struct {
+	// TypeName is the database type name of the array, such as "_INT4".
+	TypeName string
+	// Value holds the decoded slice after Scan.
+	Value interface{}
+}
+
+// Scan implements sql.Scanner.
+func (s *ArrayScanner) Scan(src interface{}) error {
+	if src == nil {
+		s.Value = nil
+		return nil
+	}
+
+	var err error
+	switch strings.ToUpper(s.TypeName) {
+	case "_INT2", "_INT4", "_INT8":
+		var v pq.Int64Array
+		err = v.Scan(src)
+		s.Value = []int64(v)
+	case "_FLOAT4", "_FLOAT8":
+		var v pq.Float64Array
+		err = v.Scan(src)
+		s.Value = []float64(v)
+	case "_BOOL":
+		var v pq.BoolArray
+		err = v.Scan(src)
+		s.Value = []bool(v)
+	default:
+		var v pq.StringArray
+		err = v.Scan(src)
+		s.Value = []string(v)
+	}
+
+	if err != nil {
+		s.Value = nil
+		return fmt.Errorf("postgresql: cannot scan %s array: %w", s.TypeName, err)
+	}
+	return nil
+}
+
+// isArrayType reports whether a database type name denotes an array.
+// PostgreSQL names array types after their element type with a leading
+// underscore.
+func isArrayType(typeName string) bool {
+	return strings.HasPrefix(typeName, "_")
+}
@@ -0,0 +1,71 @@
+package postgresql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestArrayScanner_Scan(t *testing.T) {
+	tests := []struct {
+		typeName string
+		src      interface{}
+		expected interface{}
+	}{
+		{"_INT4", []byte("{1,2,3}"), []int64{1, 2, 3}},
+		{"_INT8", "{-7,42}", []int64{-7, 42}},
+		{"_FLOAT8", []byte("{1.5,2}"), []float64{1.5, 2}},
+		{"_BOOL", []byte("{t,f}"), []bool{true, false}},
+		{"_TEXT", []byte(`{a,"b c","d\"e"}`), []string{"a", "b c", `d"e`}},
+		{"_UUID", []byte("{}"), []string{}},
+		{"_INT4", nil, nil},
+	}
+
+	for _, tt := range tests {
+		s := &ArrayScanner{TypeName: tt.typeName}
+		if err := s.Scan(tt.src); err != nil {
+			t.Errorf("%s %v: unexpected error: %v", tt.typeName, tt.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(s.Value, tt.expected) {
+			t.Errorf("%s %v: expected %#v, got %#v", tt.typeName, tt.src, tt.expected, s.Value)
+		}
+	}
+}
+
+func TestArrayScanner_ScanInvalid(t *testing.T) {
+	for _, src := range []interface{}{[]byte("{1,NULL}"), []byte("{{1,2},{3,4}}"), 42} {
+		s := &ArrayScanner{TypeName: "_INT4"}
+		if err := s.Scan(src); err == nil {
+			t.Errorf("expected error for %v, got nil", src)
+		}
+	}
+}
+
+func TestIsArrayType(t *testing.T) {
+	for name, expected := range map[string]bool{"_INT4": true, "_TEXT": true, "INT4": false, "": false} {
+		if result := isArrayType(name); result != expected {
+			t.Errorf("isArrayType(%q): expected %v, got %v", name, expected, result)
+		}
+	}
+}
+
+func TestPostgreSQLAdapter_AutoArrayScan(t *testing.T) {
+	a := newIntegrationAdapter(t, WithAutoArrayScan(true))
+	op := &adapter.Operation{Statement: "SELECT ARRAY[1,2,3]::int[] AS ids, ARRAY['x','y'] AS tags"}
+
+	results, err := a.Fetch(context.Background(), op, nil)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+
+	row := results[0].(map[string]interface{})
+	if !reflect.DeepEqual(row["ids"], []int64{1, 2, 3}) {
+		t.Errorf("expected []int64{1, 2, 3}, got %#v", row["ids"])
+	}
+	if !reflect.DeepEqual(row["tags"], []string{"x", "y"}) {
+		t.Errorf("expected []string{\"x\", \"y\"}, got %#v", row["tags"])
+	}
+}
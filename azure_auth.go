@@ -0,0 +1,159 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/confidential"
+)
+
+// azureADScope is the token scope of Azure Database for PostgreSQL.
+const azureADScope = "https://ossrdbms-aad.database.windows.net/.default"
+
+// azureTokenRefreshMargin is how long before expiry a token is replaced.
+const azureTokenRefreshMargin = 5 * time.Minute
+
+// azureTokenRetryDelay is the wait before retrying a failed refresh.
+const azureTokenRetryDelay = 30 * time.Second
+
+// WithAzureADAuth authenticates to Azure Database for PostgreSQL with a
+// Microsoft Entra ID (Azure AD) access token, acquired for the application
+// clientID in tenantID with its client secret. The configured user must be
+// the name of the Entra ID role for the application; the password is
+// replaced by the token.
+//
+// Once connected, a background goroutine renews the token five minutes
+// before it expires. Tokens are only checked when a connection is opened,
+// so the pool does not need to be reopened: connections already open keep
+// working and new ones use the renewed token. Azure requires TLS, so set
+// sslmode to require or stricter.
+func WithAzureADAuth(tenantID, clientID, clientSecret string) Option {
+	return func(a *PostgreSQLAdapter) {
+		source := &azureTokenSource{acquire: func(ctx context.Context) (string, time.Time, error) {
+			return acquireAzureADToken(ctx, tenantID, clientID, clientSecret)
+		}}
+		a.azureToken = source
+		a.iamUser = ""
+		a.iamTokenProvider = source.token
+	}
+}
+
+// acquireAzureADToken runs the client credentials flow for clientID.
+func acquireAzureADToken(ctx context.Context, tenantID, clientID, clientSecret string) (string, time.Time, error) {
+	cred, err := confidential.NewCredFromSecret(clientSecret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	client, err := confidential.New("https://login.microsoftonline.com/"+tenantID, clientID, cred)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	result, err := client.AcquireTokenByCredential(ctx, []string{azureADScope})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return result.AccessToken, result.ExpiresOn, nil
+}
+
+// azureTokenSource caches an Azure AD token and renews it before expiry.
+type azureTokenSource struct {
+	acquire func(ctx context.Context) (string, time.Time, error)
+
+	mu      sync.Mutex
+	current string
+	expires time.Time
+	cancel  context.CancelFunc
+}
+
+// token returns a token valid for at least azureTokenRefreshMargin,
+// acquiring one if the cached token is missing or about to expire.
+func (s *azureTokenSource) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current != "" && time.Until(s.expires) > azureTokenRefreshMargin {
+		return s.current, nil
+	}
+	if err := s.refreshLocked(ctx); err != nil {
+		return "", err
+	}
+	return s.current, nil
+}
+
+// refreshLocked acquires a new token. s.mu must be held.
+func (s *azureTokenSource) refreshLocked(ctx context.Context) error {
+	token, expires, err := s.acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("postgresql: failed to acquire Azure AD token: %w", err)
+	}
+	s.current, s.expires = token, expires
+	return nil
+}
+
+// start launches the background renewal, replacing any running one.
+func (s *azureTokenSource) start() {
+	s.stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.mu.Lock()
+	s.cancel = cancel
+	s.mu.Unlock()
+	go s.run(ctx)
+}
+
+// stop ends the background renewal if it is running.
+func (s *azureTokenSource) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+// run renews the token azureTokenRefreshMargin before it expires until ctx
+// is done, retrying failed renewals every azureTokenRetryDelay.
+func (s *azureTokenSource) run(ctx context.Context) {
+	for {
+		s.mu.Lock()
+		wait := time.Until(s.expires) - azureTokenRefreshMargin
+		s.mu.Unlock()
+
+		timer := time.NewTimer(max(wait, 0))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.mu.Lock()
+		err := s.refreshLocked(ctx)
+		s.mu.Unlock()
+		if err != nil && ctx.Err() == nil {
+			slog.Warn("postgresql: Azure AD token renewal failed", "error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(azureTokenRetryDelay):
+			}
+		}
+	}
+}
+
+// startTokenRefresh starts renewing the WithAzureADAuth token, if enabled.
+func (a *PostgreSQLAdapter) startTokenRefresh() {
+	if a.azureToken != nil && a.db != nil {
+		a.azureToken.start()
+	}
+}
+
+// stopTokenRefresh stops renewing the WithAzureADAuth token.
+func (a *PostgreSQLAdapter) stopTokenRefresh() {
+	if a.azureToken != nil {
+		a.azureToken.stop()
+	}
+}
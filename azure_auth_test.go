@@ -0,0 +1,97 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAzureTokenSource_Token(t *testing.T) {
+	var calls atomic.Int32
+	expires := time.Now().Add(time.Hour)
+	source := &azureTokenSource{acquire: func(ctx context.Context) (string, time.Time, error) {
+		n := calls.Add(1)
+		return fmt.Sprintf("token-%d", n), expires, nil
+	}}
+
+	for i := 0; i < 3; i++ {
+		token, err := source.token(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if token != "token-1" {
+			t.Errorf("expected cached token-1, got %q", token)
+		}
+	}
+
+	// A token about to expire is replaced on the next call.
+	expires = time.Now().Add(time.Minute)
+	source.expires = expires
+	if token, _ := source.token(context.Background()); token != "token-2" {
+		t.Errorf("expected renewed token-2, got %q", token)
+	}
+}
+
+func TestAzureTokenSource_TokenError(t *testing.T) {
+	source := &azureTokenSource{acquire: func(ctx context.Context) (string, time.Time, error) {
+		return "", time.Time{}, errors.New("invalid_client")
+	}}
+
+	if _, err := source.token(context.Background()); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestAzureTokenSource_BackgroundRefresh(t *testing.T) {
+	var calls atomic.Int32
+	source := &azureTokenSource{acquire: func(ctx context.Context) (string, time.Time, error) {
+		n := calls.Add(1)
+		// Expire inside the refresh margin so the loop renews at once.
+		return fmt.Sprintf("token-%d", n), time.Now().Add(azureTokenRefreshMargin + 50*time.Millisecond), nil
+	}}
+	if _, err := source.token(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	source.start()
+	time.Sleep(200 * time.Millisecond)
+	source.stop()
+
+	renewed := calls.Load()
+	if renewed < 2 {
+		t.Errorf("expected background renewal, got %d acquisitions", renewed)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if calls.Load() != renewed {
+		t.Error("expected renewal to stop after stop()")
+	}
+}
+
+func TestWithAzureADAuth(t *testing.T) {
+	adapter := NewPostgreSQLAdapter(
+		WithIAMAuth("eu-west-1", "iam_user", nil),
+		WithAzureADAuth("tenant", "client", "secret"),
+	)
+
+	if adapter.azureToken == nil || adapter.iamTokenProvider == nil {
+		t.Fatal("expected Azure AD token provider to be set")
+	}
+	if adapter.iamUser != "" {
+		t.Errorf("expected configured user to be kept, got iamUser %q", adapter.iamUser)
+	}
+
+	adapter.azureToken.acquire = func(ctx context.Context) (string, time.Time, error) {
+		return "aad-token", time.Now().Add(time.Hour), nil
+	}
+	adapter.dsn = "host=db user=app@tenant dbname=app sslmode=require"
+	dsn, err := adapter.connectDSN(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "host=db user=app@tenant dbname=app sslmode=require password='aad-token'"; dsn != expected {
+		t.Errorf("expected %q, got %q", expected, dsn)
+	}
+}
@@ -0,0 +1,177 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// BackgroundJob is a query running in a separate backend launched through
+// the pg_background extension. The launching session owns the worker, so the
+// job keeps its connection reserved until Result or Detach is called.
+type BackgroundJob struct {
+	// PID is the process id of the background worker.
+	PID int
+
+	adapter   *PostgreSQLAdapter
+	conn      *sql.Conn
+	returning bool
+}
+
+// positionalParam matches $1, $2, ... placeholders.
+var positionalParam = regexp.MustCompile(`\$(\d+)`)
+
+// BackgroundExec launches query in a background worker with
+// pg_background_launch and returns immediately. pg_background only accepts
+// plain SQL text, so args (bound to $1, $2, ...) are quoted as literals by
+// the server before the query is launched.
+//
+// Queries that return rows (SELECT, WITH, VALUES, TABLE) are wrapped so
+// Result can return each row as a map; for other statements Result returns
+// a single row holding the command tag under "result".
+func (a *PostgreSQLAdapter) BackgroundExec(ctx context.Context, query string, args []interface{}) (*BackgroundJob, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	conn, err := a.conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to acquire connection: %w", err)
+	}
+
+	sqlText, err := inlineArgs(ctx, conn, query, args)
+	if err != nil {
+		_ = a.releaseConn(conn)
+		return nil, err
+	}
+
+	returning := returnsRows(sqlText)
+	if returning {
+		sqlText = fmt.Sprintf("SELECT row_to_json(_bg)::text FROM (%s) AS _bg", sqlText)
+	}
+
+	job := &BackgroundJob{adapter: a, conn: conn, returning: returning}
+	if err := conn.QueryRowContext(ctx, "SELECT pg_background_launch($1)", sqlText).Scan(&job.PID); err != nil {
+		_ = a.releaseConn(conn)
+		return nil, fmt.Errorf("postgresql: failed to launch background query: %w", err)
+	}
+
+	return job, nil
+}
+
+// Result waits for the background query to finish and returns its rows. The
+// job's connection is released afterwards, so Result can only be called once.
+func (j *BackgroundJob) Result(ctx context.Context) ([]map[string]interface{}, error) {
+	if j.conn == nil {
+		return nil, fmt.Errorf("postgresql: background job %d already finished", j.PID)
+	}
+	defer j.release()
+
+	rows, err := j.conn.QueryContext(ctx, "SELECT * FROM pg_background_result($1) AS (result text)", j.PID)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to read background result: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []map[string]interface{}
+	for rows.Next() {
+		var text string
+		if err := rows.Scan(&text); err != nil {
+			return nil, fmt.Errorf("postgresql: scan failed: %w", err)
+		}
+
+		if !j.returning {
+			results = append(results, map[string]interface{}{"result": text})
+			continue
+		}
+
+		row := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(text), &row); err != nil {
+			return nil, fmt.Errorf("postgresql: failed to decode background row: %w", err)
+		}
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgresql: rows iteration failed: %w", err)
+	}
+
+	return results, nil
+}
+
+// Detach lets the background query run to completion without collecting its
+// result and releases the job's connection.
+func (j *BackgroundJob) Detach() error {
+	if j.conn == nil {
+		return nil
+	}
+	defer j.release()
+
+	// Detaching must not be cut short by a caller's deadline, or the worker
+	// would be left attached to a connection returned to the pool.
+	if _, err := j.conn.ExecContext(context.Background(), "SELECT pg_background_detach($1)", j.PID); err != nil {
+		return fmt.Errorf("postgresql: failed to detach background job %d: %w", j.PID, err)
+	}
+	return nil
+}
+
+// release returns the job's connection to the pool.
+func (j *BackgroundJob) release() {
+	_ = j.adapter.releaseConn(j.conn)
+	j.conn = nil
+}
+
+// inlineArgs replaces each $N placeholder in query with args[N-1] quoted as
+// a literal by the server's quote_nullable.
+func inlineArgs(ctx context.Context, conn *sql.Conn, query string, args []interface{}) (string, error) {
+	if len(args) == 0 {
+		return query, nil
+	}
+
+	exprs := make([]string, len(args))
+	for i := range args {
+		exprs[i] = fmt.Sprintf("quote_nullable($%d::text)", i+1)
+	}
+
+	literals := make([]string, len(args))
+	dest := make([]interface{}, len(args))
+	for i := range literals {
+		dest[i] = &literals[i]
+	}
+
+	if err := conn.QueryRowContext(ctx, "SELECT "+strings.Join(exprs, ", "), args...).Scan(dest...); err != nil {
+		return "", fmt.Errorf("postgresql: failed to quote background query arguments: %w", err)
+	}
+
+	var missing string
+	result := positionalParam.ReplaceAllStringFunc(query, func(m string) string {
+		var n int
+		_, _ = fmt.Sscanf(m, "$%d", &n)
+		if n < 1 || n > len(literals) {
+			missing = m
+			return m
+		}
+		return literals[n-1]
+	})
+	if missing != "" {
+		return "", fmt.Errorf("postgresql: no argument for placeholder %s", missing)
+	}
+
+	return result, nil
+}
+
+// returnsRows reports whether query is a statement that produces a result set.
+func returnsRows(query string) bool {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return false
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "SELECT", "WITH", "VALUES", "TABLE":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+)
+
+func TestReturnsRows(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected bool
+	}{
+		{"SELECT count(*) FROM events", true},
+		{"  with t AS (SELECT 1) SELECT * FROM t", true},
+		{"VALUES (1), (2)", true},
+		{"TABLE events", true},
+		{"UPDATE events SET processed = true", false},
+		{"VACUUM ANALYZE events", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if result := returnsRows(tt.query); result != tt.expected {
+			t.Errorf("returnsRows(%q): expected %v, got %v", tt.query, tt.expected, result)
+		}
+	}
+}
+
+func TestPostgreSQLAdapter_BackgroundExecWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if _, err := a.BackgroundExec(context.Background(), "SELECT 1", nil); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestBackgroundJob_ResultAfterRelease(t *testing.T) {
+	job := &BackgroundJob{PID: 42}
+	if _, err := job.Result(context.Background()); err == nil {
+		t.Error("expected error reading a finished job, got nil")
+	}
+	if err := job.Detach(); err != nil {
+		t.Errorf("expected detaching a finished job to be a no-op, got %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// BatchExecute runs several actions as a unit and returns the result of each,
+// in order, as Execute would. params[i] supplies the parameters of
+// actions[i]. The actions run inside a single transaction, so either all of
+// them take effect or none do. When ctx carries a transaction (see WithTx)
+// the actions run inside it instead.
+//
+// With the pgx driver all statements are sent to the server in one round
+// trip using a pgx.Batch. With lib/pq they are executed one after another.
+func (a *PostgreSQLAdapter) BatchExecute(ctx context.Context, actions []*adapter.Action, params []map[string]interface{}) ([]interface{}, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	if len(actions) != len(params) {
+		return nil, fmt.Errorf("postgresql: batch has %d actions but %d parameter sets", len(actions), len(params))
+	}
+
+	if len(actions) == 0 {
+		return nil, nil
+	}
+
+	queries := make([]string, len(actions))
+	args := make([][]interface{}, len(actions))
+	for i, action := range actions {
+		actionArgs, err := extractArgs(action.Statement, params[i])
+		if err != nil {
+			return nil, fmt.Errorf("postgresql: batch action %d: %w", i, err)
+		}
+		queries[i] = a.annotate(replaceNamedParams(action.Statement), action.Name)
+		args[i] = actionArgs
+	}
+
+	if tx := a.txFromContext(ctx); tx != nil {
+		return a.batchOn(ctx, tx.tx, queries, args)
+	}
+	if a.driverName == DriverPGX {
+		return a.batchPgx(ctx, queries, args)
+	}
+	return a.batchSequential(ctx, queries, args)
+}
+
+// batchPgx sends the prepared queries in a single pgx.Batch. Without an
+// explicit transaction the server runs the whole batch as one implicit
+// transaction.
+func (a *PostgreSQLAdapter) batchPgx(ctx context.Context, queries []string, args [][]interface{}) ([]interface{}, error) {
+	conn, err := a.conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to acquire connection: %w", err)
+	}
+	defer func() { _ = a.releaseConn(conn) }()
+
+	results := make([]interface{}, len(queries))
+	err = conn.Raw(func(driverConn interface{}) error {
+		stdConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("postgresql: unexpected driver connection %T", driverConn)
+		}
+
+		batch := &pgx.Batch{}
+		for i, query := range queries {
+			batch.Queue(query, args[i]...)
+		}
+
+		br := stdConn.Conn().SendBatch(ctx, batch)
+		for i := range queries {
+			rows, err := br.Query()
+			if err != nil {
+				_ = br.Close()
+				return fmt.Errorf("postgresql: batch action %d failed: %w", i, err)
+			}
+
+			result, err := collectPgxRows(rows)
+			if err != nil {
+				_ = br.Close()
+				return fmt.Errorf("postgresql: batch action %d: %w", i, err)
+			}
+			results[i] = result
+		}
+
+		if err := br.Close(); err != nil {
+			return fmt.Errorf("postgresql: batch failed: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// collectPgxRows reads every row of a pgx result into a map keyed by column
+// name, like scanRows does for database/sql.
+func collectPgxRows(rows pgx.Rows) ([]interface{}, error) {
+	defer rows.Close()
+
+	fields := rows.FieldDescriptions()
+	var results []interface{}
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return nil, fmt.Errorf("postgresql: scan failed: %w", err)
+		}
+
+		result := make(map[string]interface{}, len(fields))
+		for i, field := range fields {
+			result[field.Name] = values[i]
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgresql: rows iteration failed: %w", err)
+	}
+
+	return results, nil
+}
+
+// batchSequential runs the prepared queries one after another in a transaction.
+func (a *PostgreSQLAdapter) batchSequential(ctx context.Context, queries []string, args [][]interface{}) ([]interface{}, error) {
+	tx, err := a.beginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to begin batch: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	results, err := a.batchOn(ctx, tx, queries, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("postgresql: failed to commit batch: %w", err)
+	}
+
+	return results, nil
+}
+
+// batchOn runs the prepared queries one after another on q.
+func (a *PostgreSQLAdapter) batchOn(ctx context.Context, q querier, queries []string, args [][]interface{}) ([]interface{}, error) {
+	results := make([]interface{}, len(queries))
+	for i, query := range queries {
+		rows, err := q.QueryContext(ctx, query, args[i]...)
+		if err != nil {
+			return nil, fmt.Errorf("postgresql: batch action %d failed: %w", i, err)
+		}
+
+		result, err := a.scanRows(rows)
+		_ = rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("postgresql: batch action %d: %w", i, err)
+		}
+		results[i] = result
+	}
+
+	return results, nil
+}
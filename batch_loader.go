@@ -0,0 +1,201 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// FetchByIDs returns the rows of the table named by op.Statement whose key
+// is one of identifiers, in a single SELECT ... WHERE key = ANY($1). The
+// key column is the single column of op.Identifier, or "id" when
+// op.Identifier is empty, and identifiers are given as for BulkDelete.
+// Rows come back in no particular order, and identifiers without a
+// matching row are simply absent from the result.
+func (a *PostgreSQLAdapter) FetchByIDs(ctx context.Context, op *adapter.Operation, identifiers []interface{}) ([]interface{}, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	key, err := singleKey(op, "fetch by ids")
+	if err != nil {
+		return nil, err
+	}
+	if len(identifiers) == 0 {
+		return []interface{}{}, nil
+	}
+	ids, err := keyValues(identifiers, key)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := a.queryContext(ctx, operationName(op, "fetch_by_ids"), fetchByIDsQuery(op.Statement, key.DataField), pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: query failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return a.scanRows(rows)
+}
+
+// fetchByIDsQuery renders the SELECT used by FetchByIDs.
+func fetchByIDsQuery(table, column string) string {
+	return fmt.Sprintf("SELECT * FROM %s WHERE %s = ANY($1)", QuoteIdentifier(table), column)
+}
+
+// LoadResult is what a BatchLoader delivers for one Load: the row with the
+// requested key, or an error. A key without a matching row yields
+// adapter.ErrNotFound.
+type LoadResult struct {
+	Row map[string]interface{}
+	Err error
+}
+
+// BatchLoader coalesces individual lookups by key into batched FetchByIDs
+// calls, the DataLoader pattern, so that resolving N objects costs one
+// query rather than N. Keys passed to Load are collected until wait has
+// passed since the first of them or maxBatch keys are pending, whichever
+// comes first, then fetched together. A BatchLoader is safe for
+// concurrent use.
+type BatchLoader struct {
+	ctx      context.Context
+	adapter  *PostgreSQLAdapter
+	op       *adapter.Operation
+	maxBatch int
+	wait     time.Duration
+
+	mu      sync.Mutex
+	pending []loadRequest
+	timer   *time.Timer
+}
+
+// loadRequest is a key waiting in a BatchLoader batch.
+type loadRequest struct {
+	id     interface{}
+	result chan LoadResult
+}
+
+// NewBatchLoader returns a BatchLoader fetching from the table named by
+// op.Statement, keyed as for FetchByIDs. Every batch runs with ctx. A
+// maxBatch of zero or less leaves batches unbounded, so they are sent only
+// once wait has passed.
+func NewBatchLoader(ctx context.Context, adapter *PostgreSQLAdapter, op *adapter.Operation, maxBatch int, wait time.Duration) *BatchLoader {
+	return &BatchLoader{
+		ctx:      ctx,
+		adapter:  adapter,
+		op:       op,
+		maxBatch: maxBatch,
+		wait:     wait,
+	}
+}
+
+// Load queues the key value id for the next batch and returns a channel
+// that receives its result once the batch has been fetched. The channel is
+// buffered, so callers that give up on a result do not hold up the batch.
+func (l *BatchLoader) Load(id interface{}) <-chan LoadResult {
+	req := loadRequest{id: id, result: make(chan LoadResult, 1)}
+
+	l.mu.Lock()
+	l.pending = append(l.pending, req)
+	var batch []loadRequest
+	switch {
+	case l.maxBatch > 0 && len(l.pending) >= l.maxBatch:
+		batch = l.takeBatch()
+	case len(l.pending) == 1:
+		l.timer = time.AfterFunc(l.wait, l.flush)
+	}
+	l.mu.Unlock()
+
+	if batch != nil {
+		go l.dispatch(batch)
+	}
+	return req.result
+}
+
+// flush fetches whatever keys are pending when the wait timer fires.
+func (l *BatchLoader) flush() {
+	l.mu.Lock()
+	batch := l.takeBatch()
+	l.mu.Unlock()
+
+	if len(batch) > 0 {
+		l.dispatch(batch)
+	}
+}
+
+// takeBatch removes and returns the pending keys and stops the wait timer.
+// l.mu must be held.
+func (l *BatchLoader) takeBatch() []loadRequest {
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	batch := l.pending
+	l.pending = nil
+	return batch
+}
+
+// dispatch fetches the keys of batch and delivers each its row, or the
+// error that failed the whole batch.
+func (l *BatchLoader) dispatch(batch []loadRequest) {
+	rows, err := l.fetch(batch)
+	for _, req := range batch {
+		switch row, ok := rows[loadKey(req.id)]; {
+		case err != nil:
+			req.result <- LoadResult{Err: err}
+		case !ok:
+			req.result <- LoadResult{Err: adapter.ErrNotFound}
+		default:
+			req.result <- LoadResult{Row: row}
+		}
+	}
+}
+
+// fetch runs the FetchByIDs for batch, asking for each distinct key once,
+// and returns the rows by key. Keys are matched to rows by loadKey, since
+// the driver may return a key as a different Go type than the caller
+// passed in.
+func (l *BatchLoader) fetch(batch []loadRequest) (map[string]map[string]interface{}, error) {
+	key, err := singleKey(l.op, "batch loader")
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []interface{}
+	seen := map[string]bool{}
+	for _, req := range batch {
+		if s := loadKey(req.id); !seen[s] {
+			seen[s] = true
+			ids = append(ids, req.id)
+		}
+	}
+
+	results, err := l.adapter.FetchByIDs(l.ctx, l.op, ids)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := rowMaps(results)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]map[string]interface{}, len(rows))
+	for _, row := range rows {
+		byID[loadKey(row[key.DataField])] = row
+	}
+	return byID, nil
+}
+
+// loadKey is the form in which BatchLoader matches a key to its row: the
+// printed value, except that []byte, as lib/pq returns uuid and numeric
+// columns, is taken as the text it holds.
+func loadKey(v interface{}) string {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,135 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestFetchByIDsQuery(t *testing.T) {
+	expected := `SELECT * FROM "public"."users" WHERE id = ANY($1)`
+	if result := fetchByIDsQuery("public.users", "id"); result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestPostgreSQLAdapter_FetchByIDsWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	op := &adapter.Operation{Statement: "users"}
+	if _, err := a.FetchByIDs(context.Background(), op, []interface{}{1}); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestBatchLoader_WithoutConnect(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxBatch int
+		wait     time.Duration
+	}{
+		{"max batch reached", 2, time.Hour},
+		{"wait elapsed", 0, time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewBatchLoader(context.Background(), NewPostgreSQLAdapter(), &adapter.Operation{Statement: "users"}, tt.maxBatch, tt.wait)
+			results := []<-chan LoadResult{l.Load(1), l.Load(2)}
+			for i, ch := range results {
+				select {
+				case r := <-ch:
+					if !errors.Is(r.Err, ErrNotConnected) {
+						t.Errorf("load %d: expected ErrNotConnected, got %v", i, r.Err)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatalf("load %d: no result delivered", i)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"int", 42, "42"},
+		{"string", "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"},
+		{"bytes", []byte("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"), "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"},
+		{"numeric bytes", []byte("12.50"), "12.50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := loadKey(tt.value); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_BatchLoader(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, `CREATE TABLE batch_loader_test (id int PRIMARY KEY, name text);
+		INSERT INTO batch_loader_test SELECT i, 'user' || i FROM generate_series(1, 5) AS i`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE batch_loader_test") })
+
+	op := &adapter.Operation{Statement: "batch_loader_test"}
+	rows, err := a.FetchByIDs(ctx, op, []interface{}{1, map[string]interface{}{"id": 3}, 42})
+	if err != nil {
+		t.Fatalf("fetch by ids failed: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Errorf("expected 2 rows, got %d", len(rows))
+	}
+
+	l := NewBatchLoader(ctx, a, op, 0, 10*time.Millisecond)
+	one, again, missing := l.Load(1), l.Load(1), l.Load(42)
+	for _, ch := range []<-chan LoadResult{one, again} {
+		r := <-ch
+		if r.Err != nil {
+			t.Fatalf("load failed: %v", r.Err)
+		}
+		if r.Row["name"] != "user1" {
+			t.Errorf("expected user1, got %v", r.Row["name"])
+		}
+	}
+	if r := <-missing; !errors.Is(r.Err, adapter.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing key, got %v", r.Err)
+	}
+}
+
+func TestPostgreSQLAdapter_BatchLoaderUUIDKey(t *testing.T) {
+	const id = "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+
+	for _, driverName := range []string{DriverPQ, DriverPGX} {
+		t.Run(driverName, func(t *testing.T) {
+			a := newIntegrationAdapter(t, WithDriver(driverName))
+			ctx := context.Background()
+
+			if _, err := a.db.ExecContext(ctx, `CREATE TABLE batch_loader_uuid_test (id uuid PRIMARY KEY, name text);
+				INSERT INTO batch_loader_uuid_test VALUES ('`+id+`', 'alice')`); err != nil {
+				t.Fatalf("failed to create table: %v", err)
+			}
+			t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE batch_loader_uuid_test") })
+
+			l := NewBatchLoader(ctx, a, &adapter.Operation{Statement: "batch_loader_uuid_test"}, 0, 10*time.Millisecond)
+			r := <-l.Load(id)
+			if r.Err != nil {
+				t.Fatalf("load failed: %v", r.Err)
+			}
+			if r.Row["name"] != "alice" {
+				t.Errorf("expected alice, got %v", r.Row["name"])
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package postgresql
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestPostgreSQLAdapter_BatchExecuteWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	actions := []*adapter.Action{{Statement: "SELECT 1"}}
+	if _, err := a.BatchExecute(context.Background(), actions, []map[string]interface{}{nil}); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_BatchExecute(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		// lib/pq runs the actions one after another in a transaction.
+		{"sequential", nil},
+		{"pgx batch", []Option{WithDriver(DriverPGX)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newIntegrationAdapter(t, tt.opts...)
+			ctx := context.Background()
+
+			if _, err := a.db.ExecContext(ctx, "CREATE TABLE batch_test (id bigint PRIMARY KEY, name text)"); err != nil {
+				t.Fatalf("failed to create table: %v", err)
+			}
+			t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE batch_test") })
+
+			insert := &adapter.Action{Statement: "INSERT INTO batch_test (id, name) VALUES ({id}, {name}) RETURNING id"}
+			count := &adapter.Action{Statement: "SELECT count(*) AS n FROM batch_test"}
+
+			results, err := a.BatchExecute(ctx,
+				[]*adapter.Action{insert, insert, count},
+				[]map[string]interface{}{{"id": 2, "name": "two"}, {"id": 1, "name": "one"}, nil})
+			if err != nil {
+				t.Fatalf("batch failed: %v", err)
+			}
+			expected := []interface{}{
+				[]interface{}{map[string]interface{}{"id": int64(2)}},
+				[]interface{}{map[string]interface{}{"id": int64(1)}},
+				[]interface{}{map[string]interface{}{"n": int64(2)}},
+			}
+			if !reflect.DeepEqual(results, expected) {
+				t.Errorf("expected results in action order %v, got %v", expected, results)
+			}
+
+			// The duplicate key fails the second action, which is named in the
+			// error, and the whole batch is rolled back.
+			_, err = a.BatchExecute(ctx,
+				[]*adapter.Action{insert, insert, insert},
+				[]map[string]interface{}{{"id": 3, "name": "three"}, {"id": 1, "name": "again"}, {"id": 4, "name": "four"}})
+			if err == nil || !strings.Contains(err.Error(), "batch action 1") {
+				t.Errorf("expected the error to name batch action 1, got %v", err)
+			}
+
+			var n int
+			if err := a.db.QueryRowContext(ctx, "SELECT count(*) FROM batch_test").Scan(&n); err != nil {
+				t.Fatalf("count failed: %v", err)
+			}
+			if n != 2 {
+				t.Errorf("expected the failed batch to add no rows, got %d rows", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// BulkDelete deletes the rows of the table named by op.Statement whose key
+// is one of identifiers, in a single DELETE ... WHERE key = ANY($1), and
+// returns the number of rows deleted. The key column is the single column
+// of op.Identifier, or "id" when op.Identifier is empty.
+//
+// An identifier is either the key value itself or an object map holding it
+// under the identifier's ObjectField. Unlike Delete, identifiers without a
+// matching row are not an error; compare the count with len(identifiers)
+// when they must all exist.
+func (a *PostgreSQLAdapter) BulkDelete(ctx context.Context, op *adapter.Operation, identifiers []interface{}) (int64, error) {
+	if a.db == nil {
+		return 0, ErrNotConnected
+	}
+
+	key, err := singleKey(op, "bulk delete")
+	if err != nil {
+		return 0, err
+	}
+	if len(identifiers) == 0 {
+		return 0, nil
+	}
+	ids, err := keyValues(identifiers, key)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := a.execContext(ctx, operationName(op, "bulk_delete"), bulkDeleteQuery(op.Statement, key.DataField), pq.Array(ids))
+	if err != nil {
+		return 0, fmt.Errorf("postgresql: bulk delete failed: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("postgresql: failed to get rows affected: %w", err)
+	}
+	return rowsAffected, nil
+}
+
+// bulkDeleteQuery renders the DELETE used by BulkDelete.
+func bulkDeleteQuery(table, column string) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE %s = ANY($1)", QuoteIdentifier(table), column)
+}
@@ -0,0 +1,59 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestBulkDeleteQuery(t *testing.T) {
+	expected := `DELETE FROM "public"."users" WHERE id = ANY($1)`
+	if result := bulkDeleteQuery("public.users", "id"); result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestPostgreSQLAdapter_BulkDeleteWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	op := &adapter.Operation{Statement: "users"}
+	if _, err := a.BulkDelete(context.Background(), op, []interface{}{1}); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_BulkDelete(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, `CREATE TABLE bulk_delete_test (id int PRIMARY KEY);
+		INSERT INTO bulk_delete_test SELECT generate_series(1, 5)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE bulk_delete_test") })
+
+	op := &adapter.Operation{
+		Statement:  "bulk_delete_test",
+		Identifier: []adapter.PropertyMapping{{ObjectField: "ID", DataField: "id"}},
+	}
+	deleted, err := a.BulkDelete(ctx, op, []interface{}{1, map[string]interface{}{"ID": 3}, 42})
+	if err != nil {
+		t.Fatalf("bulk delete failed: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("expected 2 rows deleted, got %d", deleted)
+	}
+
+	var remaining int
+	if err := a.db.QueryRowContext(ctx, "SELECT count(*) FROM bulk_delete_test").Scan(&remaining); err != nil {
+		t.Fatalf("failed to count rows: %v", err)
+	}
+	if remaining != 3 {
+		t.Errorf("expected 3 remaining rows, got %d", remaining)
+	}
+
+	op.Identifier = append(op.Identifier, adapter.PropertyMapping{ObjectField: "Tenant", DataField: "tenant"})
+	if _, err := a.BulkDelete(ctx, op, []interface{}{1}); err == nil {
+		t.Error("expected error for a composite key, got nil")
+	}
+}
@@ -0,0 +1,164 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// BulkUpdate updates the rows of the table named by op.Statement from
+// objects in a single statement per chunk:
+//
+//	UPDATE t SET col = v.col FROM (VALUES ($1, $2), ...) AS v(pk, col) WHERE t.pk = v.pk
+//
+// Rows are matched on the op.Identifier columns and every other column in
+// op.Properties is overwritten. Bind parameters in VALUES carry no type, so
+// the column types are read from the catalog first and the first row is
+// cast to them.
+//
+// Objects are sent in chunks of at most maxBindParams values; run it inside
+// a transaction (WithTx) when the chunks must apply atomically. Like Update,
+// it returns adapter.ErrNotFound when fewer rows were updated than objects
+// given, which also happens when two objects share a key.
+func (a *PostgreSQLAdapter) BulkUpdate(ctx context.Context, op *adapter.Operation, objects []interface{}) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	if len(op.Identifier) == 0 {
+		return fmt.Errorf("postgresql: bulk update requires identifier columns")
+	}
+	if len(objects) == 0 {
+		return nil
+	}
+
+	props := append([]adapter.PropertyMapping{}, op.Identifier...)
+	for _, prop := range op.Properties {
+		if !hasDataField(props, prop.DataField) {
+			props = append(props, prop)
+		}
+	}
+	if len(props) == len(op.Identifier) {
+		return fmt.Errorf("postgresql: bulk update requires at least one non-identifier column")
+	}
+
+	types, err := a.columnTypes(ctx, op.Statement)
+	if err != nil {
+		return err
+	}
+	casts := make([]string, len(props))
+	for i, prop := range props {
+		dataType, ok := types[prop.DataField]
+		if !ok {
+			return fmt.Errorf("postgresql: column %s does not exist in %s", prop.DataField, op.Statement)
+		}
+		casts[i] = dataType
+	}
+
+	chunkSize := maxBindParams / len(props)
+	for start := 0; start < len(objects); start += chunkSize {
+		end := min(start+chunkSize, len(objects))
+		if err := a.bulkUpdateChunk(ctx, op, props, casts, objects[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bulkUpdateChunk runs one UPDATE ... FROM (VALUES ...) for objects.
+func (a *PostgreSQLAdapter) bulkUpdateChunk(ctx context.Context, op *adapter.Operation, props []adapter.PropertyMapping, casts []string, objects []interface{}) error {
+	values := make([]interface{}, 0, len(objects)*len(props))
+	for _, objInterface := range objects {
+		args, err := objectArgs(objInterface.(map[string]interface{}), props)
+		if err != nil {
+			return err
+		}
+		values = append(values, args...)
+	}
+
+	columns := make([]string, len(props))
+	for i, prop := range props {
+		columns[i] = prop.DataField
+	}
+	query := buildBulkUpdateQuery(op.Statement, columns, len(op.Identifier), casts, len(objects))
+
+	result, err := a.execContext(ctx, operationName(op, "bulk_update"), query, values...)
+	if err != nil {
+		return fmt.Errorf("postgresql: bulk update failed: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("postgresql: failed to get rows affected: %w", err)
+	}
+	if rowsAffected < int64(len(objects)) {
+		return adapter.ErrNotFound
+	}
+	return nil
+}
+
+// buildBulkUpdateQuery renders the UPDATE ... FROM (VALUES ...) statement for
+// rows objects. The first keys columns are the key. Only the placeholders of
+// the first row are cast: PostgreSQL resolves each VALUES column to a single
+// type, so the cast applies to every row.
+func buildBulkUpdateQuery(table string, columns []string, keys int, casts []string, rows int) string {
+	assignments := make([]string, 0, len(columns)-keys)
+	for _, col := range columns[keys:] {
+		assignments = append(assignments, fmt.Sprintf("%s = v.%s", col, col))
+	}
+	conditions := make([]string, keys)
+	for i, col := range columns[:keys] {
+		conditions[i] = fmt.Sprintf("t.%s = v.%s", col, col)
+	}
+
+	valueRows := make([]string, rows)
+	placeholders := make([]string, len(columns))
+	paramIndex := 1
+	for i := range valueRows {
+		for j := range columns {
+			placeholders[j] = fmt.Sprintf("$%d", paramIndex)
+			if i == 0 {
+				placeholders[j] += "::" + casts[j]
+			}
+			paramIndex++
+		}
+		valueRows[i] = "(" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	return fmt.Sprintf("UPDATE %s AS t SET %s FROM (VALUES %s) AS v(%s) WHERE %s",
+		QuoteIdentifier(table),
+		strings.Join(assignments, ", "),
+		strings.Join(valueRows, ", "),
+		strings.Join(columns, ", "),
+		strings.Join(conditions, " AND "))
+}
+
+// columnTypes returns the SQL type of every column of table, keyed by
+// column name.
+func (a *PostgreSQLAdapter) columnTypes(ctx context.Context, table string) (map[string]string, error) {
+	query := `SELECT attname, format_type(atttypid, atttypmod)
+		FROM pg_attribute
+		WHERE attrelid = $1::regclass AND attnum > 0 AND NOT attisdropped`
+
+	rows, err := a.queryContext(ctx, "column_types", query, QuoteIdentifier(table))
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to read columns of %s: %w", table, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	types := make(map[string]string)
+	for rows.Next() {
+		var column, dataType string
+		if err := rows.Scan(&column, &dataType); err != nil {
+			return nil, fmt.Errorf("postgresql: scan failed: %w", err)
+		}
+		types[column] = dataType
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgresql: rows iteration failed: %w", err)
+	}
+	return types, nil
+}
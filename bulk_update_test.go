@@ -0,0 +1,97 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestBuildBulkUpdateQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		columns  []string
+		keys     int
+		casts    []string
+		rows     int
+		expected string
+	}{
+		{
+			name:    "single key",
+			columns: []string{"id", "name", "email"},
+			keys:    1,
+			casts:   []string{"integer", "text", "character varying(255)"},
+			rows:    2,
+			expected: `UPDATE "users" AS t SET name = v.name, email = v.email ` +
+				"FROM (VALUES ($1::integer, $2::text, $3::character varying(255)), ($4, $5, $6)) AS v(id, name, email) " +
+				"WHERE t.id = v.id",
+		},
+		{
+			name:    "composite key",
+			columns: []string{"tenant_id", "user_id", "role"},
+			keys:    2,
+			casts:   []string{"bigint", "bigint", "text"},
+			rows:    1,
+			expected: `UPDATE "users" AS t SET role = v.role ` +
+				"FROM (VALUES ($1::bigint, $2::bigint, $3::text)) AS v(tenant_id, user_id, role) " +
+				"WHERE t.tenant_id = v.tenant_id AND t.user_id = v.user_id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := buildBulkUpdateQuery("users", tt.columns, tt.keys, tt.casts, tt.rows); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_BulkUpdateWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	op := &adapter.Operation{Statement: "users"}
+	if err := a.BulkUpdate(context.Background(), op, []interface{}{map[string]interface{}{"ID": 1}}); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_BulkUpdate(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, `CREATE TABLE bulk_update_test (id int PRIMARY KEY, name text, score numeric(5,2));
+		INSERT INTO bulk_update_test VALUES (1, 'a', 1), (2, 'b', 2), (3, 'c', 3)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE bulk_update_test") })
+
+	op := &adapter.Operation{
+		Statement: "bulk_update_test",
+		Properties: []adapter.PropertyMapping{
+			{ObjectField: "Name", DataField: "name"},
+			{ObjectField: "Score", DataField: "score"},
+		},
+		Identifier: []adapter.PropertyMapping{{ObjectField: "ID", DataField: "id"}},
+	}
+	err := a.BulkUpdate(ctx, op, []interface{}{
+		map[string]interface{}{"ID": 1, "Name": "x", "Score": 10.5},
+		map[string]interface{}{"ID": 3, "Name": "z", "Score": nil},
+	})
+	if err != nil {
+		t.Fatalf("bulk update failed: %v", err)
+	}
+
+	var names string
+	if err := a.db.QueryRowContext(ctx, "SELECT string_agg(name || ':' || coalesce(score::text, '-'), ',' ORDER BY id) FROM bulk_update_test").Scan(&names); err != nil {
+		t.Fatalf("failed to read rows: %v", err)
+	}
+	if names != "x:10.50,b:2.00,z:-" {
+		t.Errorf("unexpected rows after bulk update: %s", names)
+	}
+
+	err = a.BulkUpdate(ctx, op, []interface{}{map[string]interface{}{"ID": 42, "Name": "missing", "Score": 0}})
+	if !errors.Is(err, adapter.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing row, got %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package postgresql
+
+import (
+	"context"
+	"net"
+
+	"cloud.google.com/go/cloudsqlconn"
+)
+
+// WithCloudSQLConnector opens every connection to the Cloud SQL instance
+// named by its connection name ("project:region:instance") through dialer,
+// Google's Cloud SQL Go connector. The connector authorizes the connection
+// with IAM and encrypts it, so no authorized networks or Auth Proxy process
+// are needed:
+//
+//	dialer, err := cloudsqlconn.NewDialer(ctx, cloudsqlconn.WithIAMAuthN())
+//	if err != nil {
+//	    return err
+//	}
+//	defer dialer.Close()
+//	a := postgresql.NewPostgreSQLAdapter(
+//	    postgresql.WithCloudSQLConnector(dialer, "my-project:europe-west1:main"),
+//	)
+//
+// The host and port config keys are ignored. Set sslmode to disable, as the
+// connector already provides TLS. It works with either driver and replaces
+// any WithCustomDialer.
+func WithCloudSQLConnector(dialer *cloudsqlconn.Dialer, instance string) Option {
+	return WithCustomDialer(func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return dialer.Dial(ctx, instance)
+	})
+}
@@ -0,0 +1,17 @@
+package postgresql
+
+import (
+	"testing"
+
+	"cloud.google.com/go/cloudsqlconn"
+)
+
+func TestWithCloudSQLConnector(t *testing.T) {
+	// Building the option must not touch the dialer, which is only used when
+	// a connection is opened.
+	var dialer *cloudsqlconn.Dialer
+	a := NewPostgreSQLAdapter(WithCloudSQLConnector(dialer, "project:region:instance"))
+	if a.dialer == nil {
+		t.Error("expected the Cloud SQL connector to be installed as the dialer")
+	}
+}
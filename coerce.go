@@ -0,0 +1,73 @@
+package postgresql
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// TypeCoercer converts the values Fetch and Execute read from a column
+// before they are placed in the row map. dbType is the column's type name
+// as reported by the driver, e.g. "NUMERIC", "VARCHAR" or "INT8". Coerce
+// returns raw unchanged for values it does not handle.
+type TypeCoercer interface {
+	Coerce(colName string, dbType string, raw interface{}) interface{}
+}
+
+// WithTypeCoercer passes every value read by Fetch and Execute through c.
+// Use DefaultTypeCoercer to get Go-native values for the column types the
+// drivers return as bytes.
+func WithTypeCoercer(c TypeCoercer) Option {
+	return func(a *PostgreSQLAdapter) {
+		a.typeCoercer = c
+	}
+}
+
+// DefaultTypeCoercer converts text columns (VARCHAR, TEXT, BPCHAR, NAME)
+// from []byte to string, NUMERIC to *big.Float, keeping every digit, and
+// BIGINT to int64. NaN numerics, which *big.Float cannot hold, are left
+// as they are.
+type DefaultTypeCoercer struct{}
+
+// Coerce implements TypeCoercer.
+func (DefaultTypeCoercer) Coerce(colName string, dbType string, raw interface{}) interface{} {
+	text, ok := rawText(raw)
+	if !ok {
+		return raw
+	}
+
+	switch strings.ToUpper(dbType) {
+	case "VARCHAR", "TEXT", "BPCHAR", "CHAR", "NAME":
+		return text
+	case "NUMERIC", "DECIMAL":
+		// Four bits per digit keeps every significant digit.
+		prec := uint(len(text)) * 4
+		if prec < 64 {
+			prec = 64
+		}
+		f, _, err := big.ParseFloat(text, 10, prec, big.ToNearestEven)
+		if err != nil {
+			return raw
+		}
+		return f
+	case "INT8", "BIGINT":
+		n, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return raw
+		}
+		return n
+	}
+	return raw
+}
+
+// rawText returns the text of a value the driver returned as bytes or a
+// string.
+func rawText(raw interface{}) (string, bool) {
+	switch v := raw.(type) {
+	case []byte:
+		return string(v), true
+	case string:
+		return v, true
+	}
+	return "", false
+}
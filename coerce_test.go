@@ -0,0 +1,71 @@
+package postgresql
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestDefaultTypeCoercer(t *testing.T) {
+	tests := []struct {
+		name     string
+		dbType   string
+		raw      interface{}
+		expected interface{}
+	}{
+		{name: "varchar bytes", dbType: "VARCHAR", raw: []byte("abc"), expected: "abc"},
+		{name: "text string", dbType: "TEXT", raw: "abc", expected: "abc"},
+		{name: "bigint bytes", dbType: "INT8", raw: []byte("9007199254740993"), expected: int64(9007199254740993)},
+		{name: "bigint already int", dbType: "INT8", raw: int64(7), expected: int64(7)},
+		{name: "numeric NaN", dbType: "NUMERIC", raw: []byte("NaN"), expected: []byte("NaN")},
+		{name: "nil", dbType: "NUMERIC", raw: nil, expected: nil},
+		{name: "other type", dbType: "UUID", raw: []byte("x"), expected: []byte("x")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := DefaultTypeCoercer{}.Coerce("col", tt.dbType, tt.raw)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %#v, got %#v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestDefaultTypeCoercer_Numeric(t *testing.T) {
+	const digits = "12345678901234567890.123456789"
+	result, ok := DefaultTypeCoercer{}.Coerce("amount", "NUMERIC", []byte(digits)).(*big.Float)
+	if !ok {
+		t.Fatalf("expected *big.Float, got %T", result)
+	}
+	if text := result.Text('f', 9); text != digits {
+		t.Errorf("expected %s, got %s", digits, text)
+	}
+}
+
+func TestWithTypeCoercer(t *testing.T) {
+	a := NewPostgreSQLAdapter(WithTypeCoercer(DefaultTypeCoercer{}))
+	if _, ok := a.typeCoercer.(DefaultTypeCoercer); !ok {
+		t.Errorf("expected DefaultTypeCoercer, got %T", a.typeCoercer)
+	}
+}
+
+func TestPostgreSQLAdapter_FetchTypeCoercion(t *testing.T) {
+	a := newIntegrationAdapter(t, WithTypeCoercer(DefaultTypeCoercer{}))
+
+	op := &adapter.Operation{Statement: "SELECT 1.50::numeric AS amount, 42::bigint AS n"}
+	results, err := a.Fetch(context.Background(), op, nil)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	row := results[0].(map[string]interface{})
+	if amount, ok := row["amount"].(*big.Float); !ok || amount.Text('f', 2) != "1.50" {
+		t.Errorf("expected *big.Float 1.50, got %#v", row["amount"])
+	}
+	if row["n"] != int64(42) {
+		t.Errorf("expected int64 42, got %#v", row["n"])
+	}
+}
@@ -0,0 +1,112 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// SetTableComment sets the comment on schema.table. An empty comment removes
+// any existing one.
+func (a *PostgreSQLAdapter) SetTableComment(ctx context.Context, schema, table, comment string) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	query := commentOnTableQuery(schema, table, comment)
+	if _, err := a.execContext(ctx, "set_table_comment", query); err != nil {
+		return fmt.Errorf("postgresql: failed to comment on table %s.%s: %w", schema, table, err)
+	}
+	return nil
+}
+
+// SetColumnComment sets the comment on schema.table.column. An empty comment
+// removes any existing one.
+func (a *PostgreSQLAdapter) SetColumnComment(ctx context.Context, schema, table, column, comment string) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	query := commentOnColumnQuery(schema, table, column, comment)
+	if _, err := a.execContext(ctx, "set_column_comment", query); err != nil {
+		return fmt.Errorf("postgresql: failed to comment on column %s.%s.%s: %w", schema, table, column, err)
+	}
+	return nil
+}
+
+// GetTableComment returns the comment on schema.table, or an empty string
+// when the table has none.
+func (a *PostgreSQLAdapter) GetTableComment(ctx context.Context, schema, table string) (string, error) {
+	if a.db == nil {
+		return "", ErrNotConnected
+	}
+
+	query := `SELECT d.description
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_description d
+			ON d.objoid = c.oid AND d.classoid = 'pg_class'::regclass AND d.objsubid = 0
+		WHERE n.nspname = $1 AND c.relname = $2`
+
+	var comment sql.NullString
+	err := a.queryRowContext(ctx, "get_table_comment", query, schema, table).Scan(&comment)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("postgresql: table %s.%s does not exist", schema, table)
+	}
+	if err != nil {
+		return "", fmt.Errorf("postgresql: failed to read comment of table %s.%s: %w", schema, table, err)
+	}
+	return comment.String, nil
+}
+
+// GetColumnComment returns the comment on schema.table.column, or an empty
+// string when the column has none.
+func (a *PostgreSQLAdapter) GetColumnComment(ctx context.Context, schema, table, column string) (string, error) {
+	if a.db == nil {
+		return "", ErrNotConnected
+	}
+
+	query := `SELECT d.description
+		FROM pg_attribute att
+		JOIN pg_class c ON c.oid = att.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_description d
+			ON d.objoid = c.oid AND d.classoid = 'pg_class'::regclass AND d.objsubid = att.attnum
+		WHERE n.nspname = $1 AND c.relname = $2 AND att.attname = $3
+			AND att.attnum > 0 AND NOT att.attisdropped`
+
+	var comment sql.NullString
+	err := a.queryRowContext(ctx, "get_column_comment", query, schema, table, column).Scan(&comment)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("postgresql: column %s.%s.%s does not exist", schema, table, column)
+	}
+	if err != nil {
+		return "", fmt.Errorf("postgresql: failed to read comment of column %s.%s.%s: %w", schema, table, column, err)
+	}
+	return comment.String, nil
+}
+
+// commentOnTableQuery builds the COMMENT ON TABLE statement. COMMENT does not
+// accept bind parameters, so the comment is quoted as a literal.
+func commentOnTableQuery(schema, table, comment string) string {
+	return fmt.Sprintf("COMMENT ON TABLE %s.%s IS %s",
+		pq.QuoteIdentifier(schema), pq.QuoteIdentifier(table), commentLiteral(comment))
+}
+
+// commentOnColumnQuery builds the COMMENT ON COLUMN statement.
+func commentOnColumnQuery(schema, table, column, comment string) string {
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s.%s IS %s",
+		pq.QuoteIdentifier(schema), pq.QuoteIdentifier(table), pq.QuoteIdentifier(column), commentLiteral(comment))
+}
+
+// commentLiteral quotes comment for a COMMENT statement; an empty comment
+// becomes NULL, which drops the existing comment.
+func commentLiteral(comment string) string {
+	if comment == "" {
+		return "NULL"
+	}
+	return pq.QuoteLiteral(comment)
+}
@@ -0,0 +1,51 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCommentOnTableQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		comment  string
+		expected string
+	}{
+		{"plain", "Registered users", `COMMENT ON TABLE "public"."users" IS 'Registered users'`},
+		{"quote", "User's data", `COMMENT ON TABLE "public"."users" IS 'User''s data'`},
+		{"empty", "", `COMMENT ON TABLE "public"."users" IS NULL`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := commentOnTableQuery("public", "users", tt.comment); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCommentOnColumnQuery(t *testing.T) {
+	expected := `COMMENT ON COLUMN "public"."users"."email" IS 'Login address'`
+	if result := commentOnColumnQuery("public", "users", "email", "Login address"); result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestPostgreSQLAdapter_CommentsWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	ctx := context.Background()
+
+	if err := a.SetTableComment(ctx, "public", "users", "x"); err == nil {
+		t.Error("SetTableComment: expected error when not connected, got nil")
+	}
+	if err := a.SetColumnComment(ctx, "public", "users", "id", "x"); err == nil {
+		t.Error("SetColumnComment: expected error when not connected, got nil")
+	}
+	if _, err := a.GetTableComment(ctx, "public", "users"); err == nil {
+		t.Error("GetTableComment: expected error when not connected, got nil")
+	}
+	if _, err := a.GetColumnComment(ctx, "public", "users", "id"); err == nil {
+		t.Error("GetColumnComment: expected error when not connected, got nil")
+	}
+}
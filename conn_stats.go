@@ -0,0 +1,61 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql/driver"
+	"sync/atomic"
+)
+
+// ConnectionsCreated returns how many connections the adapter has opened
+// since it was created, across Reconnect. Unlike sql.DBStats, which
+// describes the pool at one instant, it only ever grows.
+func (a *PostgreSQLAdapter) ConnectionsCreated() int64 {
+	return a.connsCreated.Load()
+}
+
+// ConnectionsReused returns how many times a connection has been taken
+// from the pool again after an earlier use. A high ratio of reused to
+// created connections means the pool is sized well; a low one points at
+// connections being closed by SetConnMaxLifetime, SetMaxIdleConns or
+// errors.
+func (a *PostgreSQLAdapter) ConnectionsReused() int64 {
+	return a.connsReused.Load()
+}
+
+// pqConn lists the interfaces lib/pq's connection implements. Embedding it
+// in pqCountedConn forwards all of them, so database/sql behaves exactly as
+// with the bare connection.
+type pqConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+// pqCountedConn wraps a lib/pq connection to count its reuse: database/sql
+// calls ResetSession each time a used connection is handed out again. pgx
+// counts through stdlib.OptionResetSession instead.
+type pqCountedConn struct {
+	pqConn
+	reused *atomic.Int64
+
+	// searchPathSet is set by markSearchPathSet.
+	searchPathSet atomic.Bool
+}
+
+// ResetSession counts the reuse, resets a search_path set by a schema
+// router and resets the underlying connection.
+func (c *pqCountedConn) ResetSession(ctx context.Context) error {
+	c.reused.Add(1)
+	if c.searchPathSet.Load() {
+		if _, err := c.ExecContext(ctx, "RESET search_path", nil); err != nil {
+			return driver.ErrBadConn
+		}
+		c.searchPathSet.Store(false)
+	}
+	return c.pqConn.ResetSession(ctx)
+}
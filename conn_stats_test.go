@@ -0,0 +1,92 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql/driver"
+	"sync/atomic"
+	"testing"
+)
+
+type fakePQConn struct {
+	pqConn
+	resets int
+	execs  []string
+}
+
+func (c *fakePQConn) ResetSession(context.Context) error {
+	c.resets++
+	return nil
+}
+
+func (c *fakePQConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.execs = append(c.execs, query)
+	return driver.RowsAffected(0), nil
+}
+
+func TestPQCountedConn_ResetSession(t *testing.T) {
+	inner := &fakePQConn{}
+	var reused atomic.Int64
+	conn := &pqCountedConn{pqConn: inner, reused: &reused}
+
+	var _ driver.SessionResetter = conn
+	for i := 0; i < 3; i++ {
+		if err := conn.ResetSession(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if reused.Load() != 3 || inner.resets != 3 {
+		t.Errorf("expected 3 counted and forwarded resets, got %d and %d", reused.Load(), inner.resets)
+	}
+}
+
+func TestPQCountedConn_ResetSearchPath(t *testing.T) {
+	inner := &fakePQConn{}
+	var reused atomic.Int64
+	conn := &pqCountedConn{pqConn: inner, reused: &reused}
+
+	markSearchPathSet(conn)
+	for i := 0; i < 2; i++ {
+		if err := conn.ResetSession(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(inner.execs) != 1 || inner.execs[0] != "RESET search_path" {
+		t.Errorf("expected a single RESET search_path, got %v", inner.execs)
+	}
+}
+
+func TestPostgreSQLAdapter_ConnectionCountersZero(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if a.ConnectionsCreated() != 0 || a.ConnectionsReused() != 0 {
+		t.Errorf("expected zero counters, got %d created and %d reused", a.ConnectionsCreated(), a.ConnectionsReused())
+	}
+}
+
+func TestPostgreSQLAdapter_ConnectionCounters(t *testing.T) {
+	for _, driverName := range []string{DriverPQ, DriverPGX} {
+		t.Run(driverName, func(t *testing.T) {
+			a := newIntegrationAdapter(t, WithDriver(driverName))
+			db, err := a.openDB()
+			if err != nil {
+				t.Fatalf("failed to open pool: %v", err)
+			}
+			defer func() { _ = db.Close() }()
+			db.SetMaxOpenConns(1)
+
+			for i := 0; i < 3; i++ {
+				if _, err := db.Exec("SELECT 1"); err != nil {
+					t.Fatalf("query %d failed: %v", i, err)
+				}
+			}
+
+			if created := a.ConnectionsCreated(); created != 1 {
+				t.Errorf("expected 1 connection created, got %d", created)
+			}
+			if reused := a.ConnectionsReused(); reused != 2 {
+				t.Errorf("expected 2 reuses, got %d", reused)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+)
+
+// ConnectHookFunc sets up a session right after its connection is opened.
+type ConnectHookFunc func(ctx context.Context, conn *sql.Conn) error
+
+// WithConnectHook makes the adapter call fn on every new connection before
+// the pool hands it out, for session setup such as SET TIME ZONE, SET
+// search_path or LOAD. If fn returns an error the connection is closed
+// and the operation that needed it fails, so no connection with a
+// half-applied setup reaches the pool. This is pgx's AfterConnect for
+// both drivers.
+//
+//	a := postgresql.NewPostgreSQLAdapter(postgresql.WithConnectHook(
+//	    func(ctx context.Context, conn *sql.Conn) error {
+//	        _, err := conn.ExecContext(ctx, "SET TIME ZONE 'UTC'")
+//	        return err
+//	    }))
+//
+// fn must not keep conn beyond its return.
+func WithConnectHook(fn ConnectHookFunc) Option {
+	return func(a *PostgreSQLAdapter) {
+		a.connectHook = fn
+	}
+}
+
+// hookConnector runs a connect hook on every connection its Connector
+// opens.
+type hookConnector struct {
+	driver.Connector
+	hook ConnectHookFunc
+}
+
+// Connect opens one connection and runs the hook on it.
+func (c hookConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.runHook(ctx, conn); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("postgresql: connect hook failed: %w", err)
+	}
+	return conn, nil
+}
+
+// runHook hands conn to the hook as an *sql.Conn, through a throwaway pool
+// holding only conn, which it leaves open.
+func (c hookConnector) runHook(ctx context.Context, conn driver.Conn) error {
+	db := sql.OpenDB(&singleConnector{conn: conn, driver: c.Driver()})
+	defer func() { _ = db.Close() }()
+
+	sc, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = sc.Close() }()
+
+	return c.hook(ctx, sc)
+}
+
+// singleConnector serves a single, already open connection, once.
+type singleConnector struct {
+	conn   driver.Conn
+	driver driver.Driver
+	used   bool
+}
+
+// Connect returns the connection, wrapped so that closing the pool does not
+// close it.
+func (c *singleConnector) Connect(context.Context) (driver.Conn, error) {
+	if c.used {
+		return nil, errors.New("postgresql: connect hook needs a single connection")
+	}
+	c.used = true
+	if pc, ok := c.conn.(pqConn); ok {
+		return unclosableConn{pc}, nil
+	}
+	return unclosableBareConn{c.conn}, nil
+}
+
+// Driver returns the driver of the wrapped connector.
+func (c *singleConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// unclosableConn forwards everything but Close to a connection
+// implementing the interfaces lib/pq and pgx do.
+type unclosableConn struct {
+	pqConn
+}
+
+// Close leaves the connection open.
+func (unclosableConn) Close() error {
+	return nil
+}
+
+// unclosableBareConn is unclosableConn for other drivers.
+type unclosableBareConn struct {
+	driver.Conn
+}
+
+// Close leaves the connection open.
+func (unclosableBareConn) Close() error {
+	return nil
+}
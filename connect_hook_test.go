@@ -0,0 +1,112 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"os"
+	"testing"
+)
+
+// fakeConn is a driver.Conn that only records whether it was closed.
+type fakeConn struct {
+	closed bool
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { c.closed = true; return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+// fakeConnector hands out conn.
+type fakeConnector struct {
+	conn *fakeConn
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return c.conn, nil }
+func (c fakeConnector) Driver() driver.Driver                        { return nil }
+
+func TestHookConnector(t *testing.T) {
+	errHook := errors.New("hook failed")
+
+	tests := []struct {
+		name    string
+		hookErr error
+	}{
+		{"hook succeeds", nil},
+		{"hook fails", errHook},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &fakeConn{}
+			var got driver.Conn
+			c := hookConnector{
+				Connector: fakeConnector{conn},
+				hook: func(ctx context.Context, sc *sql.Conn) error {
+					_ = sc.Raw(func(dc interface{}) error {
+						got = dc.(unclosableBareConn).Conn
+						return nil
+					})
+					return tt.hookErr
+				},
+			}
+
+			result, err := c.Connect(context.Background())
+			if got != conn {
+				t.Error("expected the hook to receive the new connection")
+			}
+			if tt.hookErr != nil {
+				if !errors.Is(err, errHook) {
+					t.Errorf("expected the hook error, got %v", err)
+				}
+				if !conn.closed {
+					t.Error("expected the connection to be closed")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("connect failed: %v", err)
+			}
+			if result != conn || conn.closed {
+				t.Error("expected the connection to be returned open")
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_ConnectHook(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if !isURLDSN(dsn) {
+		t.Skip("POSTGRES_TEST_DSN not set to a postgres:// URL; skipping integration test")
+	}
+
+	for _, driverName := range []string{DriverPQ, DriverPGX} {
+		t.Run(driverName, func(t *testing.T) {
+			a := NewPostgreSQLAdapter(WithDriver(driverName), WithConnectHook(func(ctx context.Context, conn *sql.Conn) error {
+				_, err := conn.ExecContext(ctx, "SET TIME ZONE 'Pacific/Auckland'")
+				return err
+			}))
+			if err := a.Connect(context.Background(), map[string]interface{}{ConfigConnectionURL: dsn}); err != nil {
+				t.Fatalf("connect failed: %v", err)
+			}
+			defer a.Close()
+
+			var tz string
+			if err := a.db.QueryRow("SELECT current_setting('TimeZone')").Scan(&tz); err != nil {
+				t.Fatalf("query failed: %v", err)
+			}
+			if tz != "Pacific/Auckland" {
+				t.Errorf("expected the hook's time zone, got %q", tz)
+			}
+		})
+	}
+
+	a := NewPostgreSQLAdapter(WithConnectHook(func(context.Context, *sql.Conn) error {
+		return errors.New("setup failed")
+	}))
+	if err := a.Connect(context.Background(), map[string]interface{}{ConfigConnectionURL: dsn}); err == nil {
+		_ = a.Close()
+		t.Error("expected connect to fail when the hook fails")
+	}
+}
@@ -0,0 +1,74 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// maxConnectRetryDelay caps the backoff between connection attempts.
+const maxConnectRetryDelay = 30 * time.Second
+
+// WithConnectRetry makes Connect, ConnectWithFallback and Reconnect try the
+// initial ping up to maxAttempts times, waiting baseDelay after the first
+// failure and doubling the wait after each further one, up to 30 seconds.
+// This covers a database that is still starting, as in container setups.
+// Errors that retrying cannot fix, such as a rejected password or an unknown
+// database, fail at once. A maxAttempts of one or less disables retries.
+func WithConnectRetry(maxAttempts int, baseDelay time.Duration) Option {
+	return func(a *PostgreSQLAdapter) {
+		a.connectAttempts = maxAttempts
+		a.connectRetryDelay = baseDelay
+	}
+}
+
+// pingWithRetry pings db, retrying as configured by WithConnectRetry.
+func (a *PostgreSQLAdapter) pingWithRetry(ctx context.Context, db *sql.DB) error {
+	attempts := max(a.connectAttempts, 1)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = db.PingContext(ctx); err == nil {
+			return nil
+		}
+		if attempt >= attempts || !isRetriableConnectError(err) {
+			return err
+		}
+
+		delay := connectRetryDelay(a.connectRetryDelay, attempt)
+		slog.Warn("postgresql: connection attempt failed, retrying",
+			"attempt", attempt, "max_attempts", attempts, "delay", delay, "error", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
+
+// connectRetryDelay returns the wait after the given failed attempt,
+// counting from one.
+func connectRetryDelay(base time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < maxConnectRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxConnectRetryDelay)
+}
+
+// isRetriableConnectError reports whether a failed ping may succeed later.
+// Authentication failures (SQLSTATE class 28) and a missing database
+// (3D000) come from a server that is up and will keep refusing.
+func isRetriableConnectError(err error) bool {
+	code := sqlState(err)
+	if code == "" {
+		return true
+	}
+	return !strings.HasPrefix(code, "28") && code != "3D000"
+}
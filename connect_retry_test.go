@@ -0,0 +1,88 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+func TestConnectRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{20, maxConnectRetryDelay},
+	}
+
+	for _, tt := range tests {
+		if result := connectRetryDelay(100*time.Millisecond, tt.attempt); result != tt.expected {
+			t.Errorf("attempt %d: expected %v, got %v", tt.attempt, tt.expected, result)
+		}
+	}
+}
+
+func TestIsRetriableConnectError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"network", errors.New("dial tcp 127.0.0.1:5432: connect: connection refused"), true},
+		{"starting up", &pq.Error{Code: "57P03"}, true},
+		{"bad password", &pq.Error{Code: "28P01"}, false},
+		{"unknown database", &pq.Error{Code: "3D000"}, false},
+		{"pgx bad password", fmt.Errorf("wrapped: %w", &pgconn.PgError{Code: "28P01"}), false},
+		{"pgx starting up", &pgconn.PgError{Code: "57P03"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := isRetriableConnectError(tt.err); result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_PingWithRetry(t *testing.T) {
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1 connect_timeout=1")
+	if err != nil {
+		t.Fatalf("failed to open pool: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	a := NewPostgreSQLAdapter(WithConnectRetry(3, 10*time.Millisecond))
+	start := time.Now()
+	if err := a.pingWithRetry(context.Background(), db); err == nil {
+		t.Fatal("expected error for unreachable server, got nil")
+	}
+	// Two waits: 10ms and 20ms.
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("expected retries to wait at least 30ms, took %v", elapsed)
+	}
+}
+
+func TestPostgreSQLAdapter_PingWithRetryCancelled(t *testing.T) {
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1 connect_timeout=1")
+	if err != nil {
+		t.Fatalf("failed to open pool: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	a := NewPostgreSQLAdapter(WithConnectRetry(100, time.Hour))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := a.pingWithRetry(ctx, db); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
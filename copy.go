@@ -0,0 +1,120 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/lib/pq"
+)
+
+// CopyInsert bulk-loads rows into tableName using the COPY FROM STDIN
+// protocol, which avoids parsing a huge multi-row VALUES statement and is
+// typically an order of magnitude faster for large loads. tableName may be
+// schema-qualified; each row must hold one value per entry in columns. It
+// returns the number of rows copied. When ctx carries a transaction (see
+// WithTx) the rows are copied inside it; this requires the lib/pq driver.
+func (a *PostgreSQLAdapter) CopyInsert(ctx context.Context, tableName string, columns []string, rows [][]interface{}) (int64, error) {
+	if a.db == nil {
+		return 0, ErrNotConnected
+	}
+
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	var (
+		n   int64
+		err error
+	)
+	switch tx := a.txFromContext(ctx); {
+	case tx != nil && a.driverName == DriverPGX:
+		err = fmt.Errorf("COPY inside a transaction is not supported with the pgx driver")
+	case tx != nil:
+		n, err = copyPqOn(ctx, tx.tx, tableName, columns, rows)
+	case a.driverName == DriverPGX:
+		n, err = a.copyPgx(ctx, tableName, columns, rows)
+	default:
+		n, err = a.copyPq(ctx, tableName, columns, rows)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("postgresql: copy into %s failed: %w", tableName, err)
+	}
+
+	return n, nil
+}
+
+// copyPq streams rows through lib/pq's COPY support inside a transaction.
+func (a *PostgreSQLAdapter) copyPq(ctx context.Context, tableName string, columns []string, rows [][]interface{}) (int64, error) {
+	tx, err := a.beginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	n, err := copyPqOn(ctx, tx, tableName, columns, rows)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// copyPqOn runs a lib/pq COPY on q, which must be a transaction.
+func copyPqOn(ctx context.Context, q querier, tableName string, columns []string, rows [][]interface{}) (int64, error) {
+	var copyStmt string
+	if schema, table, ok := strings.Cut(tableName, "."); ok {
+		copyStmt = pq.CopyInSchema(schema, table, columns...)
+	} else {
+		copyStmt = pq.CopyIn(tableName, columns...)
+	}
+
+	stmt, err := q.PrepareContext(ctx, copyStmt)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for i, row := range rows {
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			return 0, fmt.Errorf("row %d: %w", i, err)
+		}
+	}
+
+	// An Exec without arguments flushes the buffered rows and ends the COPY.
+	result, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// copyPgx streams rows through pgx's native CopyFrom.
+func (a *PostgreSQLAdapter) copyPgx(ctx context.Context, tableName string, columns []string, rows [][]interface{}) (int64, error) {
+	conn, err := a.conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = a.releaseConn(conn) }()
+
+	var n int64
+	err = conn.Raw(func(driverConn interface{}) error {
+		stdConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("unexpected driver connection %T", driverConn)
+		}
+
+		var copyErr error
+		n, copyErr = stdConn.Conn().CopyFrom(ctx, pgx.Identifier(strings.Split(tableName, ".")), columns, pgx.CopyFromRows(rows))
+		return copyErr
+	})
+
+	return n, err
+}
@@ -0,0 +1,111 @@
+package postgresql
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestPostgreSQLAdapter_CopyInsertWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	rows := [][]interface{}{{"Alice", "alice@example.com"}}
+	if _, err := a.CopyInsert(context.Background(), "users", []string{"name", "email"}, rows); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_CopyInsert(t *testing.T) {
+	for _, driverName := range []string{DriverPQ, DriverPGX} {
+		t.Run(driverName, func(t *testing.T) {
+			a := newIntegrationAdapter(t, WithDriver(driverName))
+			ctx := context.Background()
+
+			if _, err := a.db.ExecContext(ctx, `CREATE SCHEMA copy_test;
+				CREATE TABLE copy_test.items (id int PRIMARY KEY, name text)`); err != nil {
+				t.Fatalf("failed to create table: %v", err)
+			}
+			t.Cleanup(func() { _, _ = a.db.Exec("DROP SCHEMA copy_test CASCADE") })
+
+			rows := [][]interface{}{{1, "one"}, {2, "two"}, {3, nil}}
+			n, err := a.CopyInsert(ctx, "copy_test.items", []string{"id", "name"}, rows)
+			if err != nil {
+				t.Fatalf("copy failed: %v", err)
+			}
+			if n != 3 {
+				t.Errorf("expected 3 rows copied, got %d", n)
+			}
+
+			var count int
+			if err := a.db.QueryRowContext(ctx, "SELECT count(*) FROM copy_test.items").Scan(&count); err != nil {
+				t.Fatalf("count failed: %v", err)
+			}
+			if count != 3 {
+				t.Errorf("expected 3 rows in the table, got %d", count)
+			}
+
+			// A duplicate key fails the whole copy, and nothing is added.
+			_, err = a.CopyInsert(ctx, "copy_test.items", []string{"id", "name"}, [][]interface{}{{4, "four"}, {1, "again"}})
+			if err == nil || !strings.Contains(err.Error(), "copy into copy_test.items failed") {
+				t.Errorf("expected an error naming the table, got %v", err)
+			}
+			if err := a.db.QueryRowContext(ctx, "SELECT count(*) FROM copy_test.items").Scan(&count); err != nil {
+				t.Fatalf("count failed: %v", err)
+			}
+			if count != 3 {
+				t.Errorf("expected the failed copy to add no rows, got %d", count)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_CopyInsertInTx(t *testing.T) {
+	for _, driverName := range []string{DriverPQ, DriverPGX} {
+		t.Run(driverName, func(t *testing.T) {
+			a := newIntegrationAdapter(t, WithDriver(driverName))
+			ctx := context.Background()
+
+			if _, err := a.db.ExecContext(ctx, "CREATE TABLE copy_tx_test (id int PRIMARY KEY)"); err != nil {
+				t.Fatalf("failed to create table: %v", err)
+			}
+			t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE copy_tx_test") })
+
+			tx, err := a.BeginTx(ctx, nil)
+			if err != nil {
+				t.Fatalf("begin failed: %v", err)
+			}
+			defer func() { _ = tx.Rollback() }()
+
+			n, err := a.CopyInsert(WithTx(ctx, tx), "copy_tx_test", []string{"id"}, [][]interface{}{{1}, {2}})
+			if driverName == DriverPGX {
+				if err == nil {
+					t.Error("expected COPY inside a transaction to be rejected with pgx")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("copy failed: %v", err)
+			}
+			if n != 2 {
+				t.Errorf("expected 2 rows copied, got %d", n)
+			}
+
+			// The rows are only visible once the transaction commits.
+			var count int
+			if err := a.db.QueryRowContext(ctx, "SELECT count(*) FROM copy_tx_test").Scan(&count); err != nil {
+				t.Fatalf("count failed: %v", err)
+			}
+			if count != 0 {
+				t.Errorf("expected no rows outside the transaction, got %d", count)
+			}
+			if err := tx.Commit(); err != nil {
+				t.Fatalf("commit failed: %v", err)
+			}
+			if err := a.db.QueryRowContext(ctx, "SELECT count(*) FROM copy_tx_test").Scan(&count); err != nil {
+				t.Fatalf("count failed: %v", err)
+			}
+			if count != 2 {
+				t.Errorf("expected 2 rows after commit, got %d", count)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ddlTable collects the columns, primary key and indexes of the table
+// generated by GenerateDDL.
+type ddlTable struct {
+	name       string
+	columns    []ddlColumn
+	primaryKey []string
+	indexNames []string
+	indexes    map[string][]string
+}
+
+// ddlColumn is a column of the table generated by GenerateDDL.
+type ddlColumn struct {
+	name     string
+	dataType string
+	notNull  bool
+	unique   bool
+}
+
+// ddlTypes maps Go types with a fixed column type to that type.
+var ddlTypes = map[reflect.Type]string{
+	reflect.TypeOf(time.Time{}):       "timestamptz",
+	reflect.TypeOf([]byte(nil)):       "bytea",
+	reflect.TypeOf(sql.NullString{}):  "text",
+	reflect.TypeOf(sql.NullBool{}):    "boolean",
+	reflect.TypeOf(sql.NullByte{}):    "smallint",
+	reflect.TypeOf(sql.NullInt16{}):   "smallint",
+	reflect.TypeOf(sql.NullInt32{}):   "integer",
+	reflect.TypeOf(sql.NullInt64{}):   "bigint",
+	reflect.TypeOf(sql.NullFloat64{}): "double precision",
+	reflect.TypeOf(sql.NullTime{}):    "timestamptz",
+}
+
+// ddlKinds maps the remaining scalar kinds to a column type.
+var ddlKinds = map[reflect.Kind]string{
+	reflect.Bool:    "boolean",
+	reflect.Int8:    "smallint",
+	reflect.Int16:   "smallint",
+	reflect.Uint8:   "smallint",
+	reflect.Int32:   "integer",
+	reflect.Uint16:  "integer",
+	reflect.Int:     "bigint",
+	reflect.Int64:   "bigint",
+	reflect.Uint32:  "bigint",
+	reflect.Uint:    "numeric(20)",
+	reflect.Uint64:  "numeric(20)",
+	reflect.Float32: "real",
+	reflect.Float64: "double precision",
+	reflect.String:  "text",
+}
+
+// GenerateDDL returns the CREATE TABLE IF NOT EXISTS statement, followed by
+// any CREATE INDEX IF NOT EXISTS statements, for the struct model, a value
+// or pointer. schema may be empty to leave the table unqualified.
+//
+// The table is named by a TableName() string method on model, or after the
+// struct type in snake_case. Every exported field becomes a column, and the
+// fields of embedded structs are included in place. Field tags refine them:
+//
+//   - db:"name" names the column (default: the field name in snake_case);
+//     db:"-" skips the field.
+//   - pk:"true" adds the column to the primary key.
+//   - unique:"true" makes the column UNIQUE.
+//   - index:"true" indexes the column on its own; index:"name" adds it to
+//     the index called name, so several fields can share one index.
+//   - type:"varchar(64)" sets the column type, from the types AddColumn
+//     accepts.
+//
+// Without a type tag, the type follows the Go type: text, boolean, smallint,
+// integer, bigint, real, double precision, timestamptz, bytea, an array for
+// slices of those, and jsonb for other structs and maps. Pointers, slices,
+// maps and sql.Null* types are nullable; other columns are NOT NULL.
+//
+// GenerateDDL only builds the statements; run them with Execute or a
+// migration tool. It does not need a connection.
+func (a *PostgreSQLAdapter) GenerateDDL(ctx context.Context, schema string, model interface{}) (string, error) {
+	t := reflect.TypeOf(model)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return "", fmt.Errorf("postgresql: GenerateDDL needs a struct, got %T", model)
+	}
+
+	table := toSnakeCase(t.Name())
+	if named, ok := model.(interface{ TableName() string }); ok {
+		table = named.TableName()
+	}
+	if table == "" {
+		return "", fmt.Errorf("postgresql: cannot name the table of anonymous struct %T", model)
+	}
+	qualified := QuoteIdentifier(table)
+	if schema != "" {
+		qualified = QuoteIdentifier(schema) + "." + qualified
+	}
+
+	d := &ddlTable{name: table, indexes: map[string][]string{}}
+	if err := d.collect(t); err != nil {
+		return "", err
+	}
+	if len(d.columns) == 0 {
+		return "", fmt.Errorf("postgresql: %s has no exported fields", t)
+	}
+
+	defs := make([]string, 0, len(d.columns)+1)
+	for _, col := range d.columns {
+		def := QuoteIdentifier(col.name) + " " + col.dataType
+		if col.notNull || containsString(d.primaryKey, col.name) {
+			def += " NOT NULL"
+		}
+		if col.unique {
+			def += " UNIQUE"
+		}
+		defs = append(defs, def)
+	}
+	if len(d.primaryKey) > 0 {
+		defs = append(defs, "PRIMARY KEY ("+quoteColumns(d.primaryKey)+")")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE TABLE IF NOT EXISTS %s (\n\t%s\n);", qualified, strings.Join(defs, ",\n\t"))
+	for _, name := range d.indexNames {
+		fmt.Fprintf(&b, "\nCREATE INDEX IF NOT EXISTS %s ON %s (%s);", QuoteIdentifier(name), qualified, quoteColumns(d.indexes[name]))
+	}
+	return b.String(), nil
+}
+
+// collect adds the columns of the fields of t, recursing into embedded
+// structs, and records primary key and index membership.
+func (d *ddlTable) collect(t reflect.Type) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		dbTag := strings.Split(field.Tag.Get("db"), ",")[0]
+		if dbTag == "-" {
+			continue
+		}
+		// Embedded structs contribute their exported fields even when the
+		// struct type itself is unexported.
+		if field.Anonymous && dbTag == "" && field.Type.Kind() == reflect.Struct && ddlTypes[field.Type] == "" {
+			if err := d.collect(field.Type); err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		col := ddlColumn{name: dbTag, unique: field.Tag.Get("unique") == "true"}
+		if col.name == "" {
+			col.name = toSnakeCase(field.Name)
+		}
+		if err := validateIdentifier(col.name); err != nil {
+			return err
+		}
+
+		var err error
+		if typeTag := field.Tag.Get("type"); typeTag != "" {
+			col.dataType, err = normalizeColumnType(typeTag)
+			col.notNull = ddlNotNull(field.Type)
+		} else {
+			col.dataType, col.notNull, err = ddlType(field.Type)
+		}
+		if err != nil {
+			return fmt.Errorf("postgresql: field %s: %w", field.Name, err)
+		}
+		d.columns = append(d.columns, col)
+
+		if field.Tag.Get("pk") == "true" {
+			d.primaryKey = append(d.primaryKey, col.name)
+		}
+		if index := field.Tag.Get("index"); index != "" {
+			if index == "true" {
+				index = d.name + "_" + col.name + "_idx"
+			} else if err := validateIdentifier(index); err != nil {
+				return err
+			}
+			if _, ok := d.indexes[index]; !ok {
+				d.indexNames = append(d.indexNames, index)
+			}
+			d.indexes[index] = append(d.indexes[index], col.name)
+		}
+	}
+	return nil
+}
+
+// ddlType returns the column type for t and whether it is NOT NULL.
+func ddlType(t reflect.Type) (string, bool, error) {
+	notNull := ddlNotNull(t)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if dataType, ok := ddlTypes[t]; ok {
+		return dataType, notNull, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		elem, _, err := ddlType(t.Elem())
+		if err != nil || elem == "jsonb" {
+			return "jsonb", notNull, nil
+		}
+		return elem + "[]", notNull, nil
+	case reflect.Struct, reflect.Map:
+		return "jsonb", notNull, nil
+	}
+	if dataType, ok := ddlKinds[t.Kind()]; ok {
+		return dataType, notNull, nil
+	}
+	return "", false, fmt.Errorf("no column type for %s; set a type tag", t)
+}
+
+// ddlNotNull reports whether a field of type t cannot hold NULL.
+func ddlNotNull(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return false
+	}
+	_, nullable := reflect.New(t).Interface().(sql.Scanner)
+	return !nullable
+}
+
+// quoteColumns quotes and joins column names.
+func quoteColumns(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = QuoteIdentifier(column)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// toSnakeCase converts a Go identifier such as UserID or HTTPServer to
+// user_id or http_server.
+func toSnakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) && runes[i-1] != '_' {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
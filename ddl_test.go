@@ -0,0 +1,122 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+type ddlBase struct {
+	ID        int64     `pk:"true"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type ddlUser struct {
+	ddlBase
+	Email    string            `unique:"true"`
+	Name     *string           `type:"varchar(100)"`
+	OrgID    int32             `index:"true"`
+	Team     string            `index:"users_org_team_idx"`
+	Tags     []string          `db:"tags"`
+	Settings map[string]string `db:"settings"`
+	Score    sql.NullFloat64
+	Avatar   []byte
+	Secret   string `db:"-"`
+	internal int
+}
+
+func (ddlUser) TableName() string { return "users" }
+
+type ddlOrderItem struct {
+	OrderID int64 `pk:"true"`
+	Line    int16 `pk:"true" index:"order_lines_idx"`
+	Product string
+}
+
+func TestPostgreSQLAdapter_GenerateDDL(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	ctx := context.Background()
+
+	ddl, err := a.GenerateDDL(ctx, "app", &ddlUser{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `CREATE TABLE IF NOT EXISTS "app"."users" (
+	"id" bigint NOT NULL,
+	"created_at" timestamptz NOT NULL,
+	"email" text NOT NULL UNIQUE,
+	"name" varchar(100),
+	"org_id" integer NOT NULL,
+	"team" text NOT NULL,
+	"tags" text[],
+	"settings" jsonb,
+	"score" double precision,
+	"avatar" bytea,
+	PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "users_org_id_idx" ON "app"."users" ("org_id");
+CREATE INDEX IF NOT EXISTS "users_org_team_idx" ON "app"."users" ("team");`
+	if ddl != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, ddl)
+	}
+
+	ddl, err = a.GenerateDDL(ctx, "", ddlOrderItem{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = `CREATE TABLE IF NOT EXISTS "ddl_order_item" (
+	"order_id" bigint NOT NULL,
+	"line" smallint NOT NULL,
+	"product" text NOT NULL,
+	PRIMARY KEY ("order_id", "line")
+);
+CREATE INDEX IF NOT EXISTS "order_lines_idx" ON "ddl_order_item" ("line");`
+	if ddl != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, ddl)
+	}
+}
+
+func TestPostgreSQLAdapter_GenerateDDLErrors(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		model interface{}
+	}{
+		{"not a struct", 42},
+		{"nil", nil},
+		{"no fields", struct{ x int }{}},
+		{"unsupported type", struct{ C chan int }{}},
+		{"bad type tag", struct {
+			A string `type:"text; DROP TABLE users"`
+		}{}},
+		{"bad column name", struct {
+			A string `db:"a b"`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := a.GenerateDDL(ctx, "", tt.model); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"User":       "user",
+		"UserID":     "user_id",
+		"HTTPServer": "http_server",
+		"OrderItem2": "order_item2",
+		"already_ok": "already_ok",
+	}
+	for input, expected := range tests {
+		if result := toSnakeCase(input); result != expected {
+			t.Errorf("toSnakeCase(%q): expected %q, got %q", input, expected, result)
+		}
+	}
+}
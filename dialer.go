@@ -0,0 +1,121 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/lib/pq"
+)
+
+// DialContextFunc opens the network connection to the server. Its signature
+// matches net.Dialer.DialContext.
+type DialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// WithCustomDialer makes the adapter open its connections with dial, to
+// reach the server through a SOCKS5 proxy, an SSH tunnel or an
+// authenticating sidecar. It is used by both drivers and by Listen.
+//
+// With golang.org/x/net/proxy:
+//
+//	socks, err := proxy.SOCKS5("tcp", "proxy.internal:1080", nil, proxy.Direct)
+//	if err != nil {
+//	    return err
+//	}
+//	dial := socks.(proxy.ContextDialer).DialContext
+//	a := postgresql.NewPostgreSQLAdapter(postgresql.WithCustomDialer(dial))
+//
+// addr is the host and port from the connection settings, so the proxy
+// resolves the server's name.
+func WithCustomDialer(dial DialContextFunc) Option {
+	return func(a *PostgreSQLAdapter) {
+		a.dialer = dial
+	}
+}
+
+// openDB opens a pool for a.dsn with the configured driver, through the
+// custom dialer, with IAM tokens and with the connect hook when those are
+// set. Connections are counted for ConnectionsCreated and
+// ConnectionsReused.
+func (a *PostgreSQLAdapter) openDB() (*sql.DB, error) {
+	if a.driverName != DriverPGX {
+		// Validate the DSN now rather than on the first connection.
+		if _, err := pq.NewConnector(a.dsn); err != nil {
+			return nil, err
+		}
+		return sql.OpenDB(a.withConnectHook(pqConnector{a})), nil
+	}
+
+	config, err := pgx.ParseConfig(a.dsn)
+	if err != nil {
+		return nil, err
+	}
+	if a.dialer != nil {
+		config.DialFunc = pgconn.DialFunc(a.dialer)
+		// Leave name resolution to the dialer, as lib/pq does.
+		config.LookupFunc = func(ctx context.Context, host string) ([]string, error) {
+			return []string{host}, nil
+		}
+	}
+
+	opts := []stdlib.OptionOpenDB{
+		stdlib.OptionAfterConnect(func(context.Context, *pgx.Conn) error {
+			a.connsCreated.Add(1)
+			return nil
+		}),
+		stdlib.OptionResetSession(func(ctx context.Context, conn *pgx.Conn) error {
+			a.connsReused.Add(1)
+			return resetPgxSearchPath(ctx, conn)
+		}),
+	}
+	if a.iamTokenProvider != nil {
+		if a.iamUser != "" {
+			config.User = a.iamUser
+		}
+		opts = append(opts, stdlib.OptionBeforeConnect(func(ctx context.Context, cc *pgx.ConnConfig) error {
+			token, err := a.iamTokenProvider(ctx)
+			if err != nil {
+				return fmt.Errorf("postgresql: failed to get IAM auth token: %w", err)
+			}
+			cc.Password = token
+			return nil
+		}))
+	}
+	return sql.OpenDB(a.withConnectHook(stdlib.GetConnector(*config, opts...))), nil
+}
+
+// withConnectHook wraps connector to run the connect hook, if any.
+func (a *PostgreSQLAdapter) withConnectHook(connector driver.Connector) driver.Connector {
+	if a.connectHook == nil {
+		return connector
+	}
+	return hookConnector{Connector: connector, hook: a.connectHook}
+}
+
+// pqDialer adapts a DialContextFunc to lib/pq's Dialer and DialerContext.
+type pqDialer struct {
+	dial DialContextFunc
+}
+
+// Dial connects without a deadline.
+func (d pqDialer) Dial(network, address string) (net.Conn, error) {
+	return d.dial(context.Background(), network, address)
+}
+
+// DialTimeout connects, giving up after timeout.
+func (d pqDialer) DialTimeout(network, address string, timeout time.Duration) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return d.dial(ctx, network, address)
+}
+
+// DialContext connects until ctx is done.
+func (d pqDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return d.dial(ctx, network, address)
+}
@@ -0,0 +1,61 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPostgreSQLAdapter_CustomDialer(t *testing.T) {
+	errDialed := errors.New("dialed through custom dialer")
+
+	for _, driver := range []string{DriverPQ, DriverPGX} {
+		t.Run(driver, func(t *testing.T) {
+			var calls atomic.Int32
+			var gotAddr atomic.Value
+			dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+				calls.Add(1)
+				gotAddr.Store(addr)
+				return nil, errDialed
+			}
+
+			a := NewPostgreSQLAdapter(WithDriver(driver), WithCustomDialer(dial))
+			err := a.Connect(context.Background(), map[string]interface{}{
+				ConfigHost: "db.internal",
+				ConfigPort: 6543,
+			})
+			if err == nil {
+				t.Fatal("expected connect to fail")
+			}
+			if calls.Load() == 0 {
+				t.Fatal("expected the custom dialer to be used")
+			}
+			if addr, _ := gotAddr.Load().(string); !strings.Contains(addr, "6543") {
+				t.Errorf("expected the configured address, got %q", addr)
+			}
+		})
+	}
+}
+
+func TestPQDialer(t *testing.T) {
+	var got string
+	d := pqDialer{func(ctx context.Context, network, addr string) (net.Conn, error) {
+		got = network + " " + addr
+		if _, ok := ctx.Deadline(); !ok && strings.HasPrefix(addr, "timeout") {
+			t.Error("expected DialTimeout to set a deadline")
+		}
+		return nil, nil
+	}}
+
+	_, _ = d.Dial("tcp", "db:5432")
+	if got != "tcp db:5432" {
+		t.Errorf("unexpected dial %q", got)
+	}
+	_, _ = d.DialTimeout("tcp", "timeout:5432", 1e9)
+	if got != "tcp timeout:5432" {
+		t.Errorf("unexpected dial %q", got)
+	}
+}
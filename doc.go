@@ -71,6 +71,7 @@
 //   - max_connections: Maximum open connections
 //   - max_idle: Maximum idle connections
 //   - sslmode: SSL connection mode
+//   - sslcert, sslkey, sslrootcert: client certificate, its key and CA bundle
 //
 // # Thread Safety
 //
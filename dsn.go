@@ -0,0 +1,195 @@
+package postgresql
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// dsnKeyToConfig maps connection string keywords to the Connect config keys
+// they were built from.
+var dsnKeyToConfig = map[string]string{
+	"host":    ConfigHost,
+	"port":    ConfigPort,
+	"user":    ConfigUser,
+	"dbname":  ConfigDatabase,
+	"sslmode": ConfigSSLMode,
+}
+
+// ParsedConfig returns the settings of the connection string in use, keyed
+// like the Connect config (host, port, user, database, sslmode). Other
+// parameters such as application_name keep their connection string names.
+// The password is never included, so the result is safe to report from
+// health checks.
+func (a *PostgreSQLAdapter) ParsedConfig() (map[string]interface{}, error) {
+	if a.db == nil || a.dsn == "" {
+		return nil, ErrNotConnected
+	}
+
+	params, err := parseDSN(a.dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	config := make(map[string]interface{}, len(params))
+	for key, value := range params {
+		if key == "password" {
+			continue
+		}
+		if configKey, ok := dsnKeyToConfig[key]; ok {
+			key = configKey
+		}
+		config[key] = value
+	}
+
+	if port, ok := config[ConfigPort].(string); ok {
+		n, err := strconv.Atoi(port)
+		if err != nil {
+			return nil, fmt.Errorf("postgresql: invalid port %q in DSN", port)
+		}
+		config[ConfigPort] = n
+	}
+
+	return config, nil
+}
+
+// validSSLModes lists the sslmode values understood by the drivers.
+var validSSLModes = map[string]bool{
+	"disable": true, "allow": true, "prefer": true,
+	"require": true, "verify-ca": true, "verify-full": true,
+}
+
+// ValidateDSN checks that dsn, in key/value or postgres:// URL form, is well
+// formed and names a host and a database, without opening a connection. A
+// port or sslmode, when present, must be valid. Config loaders can use it to
+// reject a bad DSN at startup; Connect applies it to connection_url.
+func ValidateDSN(dsn string) error {
+	params, err := parseDSN(dsn)
+	if err != nil {
+		return err
+	}
+
+	if params["host"] == "" {
+		return fmt.Errorf("postgresql: DSN has no host")
+	}
+	if params["dbname"] == "" {
+		return fmt.Errorf("postgresql: DSN has no database name")
+	}
+	if port, ok := params["port"]; ok {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return fmt.Errorf("postgresql: DSN has invalid port %q", port)
+		}
+	}
+	if mode, ok := params["sslmode"]; ok && !validSSLModes[mode] {
+		return fmt.Errorf("postgresql: DSN has invalid sslmode %q", mode)
+	}
+	return nil
+}
+
+// parseDSN parses dsn in whichever form it is written.
+func parseDSN(dsn string) (map[string]string, error) {
+	if isURLDSN(dsn) {
+		return parseURLDSN(dsn)
+	}
+	return parseKeyValueDSN(dsn)
+}
+
+// isURLDSN reports whether dsn is written as a postgres:// URL.
+func isURLDSN(dsn string) bool {
+	return strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://")
+}
+
+// parseKeyValueDSN splits a "key=value key='quoted value'" connection string
+// into its parameters. Quoted values may contain \' and \\ escapes.
+func parseKeyValueDSN(dsn string) (map[string]string, error) {
+	params := make(map[string]string)
+	s := []rune(dsn)
+	i := 0
+
+	for {
+		for i < len(s) && unicode.IsSpace(s[i]) {
+			i++
+		}
+		if i >= len(s) {
+			return params, nil
+		}
+
+		start := i
+		for i < len(s) && s[i] != '=' && !unicode.IsSpace(s[i]) {
+			i++
+		}
+		key := string(s[start:i])
+		for i < len(s) && unicode.IsSpace(s[i]) {
+			i++
+		}
+		if i >= len(s) || s[i] != '=' {
+			return nil, fmt.Errorf("postgresql: missing \"=\" after %q in DSN", key)
+		}
+		i++
+		for i < len(s) && unicode.IsSpace(s[i]) {
+			i++
+		}
+
+		var value strings.Builder
+		if i < len(s) && s[i] == '\'' {
+			i++
+			for {
+				if i >= len(s) {
+					return nil, fmt.Errorf("postgresql: unterminated quoted value for %q in DSN", key)
+				}
+				if s[i] == '\'' {
+					i++
+					break
+				}
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				value.WriteRune(s[i])
+				i++
+			}
+		} else {
+			for i < len(s) && !unicode.IsSpace(s[i]) {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				value.WriteRune(s[i])
+				i++
+			}
+		}
+
+		params[key] = value.String()
+	}
+}
+
+// parseURLDSN extracts the parameters of a postgres:// connection URL.
+func parseURLDSN(dsn string) (map[string]string, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: invalid DSN: %w", err)
+	}
+
+	params := make(map[string]string)
+	if host := u.Hostname(); host != "" {
+		params["host"] = host
+	}
+	if port := u.Port(); port != "" {
+		params["port"] = port
+	}
+	if u.User != nil {
+		params["user"] = u.User.Username()
+		if password, ok := u.User.Password(); ok {
+			params["password"] = password
+		}
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		params["dbname"] = db
+	}
+	for key, values := range u.Query() {
+		if len(values) > 0 {
+			params[key] = values[len(values)-1]
+		}
+	}
+	return params, nil
+}
@@ -0,0 +1,109 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// ErrInvalidEnumValue is returned by Insert and Update when a string bound
+// to a column of a type registered with RegisterEnum is not one of the
+// type's values.
+type ErrInvalidEnumValue struct {
+	Type  string
+	Value string
+}
+
+// Error implements error.
+func (e ErrInvalidEnumValue) Error() string {
+	return fmt.Sprintf("postgresql: invalid value %q for enum %s", e.Value, e.Type)
+}
+
+// RegisterEnum declares the values of the PostgreSQL ENUM type typeName, so
+// that Insert and Update reject any other string bound to a column of that
+// type with ErrInvalidEnumValue before it reaches the server. typeName is
+// the type as format_type reports it, without quotes: unqualified when its
+// schema is on the search_path, schema-qualified otherwise. Registering a type again
+// replaces its values.
+//
+// While any enum is registered, Insert and Update look up the column types
+// of the table they write once per call. Update checks the columns listed
+// in op.Properties of statements whose table it can find.
+func (a *PostgreSQLAdapter) RegisterEnum(typeName string, values []string) {
+	allowed := make(map[string]bool, len(values))
+	for _, value := range values {
+		allowed[value] = true
+	}
+
+	a.enumsMu.Lock()
+	defer a.enumsMu.Unlock()
+	if a.enums == nil {
+		a.enums = make(map[string]map[string]bool)
+	}
+	a.enums[typeName] = allowed
+}
+
+// enumValues returns the values registered for typeName.
+func (a *PostgreSQLAdapter) enumValues(typeName string) (map[string]bool, bool) {
+	a.enumsMu.RLock()
+	defer a.enumsMu.RUnlock()
+	values, ok := a.enums[typeName]
+	return values, ok
+}
+
+// hasEnums reports whether any enum is registered.
+func (a *PostgreSQLAdapter) hasEnums() bool {
+	a.enumsMu.RLock()
+	defer a.enumsMu.RUnlock()
+	return len(a.enums) > 0
+}
+
+// checkEnums validates the values objects hold for the enum columns of
+// table among props.
+func (a *PostgreSQLAdapter) checkEnums(ctx context.Context, table string, props []adapter.PropertyMapping, objects []interface{}) error {
+	if !a.hasEnums() || table == "" || len(props) == 0 {
+		return nil
+	}
+
+	types, err := a.columnTypes(ctx, table)
+	if err != nil {
+		return err
+	}
+	for _, prop := range props {
+		typeName := strings.ReplaceAll(types[prop.DataField], `"`, "")
+		allowed, ok := a.enumValues(typeName)
+		if !ok {
+			continue
+		}
+		for _, objInterface := range objects {
+			obj, _ := objInterface.(map[string]interface{})
+			if err := checkEnumValue(typeName, allowed, obj[prop.ObjectField]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkEnumValue returns ErrInvalidEnumValue when value is a string outside
+// allowed. Other values are left to the server.
+func checkEnumValue(typeName string, allowed map[string]bool, value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case *string:
+		if v == nil {
+			return nil
+		}
+		s = *v
+	default:
+		return nil
+	}
+	if !allowed[s] {
+		return ErrInvalidEnumValue{Type: typeName, Value: s}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestCheckEnumValue(t *testing.T) {
+	allowed := map[string]bool{"happy": true, "sad": true}
+	valid := "sad"
+	invalid := "angry"
+
+	tests := []struct {
+		name    string
+		value   interface{}
+		wantErr bool
+	}{
+		{name: "valid", value: "happy"},
+		{name: "valid pointer", value: &valid},
+		{name: "nil pointer", value: (*string)(nil)},
+		{name: "nil", value: nil},
+		{name: "not a string", value: 3},
+		{name: "invalid", value: "angry", wantErr: true},
+		{name: "invalid pointer", value: &invalid, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkEnumValue("mood", allowed, tt.value)
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			var enumErr ErrInvalidEnumValue
+			if !errors.As(err, &enumErr) || enumErr.Type != "mood" || enumErr.Value != "angry" {
+				t.Errorf("expected ErrInvalidEnumValue for mood/angry, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_RegisterEnum(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if a.hasEnums() {
+		t.Error("expected no enums by default")
+	}
+
+	a.RegisterEnum("mood", []string{"happy", "sad"})
+	values, ok := a.enumValues("mood")
+	if !ok || !values["happy"] || values["angry"] {
+		t.Errorf("unexpected values: %v", values)
+	}
+
+	a.RegisterEnum("mood", []string{"ok"})
+	if values, _ := a.enumValues("mood"); values["happy"] || !values["ok"] {
+		t.Errorf("expected values to be replaced, got %v", values)
+	}
+}
+
+func TestPostgreSQLAdapter_InsertInvalidEnum(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, `CREATE TYPE enum_test_mood AS ENUM ('happy', 'sad');
+		CREATE TABLE enum_test (id int PRIMARY KEY, mood enum_test_mood)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE enum_test; DROP TYPE enum_test_mood") })
+	a.RegisterEnum("enum_test_mood", []string{"happy", "sad"})
+
+	op := &adapter.Operation{
+		Statement: "enum_test",
+		Properties: []adapter.PropertyMapping{
+			{ObjectField: "ID", DataField: "id"},
+			{ObjectField: "Mood", DataField: "mood"},
+		},
+	}
+	if err := a.Insert(ctx, op, []interface{}{map[string]interface{}{"ID": 1, "Mood": "happy"}}); err != nil {
+		t.Fatalf("insert failed: %v", err)
+	}
+
+	err := a.Insert(ctx, op, []interface{}{map[string]interface{}{"ID": 2, "Mood": "angry"}})
+	var enumErr ErrInvalidEnumValue
+	if !errors.As(err, &enumErr) || enumErr.Value != "angry" {
+		t.Errorf("expected ErrInvalidEnumValue, got %v", err)
+	}
+
+	updateOp := &adapter.Operation{
+		Statement:  "UPDATE enum_test SET mood = {Mood} WHERE id = {ID}",
+		Properties: op.Properties,
+	}
+	err = a.Update(ctx, updateOp, []interface{}{map[string]interface{}{"ID": 1, "Mood": "bored"}})
+	if !errors.As(err, &enumErr) || enumErr.Value != "bored" {
+		t.Errorf("expected ErrInvalidEnumValue from Update, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package postgresql
+
+import "os"
+
+// ExpandEnvVars returns a copy of config in which every ${VAR} and $VAR in a
+// string value is replaced by the value of the environment variable, or by
+// the empty string when it is unset. Nested maps and slices are expanded
+// too; other values are copied as is. Connect calls it on its config, so a
+// YAML setting such as
+//
+//	password: ${POSTGRES_PASSWORD}
+//
+// reaches the server as the variable's value. A literal dollar sign is
+// written $$, so a password such as pa$word must be given as pa$$word.
+func ExpandEnvVars(config map[string]interface{}) map[string]interface{} {
+	if config == nil {
+		return nil
+	}
+
+	expanded := make(map[string]interface{}, len(config))
+	for key, value := range config {
+		expanded[key] = expandEnvValue(value)
+	}
+	return expanded
+}
+
+// expandEnvValue expands the strings held by value.
+func expandEnvValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return os.Expand(v, expandEnvVar)
+	case map[string]interface{}:
+		return ExpandEnvVars(v)
+	case []interface{}:
+		expanded := make([]interface{}, len(v))
+		for i, item := range v {
+			expanded[i] = expandEnvValue(item)
+		}
+		return expanded
+	default:
+		return value
+	}
+}
+
+// expandEnvVar is the os.Expand mapping of expandEnvValue. os.Expand passes
+// the second $ of $$ as the name "$", which yields a literal dollar sign.
+func expandEnvVar(name string) string {
+	if name == "$" {
+		return "$"
+	}
+	return os.Getenv(name)
+}
@@ -0,0 +1,74 @@
+package postgresql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestExpandEnvVars(t *testing.T) {
+	t.Setenv("PG_TEST_PASSWORD", "s3cret")
+	t.Setenv("PG_TEST_HOST", "db.internal")
+
+	config := map[string]interface{}{
+		ConfigPassword: "${PG_TEST_PASSWORD}",
+		ConfigHost:     "$PG_TEST_HOST",
+		ConfigUser:     "app_${PG_TEST_UNSET}",
+		ConfigDatabase: "pa$$word$$${PG_TEST_HOST}",
+		ConfigPort:     5432,
+		"replicas":     []interface{}{"${PG_TEST_HOST}:5433", 1},
+		"tls":          map[string]interface{}{"key": "/etc/${PG_TEST_HOST}.key"},
+	}
+
+	expected := map[string]interface{}{
+		ConfigPassword: "s3cret",
+		ConfigHost:     "db.internal",
+		ConfigUser:     "app_",
+		ConfigDatabase: "pa$word$db.internal",
+		ConfigPort:     5432,
+		"replicas":     []interface{}{"db.internal:5433", 1},
+		"tls":          map[string]interface{}{"key": "/etc/db.internal.key"},
+	}
+
+	result := ExpandEnvVars(config)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+	if config[ConfigPassword] != "${PG_TEST_PASSWORD}" {
+		t.Error("expected the input config to be left untouched")
+	}
+}
+
+func TestExpandEnvVars_Nil(t *testing.T) {
+	if result := ExpandEnvVars(nil); result != nil {
+		t.Errorf("expected nil, got %v", result)
+	}
+}
+
+func TestPostgreSQLAdapter_ConnectExpandsEnvVars(t *testing.T) {
+	t.Setenv("PG_TEST_APP", "billing")
+
+	// The ping fails, but the DSN is built from the expanded config first.
+	a := NewPostgreSQLAdapter()
+	_ = a.Connect(context.Background(), map[string]interface{}{
+		ConfigHost:            "127.0.0.1",
+		ConfigPort:            1,
+		ConfigApplicationName: "${PG_TEST_APP}",
+	})
+	if a.applicationName != "billing" {
+		t.Errorf("expected expanded application name 'billing', got %q", a.applicationName)
+	}
+}
+
+func TestPostgreSQLAdapter_ConnectWithFallbackExpandsEnvVars(t *testing.T) {
+	t.Setenv("PG_TEST_APP", "billing")
+
+	// Every attempt fails, but each DSN is built from the expanded config.
+	a := NewPostgreSQLAdapter()
+	_ = a.ConnectWithFallback(context.Background(), []map[string]interface{}{
+		{ConfigHost: "127.0.0.1", ConfigPort: 1, ConfigApplicationName: "${PG_TEST_APP}"},
+	})
+	if a.applicationName != "billing" {
+		t.Errorf("expected expanded application name 'billing', got %q", a.applicationName)
+	}
+}
@@ -0,0 +1,54 @@
+package postgresql
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrNotConnected is returned by methods called before Connect or
+	// after Close.
+	ErrNotConnected = errors.New("postgresql: not connected")
+
+	// ErrNotATransaction is returned by Rollback on a PostgreSQLImplicitTx,
+	// whose statements are committed as they run and cannot be undone.
+	ErrNotATransaction = errors.New("postgresql: not a transaction")
+
+	// ErrLockNotHeld is returned by AdvisoryUnlock for a key the adapter
+	// does not hold.
+	ErrLockNotHeld = errors.New("postgresql: advisory lock not held")
+
+	// ErrExtensionNotInstalled is returned by methods that depend on a
+	// PostgreSQL extension missing from the database. Install it with
+	// CREATE EXTENSION.
+	ErrExtensionNotInstalled = errors.New("postgresql: extension not installed")
+
+	// ErrOptimisticLockConflict is returned by Update under optimistic
+	// locking when the row's version no longer matches the object's, i.e.
+	// it was changed or deleted since it was read.
+	ErrOptimisticLockConflict = errors.New("postgresql: optimistic lock conflict")
+
+	// ErrUnsupportedVersion is returned by methods that need a newer
+	// PostgreSQL release than the server runs.
+	ErrUnsupportedVersion = errors.New("postgresql: unsupported server version")
+
+	// ErrTxClosed is returned by the adapter from BeginAdapterTx once its
+	// transaction has been committed or rolled back.
+	ErrTxClosed = errors.New("postgresql: transaction closed")
+)
+
+// sqlState returns the SQLSTATE code of a server error from either driver,
+// or "" when err did not come from the server.
+func sqlState(err error) string {
+	var pqErr *pq.Error
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.As(err, &pqErr):
+		return string(pqErr.Code)
+	case errors.As(err, &pgErr):
+		return pgErr.Code
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// EnableAutoExplain loads the auto_explain module and has it log the plan,
+// with actual timings and buffer usage, of every statement that runs for at
+// least minDurationMs milliseconds. Zero logs every statement.
+//
+// The settings belong to the session that runs them. Call it with a context
+// carrying a transaction (see WithTx) to instrument that transaction's
+// connection; without one they land on a single pooled connection. To cover
+// every connection, set them in postgresql.conf or with ALTER ROLE instead.
+// Loading the module requires superuser rights unless it is listed in
+// session_preload_libraries.
+func (a *PostgreSQLAdapter) EnableAutoExplain(ctx context.Context, minDurationMs int) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+	if minDurationMs < 0 {
+		return fmt.Errorf("postgresql: auto_explain minimum duration must not be negative, got %d", minDurationMs)
+	}
+
+	// SET does not accept bind parameters, and the statements are sent
+	// together so they reach the same session.
+	if _, err := a.execContext(ctx, "enable_auto_explain", autoExplainQuery(minDurationMs)); err != nil {
+		return fmt.Errorf("postgresql: failed to enable auto_explain: %w", err)
+	}
+	return nil
+}
+
+// DisableAutoExplain resets the settings made by EnableAutoExplain. The module
+// itself stays loaded, since PostgreSQL cannot unload it from a session.
+func (a *PostgreSQLAdapter) DisableAutoExplain(ctx context.Context) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	query := "RESET auto_explain.log_min_duration; RESET auto_explain.log_analyze; RESET auto_explain.log_buffers"
+	if _, err := a.execContext(ctx, "disable_auto_explain", query); err != nil {
+		return fmt.Errorf("postgresql: failed to disable auto_explain: %w", err)
+	}
+	return nil
+}
+
+// ExplainQuery returns the plan PostgreSQL chooses for op's statement with
+// params bound, as the JSON document produced by EXPLAIN (FORMAT JSON).
+// With analyze set the statement is actually run and the plan carries real
+// row counts and timings; for INSERT, UPDATE or DELETE that means the
+// changes are made, so pass a context carrying a transaction (see WithTx)
+// and roll it back afterwards.
+func (a *PostgreSQLAdapter) ExplainQuery(ctx context.Context, op *adapter.Operation, params map[string]interface{}, analyze bool) (string, error) {
+	if a.db == nil {
+		return "", ErrNotConnected
+	}
+
+	args, err := extractArgs(op.Statement, params)
+	if err != nil {
+		return "", err
+	}
+	query := explainQuery(replaceNamedParams(op.Statement), analyze)
+
+	var plan string
+	if err := a.queryRowContext(ctx, operationName(op, "explain"), query, args...).Scan(&plan); err != nil {
+		return "", fmt.Errorf("postgresql: explain failed: %w", err)
+	}
+	return plan, nil
+}
+
+// explainQuery prefixes query with the EXPLAIN options used by ExplainQuery.
+func explainQuery(query string, analyze bool) string {
+	if analyze {
+		return "EXPLAIN (FORMAT JSON, ANALYZE) " + query
+	}
+	return "EXPLAIN (FORMAT JSON) " + query
+}
+
+// autoExplainQuery builds the statements run by EnableAutoExplain.
+func autoExplainQuery(minDurationMs int) string {
+	return fmt.Sprintf("LOAD 'auto_explain'; "+
+		"SET auto_explain.log_min_duration = %d; "+
+		"SET auto_explain.log_analyze = true; "+
+		"SET auto_explain.log_buffers = true", minDurationMs)
+}
@@ -0,0 +1,68 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestAutoExplainQuery(t *testing.T) {
+	expected := "LOAD 'auto_explain'; SET auto_explain.log_min_duration = 250; " +
+		"SET auto_explain.log_analyze = true; SET auto_explain.log_buffers = true"
+	if result := autoExplainQuery(250); result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestPostgreSQLAdapter_AutoExplainWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	ctx := context.Background()
+
+	if err := a.EnableAutoExplain(ctx, 100); err == nil {
+		t.Error("EnableAutoExplain: expected error when not connected, got nil")
+	}
+	if err := a.DisableAutoExplain(ctx); err == nil {
+		t.Error("DisableAutoExplain: expected error when not connected, got nil")
+	}
+}
+
+func TestExplainQuery(t *testing.T) {
+	tests := []struct {
+		analyze  bool
+		expected string
+	}{
+		{false, "EXPLAIN (FORMAT JSON) SELECT * FROM users WHERE id = $1"},
+		{true, "EXPLAIN (FORMAT JSON, ANALYZE) SELECT * FROM users WHERE id = $1"},
+	}
+
+	for _, tt := range tests {
+		if result := explainQuery("SELECT * FROM users WHERE id = $1", tt.analyze); result != tt.expected {
+			t.Errorf("expected %q, got %q", tt.expected, result)
+		}
+	}
+}
+
+func TestPostgreSQLAdapter_ExplainQueryWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	op := &adapter.Operation{Statement: "SELECT 1"}
+	if _, err := a.ExplainQuery(context.Background(), op, nil, false); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_ExplainQuery(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	op := &adapter.Operation{Statement: "SELECT {n}::int + 1"}
+
+	plan, err := a.ExplainQuery(context.Background(), op, map[string]interface{}{"n": 1}, true)
+	if err != nil {
+		t.Fatalf("explain failed: %v", err)
+	}
+
+	var doc []map[string]interface{}
+	if err := json.Unmarshal([]byte(plan), &doc); err != nil || len(doc) != 1 || doc[0]["Plan"] == nil {
+		t.Errorf("expected a JSON plan, got %q (%v)", plan, err)
+	}
+}
@@ -0,0 +1,26 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+)
+
+// RegisterExtensionTable marks extTable, which may be schema-qualified, as an
+// extension configuration table via pg_extension_config_dump, so pg_dump
+// includes its rows instead of treating them as part of the extension.
+//
+// PostgreSQL only accepts the call while an extension script is running, that
+// is from CREATE EXTENSION or ALTER EXTENSION ... UPDATE. Outside of that it
+// fails with "can only be called from an SQL script executed by CREATE
+// EXTENSION".
+func (a *PostgreSQLAdapter) RegisterExtensionTable(ctx context.Context, extTable string) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	query := "SELECT pg_extension_config_dump($1::regclass, '')"
+	if _, err := a.execContext(ctx, "register_extension_table", query, extTable); err != nil {
+		return fmt.Errorf("postgresql: failed to register extension table %s: %w", extTable, err)
+	}
+	return nil
+}
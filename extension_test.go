@@ -0,0 +1,13 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPostgreSQLAdapter_RegisterExtensionTableWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if err := a.RegisterExtensionTable(context.Background(), "myext.settings"); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
@@ -0,0 +1,154 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// FetchDistinctValues returns the distinct values of column in table, in
+// ascending order, for rows matching filter (see WhereFromMap). A limit of
+// zero returns every value. table may be schema-qualified; both names must
+// be plain identifiers.
+func (a *PostgreSQLAdapter) FetchDistinctValues(ctx context.Context, table, column string, filter map[string]interface{}, limit int) ([]interface{}, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+	if err := validateIdentifier(table); err != nil {
+		return nil, err
+	}
+	if err := validateIdentifier(column); err != nil {
+		return nil, err
+	}
+	if limit < 0 {
+		return nil, fmt.Errorf("postgresql: invalid limit %d", limit)
+	}
+
+	where, args, err := WhereFromMap(filter).Build(0)
+	if err != nil {
+		return nil, err
+	}
+
+	col := QuoteIdentifier(column)
+	query := fmt.Sprintf("SELECT DISTINCT %s FROM %s%s ORDER BY %s", col, QuoteIdentifier(table), where, col)
+	if limit > 0 {
+		args = append(args, limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := a.queryContext(ctx, "fetch_distinct_values", query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: query failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to get columns: %w", err)
+	}
+
+	values := []interface{}{}
+	err = a.eachRow(rows, func(row map[string]interface{}) error {
+		values = append(values, row[columns[0]])
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// FetchColumnNames returns the names of the columns op's statement yields
+// with params bound, without fetching any rows. The statement is wrapped as
+// SELECT * FROM (...) AS sub LIMIT 0, so PostgreSQL plans it and returns
+// only the row description; any LIMIT it already has is left alone.
+func (a *PostgreSQLAdapter) FetchColumnNames(ctx context.Context, op *adapter.Operation, params map[string]interface{}) ([]string, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	args, err := extractArgs(op.Statement, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := a.queryContext(ctx, operationName(op, "fetch_columns"), columnNamesQuery(replaceNamedParams(op.Statement)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: query failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to get columns: %w", err)
+	}
+	return columns, nil
+}
+
+// columnNamesQuery wraps query so it returns no rows.
+func columnNamesQuery(query string) string {
+	query = strings.TrimRight(strings.TrimSpace(query), ";")
+	return fmt.Sprintf("SELECT * FROM (%s) AS sub LIMIT 0", query)
+}
+
+// FetchCount returns the number of rows op's statement yields with params
+// bound, by running it as SELECT COUNT(*) FROM (...) AS _count_q. It lets a
+// pagination UI report the total for the same operation it pages through:
+// with ExcludeSoftDeleted in the operation options of ctx, soft-deleted rows
+// are left out of the count as Fetch leaves them out of the rows. LockMode
+// is ignored, since counting locks nothing.
+func (a *PostgreSQLAdapter) FetchCount(ctx context.Context, op *adapter.Operation, params map[string]interface{}) (int64, error) {
+	if a.db == nil {
+		return 0, ErrNotConnected
+	}
+
+	query, err := fetchStatement(ctx, op.Statement)
+	if err != nil {
+		return 0, err
+	}
+	args, err := extractArgs(query, params)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := a.queryRowContext(ctx, operationName(op, "fetch_count"), countQuery(replaceNamedParams(query)), args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("postgresql: count failed: %w", err)
+	}
+	return count, nil
+}
+
+// countQuery wraps query so it returns its row count.
+func countQuery(query string) string {
+	query = strings.TrimRight(strings.TrimSpace(query), ";")
+	return fmt.Sprintf("SELECT COUNT(*) FROM (%s) AS _count_q", query)
+}
+
+// FetchAll runs op as a multi-row fetch and returns every row as a map,
+// for callers that want the whole result set without setting op.Multi.
+// op itself is not modified. No matching rows yield an empty slice.
+func (a *PostgreSQLAdapter) FetchAll(ctx context.Context, op *adapter.Operation, params map[string]interface{}) ([]map[string]interface{}, error) {
+	multi := *op
+	multi.Multi = true
+
+	results, err := a.Fetch(ctx, &multi, params)
+	if err != nil {
+		return nil, err
+	}
+	return rowMaps(results)
+}
+
+// rowMaps converts the rows returned by Fetch to maps.
+func rowMaps(results []interface{}) ([]map[string]interface{}, error) {
+	rows := make([]map[string]interface{}, len(results))
+	for i, result := range results {
+		row, ok := result.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("postgresql: row %d is %T, not map[string]interface{}", i, result)
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
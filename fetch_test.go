@@ -0,0 +1,187 @@
+package postgresql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestPostgreSQLAdapter_FetchDistinctValuesWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if _, err := a.FetchDistinctValues(context.Background(), "users", "country", nil, 10); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_FetchDistinctValues(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, `CREATE TABLE distinct_test (country text, active bool);
+		INSERT INTO distinct_test VALUES ('fr', true), ('de', true), ('fr', true), ('es', false), ('it', true)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE distinct_test") })
+
+	values, err := a.FetchDistinctValues(ctx, "distinct_test", "country", map[string]interface{}{"active": true}, 2)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if !reflect.DeepEqual(values, []interface{}{"de", "fr"}) {
+		t.Errorf("expected [de fr], got %v", values)
+	}
+
+	if _, err := a.FetchDistinctValues(ctx, "distinct_test", "country; DROP TABLE x", nil, 0); err == nil {
+		t.Error("expected error for an unsafe column, got nil")
+	}
+}
+
+func TestColumnNamesQuery(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"SELECT id, name FROM users", "SELECT * FROM (SELECT id, name FROM users) AS sub LIMIT 0"},
+		{"SELECT id FROM users LIMIT 10;\n", "SELECT * FROM (SELECT id FROM users LIMIT 10) AS sub LIMIT 0"},
+	}
+
+	for _, tt := range tests {
+		if result := columnNamesQuery(tt.query); result != tt.expected {
+			t.Errorf("expected %q, got %q", tt.expected, result)
+		}
+	}
+}
+
+func TestPostgreSQLAdapter_FetchColumnNamesWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	op := &adapter.Operation{Statement: "SELECT 1"}
+	if _, err := a.FetchColumnNames(context.Background(), op, nil); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_FetchColumnNames(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	op := &adapter.Operation{Statement: "SELECT {n}::int AS id, 'x' AS name"}
+
+	columns, err := a.FetchColumnNames(context.Background(), op, map[string]interface{}{"n": 1})
+	if err != nil {
+		t.Fatalf("fetch columns failed: %v", err)
+	}
+	if !reflect.DeepEqual(columns, []string{"id", "name"}) {
+		t.Errorf("expected [id name], got %v", columns)
+	}
+}
+
+func TestCountQuery(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"SELECT id FROM users WHERE active", "SELECT COUNT(*) FROM (SELECT id FROM users WHERE active) AS _count_q"},
+		{"SELECT id FROM users;\n", "SELECT COUNT(*) FROM (SELECT id FROM users) AS _count_q"},
+	}
+
+	for _, tt := range tests {
+		if result := countQuery(tt.query); result != tt.expected {
+			t.Errorf("expected %q, got %q", tt.expected, result)
+		}
+	}
+}
+
+func TestPostgreSQLAdapter_FetchCountWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	op := &adapter.Operation{Statement: "SELECT 1"}
+	if _, err := a.FetchCount(context.Background(), op, nil); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_FetchCount(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	op := &adapter.Operation{Statement: "SELECT g FROM generate_series(1, 10) AS g WHERE g > {min}"}
+
+	count, err := a.FetchCount(context.Background(), op, map[string]interface{}{"min": 4})
+	if err != nil {
+		t.Fatalf("FetchCount failed: %v", err)
+	}
+	if count != 6 {
+		t.Errorf("expected 6, got %d", count)
+	}
+}
+
+func TestPostgreSQLAdapter_FetchCountExcludeSoftDeleted(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, `CREATE TABLE fetch_count_test (id int PRIMARY KEY, deleted_at timestamptz);
+		INSERT INTO fetch_count_test VALUES (1, NULL), (2, now()), (3, NULL)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE fetch_count_test") })
+
+	op := &adapter.Operation{Statement: "SELECT id FROM fetch_count_test WHERE id > {min}"}
+	params := map[string]interface{}{"min": 0}
+	ctx = WithOperationOptions(ctx, OperationOptions{
+		SoftDelete:         &SoftDeleteOption{Column: "deleted_at"},
+		ExcludeSoftDeleted: true,
+		LockMode:           LockForUpdate,
+	})
+
+	count, err := a.FetchCount(ctx, op, params)
+	if err != nil {
+		t.Fatalf("FetchCount failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected the 2 live rows, got %d", count)
+	}
+
+	ctx = WithOperationOptions(context.Background(), OperationOptions{ExcludeSoftDeleted: true})
+	if _, err := a.FetchCount(ctx, op, params); err == nil {
+		t.Error("expected error for ExcludeSoftDeleted without a column, got nil")
+	}
+}
+
+func TestRowMaps(t *testing.T) {
+	rows, err := rowMaps([]interface{}{map[string]interface{}{"id": 1}, map[string]interface{}{"id": 2}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(rows, []map[string]interface{}{{"id": 1}, {"id": 2}}) {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+
+	if _, err := rowMaps([]interface{}{map[string]interface{}{"id": 1}, "oops"}); err == nil {
+		t.Error("expected error for a non-map row, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_FetchAllWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if _, err := a.FetchAll(context.Background(), &adapter.Operation{Statement: "SELECT 1"}, nil); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_FetchAll(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	op := &adapter.Operation{Statement: "SELECT g AS n FROM generate_series(1, {count}) g"}
+
+	rows, err := a.FetchAll(context.Background(), op, map[string]interface{}{"count": 0})
+	if err != nil {
+		t.Fatalf("fetch all failed: %v", err)
+	}
+	if len(rows) != 0 || op.Multi {
+		t.Errorf("expected no rows and an unmodified op, got %v and Multi=%v", rows, op.Multi)
+	}
+
+	rows, err = a.FetchAll(context.Background(), op, map[string]interface{}{"count": 3})
+	if err != nil {
+		t.Fatalf("fetch all failed: %v", err)
+	}
+	if len(rows) != 3 || rows[2]["n"] != int64(3) {
+		t.Errorf("expected 3 rows, got %v", rows)
+	}
+}
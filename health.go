@@ -0,0 +1,71 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// HealthReport describes the database round trip and connection pool state
+// observed by HealthCheck.
+type HealthReport struct {
+	// Latency is the round-trip time of a trivial query.
+	Latency time.Duration
+	// OpenConnections counts pooled connections, both in use and idle.
+	OpenConnections int
+	// IdleConnections counts pooled connections not in use.
+	IdleConnections int
+	// WaitCount is the total number of times a caller had to wait for a
+	// free connection because the pool was at MaxOpenConnections.
+	WaitCount int64
+	// MaxOpenConnections is the pool size limit.
+	MaxOpenConnections int
+}
+
+// HealthCheck runs SELECT 1 to measure the database round trip and reports
+// it together with the connection pool statistics, for use by readiness
+// probes. If the query fails the report is still returned, with the pool
+// statistics filled in, alongside the error.
+func (a *PostgreSQLAdapter) HealthCheck(ctx context.Context) (*HealthReport, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	var one int
+	start := time.Now()
+	err := a.db.QueryRowContext(ctx, a.annotate("SELECT 1", "health_check")).Scan(&one)
+	latency := time.Since(start)
+
+	stats := a.db.Stats()
+	report := &HealthReport{
+		Latency:            latency,
+		OpenConnections:    stats.OpenConnections,
+		IdleConnections:    stats.Idle,
+		WaitCount:          stats.WaitCount,
+		MaxOpenConnections: stats.MaxOpenConnections,
+	}
+
+	if err != nil {
+		return report, fmt.Errorf("postgresql: health check failed: %w", err)
+	}
+	return report, nil
+}
+
+// Ping verifies that the database is still reachable, opening a connection
+// if the pool has none.
+func (a *PostgreSQLAdapter) Ping(ctx context.Context) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+	if err := a.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("postgresql: ping failed: %w", err)
+	}
+	return nil
+}
+
+// PingWithTimeout is Ping bounded by d.
+func (a *PostgreSQLAdapter) PingWithTimeout(d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return a.Ping(ctx)
+}
@@ -0,0 +1,78 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPostgreSQLAdapter_HealthCheckWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if _, err := a.HealthCheck(context.Background()); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_HealthCheckUnreachable(t *testing.T) {
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1 sslmode=disable connect_timeout=1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(3)
+
+	a := NewPostgreSQLAdapter()
+	a.db = db
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	report, err := a.HealthCheck(ctx)
+	if err == nil {
+		t.Fatal("expected error for unreachable server, got nil")
+	}
+	if report == nil || report.MaxOpenConnections != 3 {
+		t.Errorf("expected pool statistics alongside the error, got %+v", report)
+	}
+}
+
+func TestPostgreSQLAdapter_PingWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if err := a.Ping(context.Background()); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Ping: expected ErrNotConnected, got %v", err)
+	}
+	if err := a.PingWithTimeout(time.Second); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("PingWithTimeout: expected ErrNotConnected, got %v", err)
+	}
+}
+
+func TestPostgreSQLAdapter_PingUnreachable(t *testing.T) {
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1 sslmode=disable connect_timeout=1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	a := NewPostgreSQLAdapter()
+	a.db = db
+	if err := a.PingWithTimeout(5 * time.Second); err == nil {
+		t.Error("expected error for unreachable server, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_HealthCheck(t *testing.T) {
+	a := newIntegrationAdapter(t)
+
+	report, err := a.HealthCheck(context.Background())
+	if err != nil {
+		t.Fatalf("health check failed: %v", err)
+	}
+	if report.Latency <= 0 {
+		t.Errorf("expected positive latency, got %v", report.Latency)
+	}
+	if report.OpenConnections < 1 {
+		t.Errorf("expected at least one open connection, got %d", report.OpenConnections)
+	}
+}
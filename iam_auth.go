@@ -0,0 +1,160 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/lib/pq"
+)
+
+// rdsIAMTokenExpiry is how long an RDS IAM token stays valid for opening a
+// connection. Established connections are not affected by its expiry.
+const rdsIAMTokenExpiry = 15 * time.Minute
+
+// emptyPayloadHash is the SHA-256 of an empty body, signed for GET requests.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// WithIAMAuth authenticates as the database user iamUser with AWS RDS IAM
+// tokens instead of a password. tokenProvider is called for every new
+// connection, so pooled connections opened after the first token expires
+// still authenticate; a nil tokenProvider uses BuildRDSIAMToken for the
+// configured host and port in region with the default AWS credentials.
+// The user and password in the connection settings are ignored. RDS only
+// accepts IAM authentication over TLS, so set sslmode to require or
+// stricter.
+//
+// Listen fetches one token when it starts; its automatic reconnects reuse
+// it and fail once it has expired.
+func WithIAMAuth(region, iamUser string, tokenProvider func(ctx context.Context) (string, error)) Option {
+	return func(a *PostgreSQLAdapter) {
+		a.iamRegion = region
+		a.iamUser = iamUser
+		a.azureToken = nil
+		a.iamTokenProvider = tokenProvider
+		if a.iamTokenProvider == nil {
+			a.iamTokenProvider = a.defaultIAMToken
+		}
+	}
+}
+
+// BuildRDSIAMToken returns an authentication token for connecting to the RDS
+// instance at endpoint ("host:port") as user, signed with the default AWS
+// credential chain for region. Tokens are valid for 15 minutes.
+func BuildRDSIAMToken(ctx context.Context, endpoint, region, user string) (string, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return "", fmt.Errorf("postgresql: failed to load AWS config: %w", err)
+	}
+	creds, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("postgresql: failed to retrieve AWS credentials: %w", err)
+	}
+	return buildRDSIAMToken(ctx, endpoint, region, user, creds, time.Now())
+}
+
+// buildRDSIAMToken presigns the rds-db connect request for endpoint and
+// strips the scheme, which is what RDS expects as the password.
+func buildRDSIAMToken(ctx context.Context, endpoint, region, user string, creds aws.Credentials, now time.Time) (string, error) {
+	if _, _, err := net.SplitHostPort(endpoint); err != nil {
+		return "", fmt.Errorf("postgresql: invalid RDS endpoint %q: %w", endpoint, err)
+	}
+
+	query := url.Values{
+		"Action":        {"connect"},
+		"DBUser":        {user},
+		"X-Amz-Expires": {fmt.Sprintf("%d", int(rdsIAMTokenExpiry/time.Second))},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+endpoint+"/?"+query.Encode(), nil)
+	if err != nil {
+		return "", fmt.Errorf("postgresql: failed to build RDS IAM request: %w", err)
+	}
+
+	signed, _, err := v4.NewSigner().PresignHTTP(ctx, creds, req, emptyPayloadHash, "rds-db", region, now.UTC())
+	if err != nil {
+		return "", fmt.Errorf("postgresql: failed to sign RDS IAM token: %w", err)
+	}
+	return strings.TrimPrefix(signed, "https://"), nil
+}
+
+// defaultIAMToken builds a token for the host and port of the current DSN.
+func (a *PostgreSQLAdapter) defaultIAMToken(ctx context.Context) (string, error) {
+	params, err := parseDSN(a.dsn)
+	if err != nil {
+		return "", err
+	}
+	host, port := params["host"], params["port"]
+	if port == "" {
+		port = "5432"
+	}
+	return BuildRDSIAMToken(ctx, net.JoinHostPort(host, port), a.iamRegion, a.iamUser)
+}
+
+// connectDSN returns the lib/pq connection string for a new connection:
+// a.dsn, with the IAM user and a fresh token when token authentication is
+// on. An empty iamUser keeps the configured user.
+func (a *PostgreSQLAdapter) connectDSN(ctx context.Context) (string, error) {
+	if a.iamTokenProvider == nil {
+		return a.dsn, nil
+	}
+
+	token, err := a.iamTokenProvider(ctx)
+	if err != nil {
+		return "", fmt.Errorf("postgresql: failed to get IAM auth token: %w", err)
+	}
+
+	dsn := a.dsn
+	if isURLDSN(dsn) {
+		if dsn, err = pq.ParseURL(dsn); err != nil {
+			return "", err
+		}
+	}
+	// Later keywords override earlier ones.
+	if a.iamUser != "" {
+		dsn = fmt.Sprintf("%s user='%s'", dsn, escapeDSNValue(a.iamUser))
+	}
+	return fmt.Sprintf("%s password='%s'", dsn, escapeDSNValue(token)), nil
+}
+
+// pqConnector opens lib/pq connections with the DSN from connectDSN and
+// the custom dialer, if any, and counts them in ConnectionsCreated.
+type pqConnector struct {
+	adapter *PostgreSQLAdapter
+}
+
+// Connect opens one connection.
+func (c pqConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	dsn, err := c.adapter.connectDSN(ctx)
+	if err != nil {
+		return nil, err
+	}
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if c.adapter.dialer != nil {
+		connector.Dialer(pqDialer{c.adapter.dialer})
+	}
+	conn, err := connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.adapter.connsCreated.Add(1)
+	if pc, ok := conn.(pqConn); ok {
+		return &pqCountedConn{pqConn: pc, reused: &c.adapter.connsReused}, nil
+	}
+	return conn, nil
+}
+
+// Driver returns the lib/pq driver.
+func (c pqConnector) Driver() driver.Driver {
+	return &pq.Driver{}
+}
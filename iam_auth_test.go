@@ -0,0 +1,97 @@
+package postgresql
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestBuildRDSIAMToken(t *testing.T) {
+	creds := aws.Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	token, err := buildRDSIAMToken(context.Background(), "db.example.rds.amazonaws.com:5432", "eu-west-1", "app_user", creds, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(token, "db.example.rds.amazonaws.com:5432/?") {
+		t.Errorf("expected token to start with the endpoint, got %q", token)
+	}
+	for _, part := range []string{
+		"Action=connect",
+		"DBUser=app_user",
+		"X-Amz-Expires=900",
+		"X-Amz-Credential=AKIDEXAMPLE%2F20240501%2Feu-west-1%2Frds-db%2Faws4_request",
+		"X-Amz-Signature=",
+	} {
+		if !strings.Contains(token, part) {
+			t.Errorf("expected token to contain %q, got %q", part, token)
+		}
+	}
+
+	if _, err := buildRDSIAMToken(context.Background(), "no-port", "eu-west-1", "app_user", creds, now); err == nil {
+		t.Error("expected error for endpoint without port, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_ConnectDSN(t *testing.T) {
+	provider := func(ctx context.Context) (string, error) { return "tok'en", nil }
+
+	tests := []struct {
+		name     string
+		dsn      string
+		expected string
+	}{
+		{
+			"key value",
+			"host=db user=postgres password=old dbname=app",
+			`host=db user=postgres password=old dbname=app user='iam_user' password='tok\'en'`,
+		},
+		{
+			"url",
+			"postgres://db:5432/app",
+			`dbname='app' host='db' port='5432' user='iam_user' password='tok\'en'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewPostgreSQLAdapter(WithIAMAuth("eu-west-1", "iam_user", provider))
+			a.dsn = tt.dsn
+			result, err := a.connectDSN(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_IAMAuthTokenPerConnection(t *testing.T) {
+	for _, driver := range []string{DriverPQ, DriverPGX} {
+		t.Run(driver, func(t *testing.T) {
+			var calls atomic.Int32
+			provider := func(ctx context.Context) (string, error) {
+				calls.Add(1)
+				return "token", nil
+			}
+
+			// The server is unreachable, but the token is fetched first.
+			a := NewPostgreSQLAdapter(WithDriver(driver), WithIAMAuth("eu-west-1", "iam_user", provider))
+			err := a.Connect(context.Background(), map[string]interface{}{ConfigHost: "127.0.0.1", ConfigPort: 1})
+			if err == nil {
+				t.Fatal("expected connect to fail")
+			}
+			if calls.Load() == 0 {
+				t.Error("expected the token provider to be called")
+			}
+		})
+	}
+}
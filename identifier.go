@@ -0,0 +1,66 @@
+package postgresql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// QuoteIdentifier quotes name for use as an SQL identifier, doubling any
+// embedded double quotes. A schema-qualified name such as "sales.Orders" is
+// split on dots and each component quoted on its own, giving
+// "sales"."Orders". Components that are already validly double-quoted, with
+// every embedded double quote doubled, are kept as they are. Quoted identifiers are case-sensitive, so a table created
+// unquoted as Orders must be referred to as orders.
+func QuoteIdentifier(name string) string {
+	parts := splitQualifiedName(name)
+	for i, part := range parts {
+		if isQuotedIdentifier(part) {
+			continue
+		}
+		parts[i] = pq.QuoteIdentifier(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// isQuotedIdentifier reports whether part is a complete quoted identifier:
+// wrapped in double quotes, with every double quote inside doubled.
+func isQuotedIdentifier(part string) bool {
+	if len(part) < 2 || !strings.HasPrefix(part, `"`) || !strings.HasSuffix(part, `"`) {
+		return false
+	}
+	return !strings.Contains(strings.ReplaceAll(part[1:len(part)-1], `""`, ""), `"`)
+}
+
+// splitQualifiedName splits name on the dots that are not inside double
+// quotes.
+func splitQualifiedName(name string) []string {
+	var parts []string
+	inQuotes := false
+	start := 0
+	for i, ch := range name {
+		switch {
+		case ch == '"':
+			inQuotes = !inQuotes
+		case ch == '.' && !inQuotes:
+			parts = append(parts, name[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, name[start:])
+}
+
+// safeIdentifier matches a plain or schema-qualified SQL identifier made of
+// letters, digits and underscores.
+var safeIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// validateIdentifier rejects names that are not safe identifiers, so they can
+// be interpolated into SQL.
+func validateIdentifier(name string) error {
+	if !safeIdentifier.MatchString(name) {
+		return fmt.Errorf("postgresql: invalid identifier %q", name)
+	}
+	return nil
+}
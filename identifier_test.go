@@ -0,0 +1,48 @@
+package postgresql
+
+import "testing"
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"events", `"events"`},
+		{"Orders", `"Orders"`},
+		{"order items", `"order items"`},
+		{"archive.events_2024_01", `"archive"."events_2024_01"`},
+		{`we"ird`, `"we""ird"`},
+		{`"Sales"."Q1.2024"`, `"Sales"."Q1.2024"`},
+		{`sales."Q1.2024"`, `"sales"."Q1.2024"`},
+		{`"we""ird"`, `"we""ird"`},
+		{`"x"; DROP TABLE users; --"`, `"""x""; DROP TABLE users; --"""`},
+		{`"`, `""""`},
+	}
+
+	for _, tt := range tests {
+		if result := QuoteIdentifier(tt.input); result != tt.expected {
+			t.Errorf("QuoteIdentifier(%q): expected %s, got %s", tt.input, tt.expected, result)
+		}
+	}
+}
+
+func TestValidateIdentifier(t *testing.T) {
+	for name, valid := range map[string]bool{
+		"users":          true,
+		"_tmp1":          true,
+		"public.users":   true,
+		"Users":          true,
+		"1users":         false,
+		"users; DROP":    false,
+		"a.b.c":          false,
+		`"users"`:        false,
+		"":               false,
+		"user name":      false,
+		"public.":        false,
+		"users--comment": false,
+	} {
+		if err := validateIdentifier(name); (err == nil) != valid {
+			t.Errorf("validateIdentifier(%q): expected valid=%v, got error %v", name, valid, err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// PostgreSQLImplicitTx runs statements in auto-commit mode on a single
+// dedicated connection. It exists for statements PostgreSQL refuses to run
+// inside a transaction block, such as CREATE INDEX CONCURRENTLY or VACUUM,
+// while still guaranteeing that session state (SET, temporary tables) is
+// shared between the statements.
+type PostgreSQLImplicitTx struct {
+	adapter *PostgreSQLAdapter
+	conn    *sql.Conn
+}
+
+// BeginImplicit reserves a connection from the pool for auto-commit use.
+// Call Commit or Close to return it to the pool.
+func (a *PostgreSQLAdapter) BeginImplicit(ctx context.Context) (*PostgreSQLImplicitTx, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	conn, err := a.conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to acquire connection: %w", err)
+	}
+
+	return &PostgreSQLImplicitTx{adapter: a, conn: conn}, nil
+}
+
+// Execute runs a custom SQL statement on the reserved connection, like
+// PostgreSQLAdapter.Execute.
+func (t *PostgreSQLImplicitTx) Execute(ctx context.Context, action *adapter.Action, params map[string]interface{}) (interface{}, error) {
+	args, err := extractArgs(action.Statement, params)
+	if err != nil {
+		return nil, err
+	}
+	query := t.adapter.annotate(replaceNamedParams(action.Statement), action.Name)
+
+	rows, err := t.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: execute failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return t.adapter.scanRows(rows)
+}
+
+// RawExec runs a statement with positional ($1, $2, ...) arguments on the
+// reserved connection.
+func (t *PostgreSQLImplicitTx) RawExec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	result, err := t.conn.ExecContext(ctx, t.adapter.annotate(query, "raw_exec"), args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: exec failed: %w", err)
+	}
+	return result, nil
+}
+
+// Commit has nothing to commit, as every statement was committed when it ran.
+// It returns the reserved connection to the pool.
+func (t *PostgreSQLImplicitTx) Commit() error {
+	return t.Close()
+}
+
+// Rollback always returns ErrNotATransaction: auto-commit statements cannot
+// be undone. The connection stays reserved until Commit or Close.
+func (t *PostgreSQLImplicitTx) Rollback() error {
+	return ErrNotATransaction
+}
+
+// Close returns the reserved connection to the pool.
+func (t *PostgreSQLImplicitTx) Close() error {
+	if err := t.adapter.releaseConn(t.conn); err != nil && !errors.Is(err, sql.ErrConnDone) {
+		return fmt.Errorf("postgresql: failed to release connection: %w", err)
+	}
+	return nil
+}
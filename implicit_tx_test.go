@@ -0,0 +1,21 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPostgreSQLAdapter_BeginImplicitWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if _, err := a.BeginImplicit(context.Background()); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLImplicitTx_Rollback(t *testing.T) {
+	tx := &PostgreSQLImplicitTx{adapter: NewPostgreSQLAdapter()}
+	if err := tx.Rollback(); !errors.Is(err, ErrNotATransaction) {
+		t.Errorf("expected ErrNotATransaction, got %v", err)
+	}
+}
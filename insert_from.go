@@ -0,0 +1,89 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// InsertFromAdapter copies rows from another adapter into this one: it
+// fetches srcOp with srcParams from srcAdapter as a multi-row fetch and
+// inserts every row with destOp, returning the number of rows inserted.
+// srcAdapter is typically a PostgreSQLAdapter connected to another
+// instance, which makes this a simple way to replicate a table or the
+// result of a query between databases.
+//
+// Source columns are renamed with the ColumnTransform of the operation
+// options in ctx, if set. destOp.Statement is the destination table; when
+// destOp has no Properties, every (renamed) column of the first row is
+// inserted into the column of the same name. The source rows are held in
+// memory, so copy large tables in batches, for example with keyset
+// pagination in srcOp.
+func (a *PostgreSQLAdapter) InsertFromAdapter(ctx context.Context, destOp *adapter.Operation, srcAdapter adapter.Adapter, srcOp *adapter.Operation, srcParams map[string]interface{}) (int64, error) {
+	if a.db == nil {
+		return 0, ErrNotConnected
+	}
+
+	multi := *srcOp
+	multi.Multi = true
+	results, err := srcAdapter.Fetch(ctx, &multi, srcParams)
+	if err != nil {
+		return 0, fmt.Errorf("postgresql: failed to fetch source rows: %w", err)
+	}
+	rows, err := rowMaps(results)
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	objects := make([]interface{}, len(rows))
+	transform := OperationOptionsFromContext(ctx).ColumnTransform
+	for i, row := range rows {
+		objects[i] = renameColumns(row, transform)
+	}
+
+	op := destOp
+	if len(destOp.Properties) == 0 {
+		withProps := *destOp
+		withProps.Properties = columnProperties(objects[0].(map[string]interface{}))
+		op = &withProps
+	}
+
+	if err := a.Insert(ctx, op, objects); err != nil {
+		return 0, err
+	}
+	return int64(len(objects)), nil
+}
+
+// renameColumns returns row with its keys passed through transform, or row
+// itself when transform is nil.
+func renameColumns(row map[string]interface{}, transform func(string) string) map[string]interface{} {
+	if transform == nil {
+		return row
+	}
+	renamed := make(map[string]interface{}, len(row))
+	for column, value := range row {
+		renamed[transform(column)] = value
+	}
+	return renamed
+}
+
+// columnProperties maps every key of row to the column of the same name,
+// in sorted order.
+func columnProperties(row map[string]interface{}) []adapter.PropertyMapping {
+	columns := make([]string, 0, len(row))
+	for column := range row {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	props := make([]adapter.PropertyMapping, len(columns))
+	for i, column := range columns {
+		props[i] = adapter.PropertyMapping{ObjectField: column, DataField: column}
+	}
+	return props
+}
@@ -0,0 +1,74 @@
+package postgresql
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestRenameColumns(t *testing.T) {
+	row := map[string]interface{}{"user_id": 1, "user_name": "a"}
+
+	if result := renameColumns(row, nil); !reflect.DeepEqual(result, row) {
+		t.Errorf("expected row unchanged, got %v", result)
+	}
+
+	result := renameColumns(row, func(column string) string { return strings.TrimPrefix(column, "user_") })
+	if expected := map[string]interface{}{"id": 1, "name": "a"}; !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+}
+
+func TestColumnProperties(t *testing.T) {
+	result := columnProperties(map[string]interface{}{"name": "a", "id": 1})
+	expected := []adapter.PropertyMapping{
+		{ObjectField: "id", DataField: "id"},
+		{ObjectField: "name", DataField: "name"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+}
+
+func TestPostgreSQLAdapter_InsertFromAdapterWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	op := &adapter.Operation{Statement: "users"}
+	if _, err := a.InsertFromAdapter(context.Background(), op, NewPostgreSQLAdapter(), op, nil); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_InsertFromAdapter(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, `CREATE TABLE insert_from_src (src_id int, src_name text);
+		INSERT INTO insert_from_src VALUES (1, 'a'), (2, 'b');
+		CREATE TABLE insert_from_dest (id int PRIMARY KEY, name text)`); err != nil {
+		t.Fatalf("failed to create tables: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE insert_from_src, insert_from_dest") })
+
+	ctx = WithOperationOptions(ctx, OperationOptions{
+		ColumnTransform: func(column string) string { return strings.TrimPrefix(column, "src_") },
+	})
+	srcOp := &adapter.Operation{Statement: "SELECT src_id, src_name FROM insert_from_src"}
+	n, err := a.InsertFromAdapter(ctx, &adapter.Operation{Statement: "insert_from_dest"}, a, srcOp, nil)
+	if err != nil {
+		t.Fatalf("InsertFromAdapter failed: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 rows, got %d", n)
+	}
+
+	var names string
+	if err := a.db.QueryRowContext(context.Background(), "SELECT string_agg(name, ',' ORDER BY id) FROM insert_from_dest").Scan(&names); err != nil {
+		t.Fatalf("select failed: %v", err)
+	}
+	if names != "a,b" {
+		t.Errorf("expected a,b, got %q", names)
+	}
+}
@@ -0,0 +1,175 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// InsertMode selects how Insert sends rows to the server.
+type InsertMode int
+
+// Insert modes accepted by ForceInsertMode.
+const (
+	// InsertModeAuto uses multi-row INSERT ... VALUES below the bulk insert
+	// threshold and COPY from it on. It is the default.
+	InsertModeAuto InsertMode = iota
+	// InsertModeValues always uses INSERT ... VALUES.
+	InsertModeValues
+	// InsertModeCopy uses COPY whenever the insert allows it.
+	InsertModeCopy
+)
+
+// defaultBulkInsertThreshold is the default of ConfigBulkInsertThreshold.
+const defaultBulkInsertThreshold = 500
+
+// stagingTable is the temporary table used by insertWithStaging.
+const stagingTable = "toutago_insert_staging"
+
+// ForceInsertMode overrides the choice Insert makes between INSERT ... VALUES
+// and COPY, mainly so tests can exercise both paths with small inputs.
+//
+// COPY is only used for plain inserts: upserts (OperationOptions.Upsert)
+// and inserts leaving every column to its default always use VALUES, as
+// does a transaction on the pgx driver. With generated columns, rows are
+// copied into a temporary staging table and moved with
+// INSERT ... SELECT ... RETURNING; this needs the lib/pq driver, and pgx
+// falls back to VALUES.
+func ForceInsertMode(mode InsertMode) Option {
+	return func(a *PostgreSQLAdapter) {
+		a.insertMode = mode
+	}
+}
+
+// copyInsertProperties returns the properties written by an insert of n
+// objects for op and whether the insert should use COPY.
+func (a *PostgreSQLAdapter) copyInsertProperties(ctx context.Context, op *adapter.Operation, n int) ([]adapter.PropertyMapping, bool) {
+	props := insertProperties(ctx, op)
+
+	switch {
+	case len(props) == 0, OperationOptionsFromContext(ctx).Upsert != nil:
+		return props, false
+	case a.txFromContext(ctx) != nil && a.driverName == DriverPGX:
+		return props, false
+	case a.insertMode == InsertModeValues:
+		return props, false
+	case a.insertMode == InsertModeCopy:
+		return props, true
+	default:
+		return props, a.bulkInsertThreshold > 0 && n >= a.bulkInsertThreshold
+	}
+}
+
+// insertCopy inserts objects with CopyInsert.
+func (a *PostgreSQLAdapter) insertCopy(ctx context.Context, op *adapter.Operation, props []adapter.PropertyMapping, objects []interface{}) error {
+	columns, rows, err := copyRows(props, objects, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.CopyInsert(ctx, op.Statement, columns, rows)
+	return err
+}
+
+// insertWithStaging copies objects into a temporary table and moves them
+// into the target with a single INSERT ... SELECT ... RETURNING, assigning
+// the generated values back in the order the objects were copied.
+func (a *PostgreSQLAdapter) insertWithStaging(ctx context.Context, op *adapter.Operation, props []adapter.PropertyMapping, objects []interface{}) error {
+	columns, rows, err := copyRows(props, objects, true)
+	if err != nil {
+		return err
+	}
+
+	// COPY and the temporary table must share a transaction: lib/pq only
+	// copies inside one.
+	if tx := a.txFromContext(ctx); tx != nil {
+		return a.stageAndInsert(ctx, tx.tx, op, columns, rows, objects)
+	}
+
+	tx, err := a.beginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("postgresql: failed to begin insert: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := a.stageAndInsert(ctx, tx, op, columns, rows, objects); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("postgresql: failed to commit insert: %w", err)
+	}
+	return nil
+}
+
+// stageAndInsert runs the statements of insertWithStaging on q.
+func (a *PostgreSQLAdapter) stageAndInsert(ctx context.Context, q querier, op *adapter.Operation, columns []string, rows [][]interface{}, objects []interface{}) error {
+	dataColumns := strings.Join(columns[:len(columns)-1], ", ")
+	name := operationName(op, "insert")
+
+	create := fmt.Sprintf("CREATE TEMP TABLE %s ON COMMIT DROP AS SELECT %s, NULL::bigint AS _ord FROM %s WITH NO DATA",
+		stagingTable, dataColumns, QuoteIdentifier(op.Statement))
+	if _, err := q.ExecContext(ctx, a.annotate(create, name)); err != nil {
+		return fmt.Errorf("postgresql: failed to create staging table: %w", err)
+	}
+	// Drop it right away so a later insert in the same transaction can
+	// create it again.
+	defer func() { _, _ = q.ExecContext(ctx, "DROP TABLE IF EXISTS "+stagingTable) }()
+
+	if _, err := copyPqOn(ctx, q, stagingTable, columns, rows); err != nil {
+		return fmt.Errorf("postgresql: copy into staging table failed: %w", err)
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s ORDER BY _ord RETURNING %s",
+		QuoteIdentifier(op.Statement), dataColumns, dataColumns, stagingTable,
+		strings.Join(returningColumns(op), ", "))
+	result, err := q.QueryContext(ctx, a.annotate(insert, name))
+	if err != nil {
+		return fmt.Errorf("postgresql: insert from staging table failed: %w", err)
+	}
+	defer func() { _ = result.Close() }()
+
+	i := 0
+	for result.Next() {
+		if i >= len(objects) {
+			return fmt.Errorf("postgresql: insert returned more rows than the %d inserted", len(objects))
+		}
+		if err := scanGenerated(result, op, objects[i].(map[string]interface{})); err != nil {
+			return err
+		}
+		i++
+	}
+	if err := result.Err(); err != nil {
+		return fmt.Errorf("postgresql: insert from staging table failed: %w", err)
+	}
+	if i != len(objects) {
+		return fmt.Errorf("postgresql: insert returned %d rows for %d objects", i, len(objects))
+	}
+	return nil
+}
+
+// copyRows returns the COPY columns and rows for objects. With ordinal set,
+// a trailing _ord column numbers the rows.
+func copyRows(props []adapter.PropertyMapping, objects []interface{}, ordinal bool) ([]string, [][]interface{}, error) {
+	columns := make([]string, len(props), len(props)+1)
+	for i, prop := range props {
+		columns[i] = prop.DataField
+	}
+	if ordinal {
+		columns = append(columns, "_ord")
+	}
+
+	rows := make([][]interface{}, len(objects))
+	for i, objInterface := range objects {
+		args, err := objectArgs(objInterface.(map[string]interface{}), props)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ordinal {
+			args = append(args, int64(i))
+		}
+		rows[i] = args
+	}
+	return columns, rows, nil
+}
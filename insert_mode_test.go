@@ -0,0 +1,101 @@
+package postgresql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestPostgreSQLAdapter_CopyInsertProperties(t *testing.T) {
+	op := &adapter.Operation{
+		Statement:  "users",
+		Properties: []adapter.PropertyMapping{{ObjectField: "Name", DataField: "name"}},
+	}
+	upsert := WithOperationOptions(context.Background(), OperationOptions{
+		Upsert: &UpsertOptions{ConflictColumns: []string{"name"}},
+	})
+	allDefaults := WithOperationOptions(context.Background(), OperationOptions{UseDefault: []string{"name"}})
+
+	tests := []struct {
+		name     string
+		adapter  *PostgreSQLAdapter
+		ctx      context.Context
+		n        int
+		expected bool
+	}{
+		{"below threshold", NewPostgreSQLAdapter(), context.Background(), 499, false},
+		{"at threshold", NewPostgreSQLAdapter(), context.Background(), 500, true},
+		{"forced values", NewPostgreSQLAdapter(ForceInsertMode(InsertModeValues)), context.Background(), 10000, false},
+		{"forced copy", NewPostgreSQLAdapter(ForceInsertMode(InsertModeCopy)), context.Background(), 1, true},
+		{"upsert", NewPostgreSQLAdapter(ForceInsertMode(InsertModeCopy)), upsert, 1000, false},
+		{"all defaults", NewPostgreSQLAdapter(ForceInsertMode(InsertModeCopy)), allDefaults, 1000, false},
+		{"disabled threshold", &PostgreSQLAdapter{}, context.Background(), 1000, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, result := tt.adapter.copyInsertProperties(tt.ctx, op, tt.n); result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCopyRows(t *testing.T) {
+	props := []adapter.PropertyMapping{
+		{ObjectField: "Name", DataField: "name"},
+		{ObjectField: "Meta", DataField: "meta"},
+	}
+	objects := []interface{}{
+		map[string]interface{}{"Name": "a", "Meta": map[string]interface{}{"k": 1}},
+		map[string]interface{}{"Name": "b"},
+	}
+
+	columns, rows, err := copyRows(props, objects, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(columns, []string{"name", "meta", "_ord"}) {
+		t.Errorf("unexpected columns %v", columns)
+	}
+	expected := [][]interface{}{{"a", `{"k":1}`, int64(0)}, {"b", nil, int64(1)}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected %v, got %v", expected, rows)
+	}
+}
+
+func TestPostgreSQLAdapter_InsertCopyModes(t *testing.T) {
+	for _, mode := range []InsertMode{InsertModeValues, InsertModeCopy} {
+		a := newIntegrationAdapter(t, ForceInsertMode(mode))
+		ctx := context.Background()
+
+		if _, err := a.db.ExecContext(ctx, "CREATE TABLE insert_mode_test (id serial PRIMARY KEY, name text NOT NULL)"); err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+
+		op := &adapter.Operation{
+			Statement:  "insert_mode_test",
+			Properties: []adapter.PropertyMapping{{ObjectField: "Name", DataField: "name"}},
+			Generated:  []adapter.PropertyMapping{{ObjectField: "ID", DataField: "id"}},
+		}
+		objects := []interface{}{
+			map[string]interface{}{"Name": "a"},
+			map[string]interface{}{"Name": "b"},
+		}
+		if err := a.Insert(ctx, op, objects); err != nil {
+			t.Fatalf("mode %d: insert failed: %v", mode, err)
+		}
+		if id := objects[1].(map[string]interface{})["ID"]; id != int64(2) {
+			t.Errorf("mode %d: expected second object to get id 2, got %v", mode, id)
+		}
+
+		op.Generated = nil
+		if err := a.Insert(ctx, op, []interface{}{map[string]interface{}{"Name": "c"}}); err != nil {
+			t.Fatalf("mode %d: insert without returning failed: %v", mode, err)
+		}
+
+		_, _ = a.db.Exec("DROP TABLE insert_mode_test")
+	}
+}
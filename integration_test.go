@@ -0,0 +1,33 @@
+package postgresql
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+)
+
+// newIntegrationAdapter returns an adapter connected to the database named by
+// POSTGRES_TEST_DSN, skipping the test when the variable is unset.
+func newIntegrationAdapter(t *testing.T, opts ...Option) *PostgreSQLAdapter {
+	t.Helper()
+
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set; skipping integration test")
+	}
+
+	a := NewPostgreSQLAdapter(opts...)
+	db, err := sql.Open(a.driverName, dsn)
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		t.Fatalf("failed to ping test database: %v", err)
+	}
+
+	a.db = db
+	a.dsn = dsn
+	t.Cleanup(func() { _ = a.Close() })
+	return a
+}
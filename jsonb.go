@@ -0,0 +1,87 @@
+package postgresql
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// JSONBScanner is an sql.Scanner for json and jsonb columns. By default it
+// stores the document as a json.RawMessage; with Unmarshal set it decodes it
+// instead, so objects become map[string]interface{} and arrays
+// []interface{}. A NULL column scans to a nil Value.
+type JSONBScanner struct {
+	// Unmarshal selects decoding the document rather than keeping it raw.
+	Unmarshal bool
+	// Value holds the json.RawMessage or decoded document after Scan.
+	Value interface{}
+}
+
+// Scan implements sql.Scanner.
+func (s *JSONBScanner) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		s.Value = nil
+		return nil
+	case []byte:
+		// The driver may reuse src, so keep a copy.
+		data = append([]byte(nil), v...)
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("postgresql: cannot scan %T into JSONBScanner", src)
+	}
+
+	if !s.Unmarshal {
+		s.Value = json.RawMessage(data)
+		return nil
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("postgresql: invalid JSON: %w", err)
+	}
+	s.Value = doc
+	return nil
+}
+
+// isJSONType reports whether a database type name denotes json or jsonb.
+func isJSONType(typeName string) bool {
+	switch strings.ToUpper(typeName) {
+	case "JSON", "JSONB":
+		return true
+	}
+	return false
+}
+
+// bindArg prepares a value for binding as a statement parameter. Maps,
+// structs and json.RawMessage values are sent as JSON text so they can be
+// written to json and jsonb columns. Values implementing driver.Valuer and
+// time.Time are passed through unchanged, as is everything else.
+func bindArg(v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case nil, driver.Valuer, time.Time, *time.Time:
+		return v, nil
+	case json.RawMessage:
+		return string(val), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	kind := rv.Kind()
+	if kind == reflect.Pointer && !rv.IsNil() {
+		kind = rv.Elem().Kind()
+	}
+	if kind != reflect.Map && kind != reflect.Struct {
+		return v, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode %T as JSON: %w", v, err)
+	}
+	return string(data), nil
+}
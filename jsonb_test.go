@@ -0,0 +1,129 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestJSONBScanner_Scan(t *testing.T) {
+	raw := &JSONBScanner{}
+	if err := raw.Scan([]byte(`{"a":1}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(raw.Value, json.RawMessage(`{"a":1}`)) {
+		t.Errorf("expected raw message, got %#v", raw.Value)
+	}
+
+	decoded := &JSONBScanner{Unmarshal: true}
+	if err := decoded.Scan(`{"a":1,"b":["x"]}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{"a": float64(1), "b": []interface{}{"x"}}
+	if !reflect.DeepEqual(decoded.Value, expected) {
+		t.Errorf("expected %#v, got %#v", expected, decoded.Value)
+	}
+
+	if err := decoded.Scan(nil); err != nil || decoded.Value != nil {
+		t.Errorf("expected nil value for NULL, got %#v, %v", decoded.Value, err)
+	}
+	if err := decoded.Scan([]byte(`{bad`)); err == nil {
+		t.Error("expected error for invalid JSON, got nil")
+	}
+	if err := decoded.Scan(42); err == nil {
+		t.Error("expected error for unsupported source type, got nil")
+	}
+}
+
+func TestJSONBScanner_CopiesSource(t *testing.T) {
+	src := []byte(`{"a":1}`)
+	s := &JSONBScanner{}
+	if err := s.Scan(src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	src[2] = 'z'
+	if string(s.Value.(json.RawMessage)) != `{"a":1}` {
+		t.Errorf("expected the scanned value to be independent of the source, got %s", s.Value)
+	}
+}
+
+func TestBindArg(t *testing.T) {
+	type profile struct {
+		Name string `json:"name"`
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected interface{}
+	}{
+		{"nil", nil, nil},
+		{"int", 42, 42},
+		{"string", "x", "x"},
+		{"bytes", []byte("x"), []byte("x")},
+		{"map", map[string]interface{}{"a": 1}, `{"a":1}`},
+		{"struct", profile{Name: "Ann"}, `{"name":"Ann"}`},
+		{"struct pointer", &profile{Name: "Ann"}, `{"name":"Ann"}`},
+		{"raw message", json.RawMessage(`[1,2]`), `[1,2]`},
+		{"time", now, now},
+		{"valuer", pq.Int64Array{1, 2}, pq.Int64Array{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := bindArg(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %#v, got %#v", tt.expected, result)
+			}
+		})
+	}
+
+	if _, err := bindArg(map[string]interface{}{"f": func() {}}); err == nil {
+		t.Error("expected error for a value JSON cannot encode, got nil")
+	}
+}
+
+func TestWithJSONUnmarshal(t *testing.T) {
+	if !NewPostgreSQLAdapter(WithJSONUnmarshal(true)).jsonUnmarshal {
+		t.Error("expected JSON unmarshalling to be enabled")
+	}
+}
+
+func TestPostgreSQLAdapter_JSONBRoundTrip(t *testing.T) {
+	a := newIntegrationAdapter(t, WithJSONUnmarshal(true))
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, "CREATE TABLE jsonb_test (id int PRIMARY KEY, doc jsonb)"); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE jsonb_test") })
+
+	insert := &adapter.Operation{
+		Statement: "jsonb_test",
+		Properties: []adapter.PropertyMapping{
+			{ObjectField: "ID", DataField: "id"},
+			{ObjectField: "Doc", DataField: "doc"},
+		},
+	}
+	doc := map[string]interface{}{"tags": []interface{}{"a", "b"}}
+	if err := a.Insert(ctx, insert, []interface{}{map[string]interface{}{"ID": 1, "Doc": doc}}); err != nil {
+		t.Fatalf("insert failed: %v", err)
+	}
+
+	results, err := a.Fetch(ctx, &adapter.Operation{Statement: "SELECT doc FROM jsonb_test WHERE id = 1"}, nil)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if got := results[0].(map[string]interface{})["doc"]; !reflect.DeepEqual(got, doc) {
+		t.Errorf("expected %#v, got %#v", doc, got)
+	}
+}
@@ -0,0 +1,87 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// keyedStatement adds "WHERE col1 = {Field1} AND col2 = {Field2}" on the
+// key columns of op.Identifier to an UPDATE or DELETE statement that has no
+// WHERE clause of its own, placing it before any RETURNING clause.
+// Statements with a WHERE clause, or operations without identifiers, are
+// returned unchanged.
+func keyedStatement(statement string, keys []adapter.PropertyMapping) string {
+	if len(keys) == 0 || lastMatch(whereKeyword, statement) != nil {
+		return statement
+	}
+
+	statement = strings.TrimRight(strings.TrimSpace(statement), ";")
+	returning := ""
+	if r := lastMatch(returningKeyword, statement); r != nil {
+		statement, returning = strings.TrimSpace(statement[:r[0]]), " "+statement[r[0]:]
+	}
+
+	conditions := make([]string, len(keys))
+	for i, key := range keys {
+		conditions[i] = fmt.Sprintf("%s = {%s}", key.DataField, key.ObjectField)
+	}
+	return statement + " WHERE " + strings.Join(conditions, " AND ") + returning
+}
+
+// identifierParams turns a Delete identifier into statement parameters: a map
+// is used as is, a []interface{} supplies the key values in keys order, and
+// any other value is bound to {id} and, for a single key, to that key's
+// field.
+func identifierParams(id interface{}, keys []adapter.PropertyMapping) (map[string]interface{}, error) {
+	switch v := id.(type) {
+	case map[string]interface{}:
+		return v, nil
+	case []interface{}:
+		if len(v) != len(keys) {
+			return nil, fmt.Errorf("postgresql: identifier has %d values for %d key columns", len(v), len(keys))
+		}
+		params := make(map[string]interface{}, len(keys))
+		for i, key := range keys {
+			params[key.ObjectField] = v[i]
+		}
+		return params, nil
+	}
+
+	params := map[string]interface{}{"id": id}
+	if len(keys) == 1 {
+		params[keys[0].ObjectField] = id
+	}
+	return params, nil
+}
+
+// singleKey returns the single key of op.Identifier, or "id" when it is
+// empty, for operations that cannot handle composite keys.
+func singleKey(op *adapter.Operation, operation string) (adapter.PropertyMapping, error) {
+	switch len(op.Identifier) {
+	case 0:
+		return adapter.PropertyMapping{ObjectField: "id", DataField: "id"}, nil
+	case 1:
+		return op.Identifier[0], nil
+	default:
+		return adapter.PropertyMapping{}, fmt.Errorf("postgresql: %s does not support composite keys", operation)
+	}
+}
+
+// keyValues returns the key value of each identifier, which is either the
+// value itself or an object map holding it under key.ObjectField.
+func keyValues(identifiers []interface{}, key adapter.PropertyMapping) ([]interface{}, error) {
+	ids := make([]interface{}, len(identifiers))
+	for i, id := range identifiers {
+		if obj, ok := id.(map[string]interface{}); ok {
+			v, ok := obj[key.ObjectField]
+			if !ok {
+				return nil, fmt.Errorf("postgresql: identifier %d has no field %s", i, key.ObjectField)
+			}
+			id = v
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
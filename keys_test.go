@@ -0,0 +1,156 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+var compositeKey = []adapter.PropertyMapping{
+	{ObjectField: "TenantID", DataField: "tenant_id"},
+	{ObjectField: "UserID", DataField: "user_id"},
+}
+
+func TestKeyedStatement(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		keys      []adapter.PropertyMapping
+		expected  string
+	}{
+		{
+			name:      "no keys",
+			statement: "DELETE FROM members",
+			expected:  "DELETE FROM members",
+		},
+		{
+			name:      "existing where",
+			statement: "DELETE FROM members WHERE id = {id}",
+			keys:      compositeKey,
+			expected:  "DELETE FROM members WHERE id = {id}",
+		},
+		{
+			name:      "composite delete",
+			statement: "DELETE FROM members;",
+			keys:      compositeKey,
+			expected:  "DELETE FROM members WHERE tenant_id = {TenantID} AND user_id = {UserID}",
+		},
+		{
+			name:      "update with returning",
+			statement: "UPDATE members SET role = {Role} RETURNING role",
+			keys:      compositeKey,
+			expected:  "UPDATE members SET role = {Role} WHERE tenant_id = {TenantID} AND user_id = {UserID} RETURNING role",
+		},
+		{
+			name:      "where in subquery only",
+			statement: "UPDATE members SET role = (SELECT name FROM roles WHERE id = {RoleID})",
+			keys:      compositeKey[:1],
+			expected:  "UPDATE members SET role = (SELECT name FROM roles WHERE id = {RoleID}) WHERE tenant_id = {TenantID}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := keyedStatement(tt.statement, tt.keys); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestIdentifierParams(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        interface{}
+		keys      []adapter.PropertyMapping
+		expected  map[string]interface{}
+		expectErr bool
+	}{
+		{
+			name:     "scalar",
+			id:       7,
+			expected: map[string]interface{}{"id": 7},
+		},
+		{
+			name:     "scalar single key",
+			id:       7,
+			keys:     compositeKey[1:],
+			expected: map[string]interface{}{"id": 7, "UserID": 7},
+		},
+		{
+			name:     "map",
+			id:       map[string]interface{}{"TenantID": 1, "UserID": 2},
+			keys:     compositeKey,
+			expected: map[string]interface{}{"TenantID": 1, "UserID": 2},
+		},
+		{
+			name:     "slice",
+			id:       []interface{}{1, 2},
+			keys:     compositeKey,
+			expected: map[string]interface{}{"TenantID": 1, "UserID": 2},
+		},
+		{
+			name:      "slice length mismatch",
+			id:        []interface{}{1},
+			keys:      compositeKey,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := identifierParams(tt.id, tt.keys)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_CompositeKey(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, `CREATE TABLE composite_key_test (
+			tenant_id int, user_id int, role text, PRIMARY KEY (tenant_id, user_id));
+		INSERT INTO composite_key_test VALUES (1, 1, 'a'), (1, 2, 'b'), (2, 1, 'c')`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE composite_key_test") })
+
+	update := &adapter.Operation{
+		Statement:  "UPDATE composite_key_test SET role = {Role}",
+		Identifier: compositeKey,
+	}
+	if err := a.Update(ctx, update, []interface{}{map[string]interface{}{"TenantID": 1, "UserID": 2, "Role": "x"}}); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+
+	remove := &adapter.Operation{Statement: "DELETE FROM composite_key_test", Identifier: compositeKey}
+	if err := a.Delete(ctx, remove, []interface{}{[]interface{}{2, 1}}); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if err := a.Delete(ctx, remove, []interface{}{map[string]interface{}{"TenantID": 2, "UserID": 1}}); !errors.Is(err, adapter.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a deleted row, got %v", err)
+	}
+
+	var rows string
+	if err := a.db.QueryRowContext(ctx, "SELECT string_agg(tenant_id || '/' || user_id || ':' || role, ',' ORDER BY tenant_id, user_id) FROM composite_key_test").Scan(&rows); err != nil {
+		t.Fatalf("failed to read rows: %v", err)
+	}
+	if rows != "1/1:a,1/2:x" {
+		t.Errorf("unexpected rows: %s", rows)
+	}
+}
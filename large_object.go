@@ -0,0 +1,203 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Large object access modes, as in libpq's libpq-fs.h.
+const (
+	largeObjectWrite = 0x20000
+	largeObjectRead  = 0x40000
+)
+
+// largeObjectChunkSize is the number of bytes moved per lowrite or loread
+// call.
+const largeObjectChunkSize = 256 * 1024
+
+// CreateLargeObject stores everything read from r as a new large object and
+// returns its OID. Large objects hold up to 4TB and are read back in chunks
+// with OpenLargeObject, so neither side needs the whole value in memory.
+//
+// Large object descriptors only live inside a transaction: the object is
+// written in the transaction carried by ctx (see WithTx), or in one the
+// adapter opens and commits itself.
+func (a *PostgreSQLAdapter) CreateLargeObject(ctx context.Context, r io.Reader) (oid uint32, err error) {
+	if a.db == nil {
+		return 0, ErrNotConnected
+	}
+
+	tx, owned, err := a.largeObjectTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if owned {
+		defer func() {
+			if err != nil {
+				_ = tx.Rollback()
+			}
+		}()
+	}
+
+	if err := tx.QueryRowContext(ctx, "SELECT lo_create(0)").Scan(&oid); err != nil {
+		return 0, fmt.Errorf("postgresql: failed to create large object: %w", err)
+	}
+	fd, err := openLargeObject(ctx, tx, oid, largeObjectWrite)
+	if err != nil {
+		return 0, err
+	}
+
+	buf := make([]byte, largeObjectChunkSize)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			if _, err := tx.ExecContext(ctx, "SELECT lowrite($1, $2)", fd, buf[:n]); err != nil {
+				return 0, fmt.Errorf("postgresql: failed to write large object %d: %w", oid, err)
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return 0, fmt.Errorf("postgresql: failed to read large object data: %w", readErr)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "SELECT lo_close($1)", fd); err != nil {
+		return 0, fmt.Errorf("postgresql: failed to close large object %d: %w", oid, err)
+	}
+	if owned {
+		if err := tx.Commit(); err != nil {
+			return 0, fmt.Errorf("postgresql: failed to commit large object %d: %w", oid, err)
+		}
+	}
+	return oid, nil
+}
+
+// OpenLargeObject returns a reader for the large object with the given OID.
+// The object is read in the transaction carried by ctx, or in one the
+// adapter opens and holds, with its connection, until the reader is
+// closed; ctx must stay valid until then.
+func (a *PostgreSQLAdapter) OpenLargeObject(ctx context.Context, oid uint32) (io.ReadCloser, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	tx, owned, err := a.largeObjectTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := openLargeObject(ctx, tx, oid, largeObjectRead)
+	if err != nil {
+		if owned {
+			_ = tx.Rollback()
+		}
+		return nil, err
+	}
+	return &largeObjectReader{ctx: ctx, tx: tx, owned: owned, oid: oid, fd: fd}, nil
+}
+
+// DeleteLargeObject removes the large object with the given OID, in the
+// transaction carried by ctx, or in one the adapter opens and commits
+// itself.
+func (a *PostgreSQLAdapter) DeleteLargeObject(ctx context.Context, oid uint32) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	tx, owned, err := a.largeObjectTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	var result int
+	if err := tx.QueryRowContext(ctx, "SELECT lo_unlink($1)", oid).Scan(&result); err != nil {
+		if owned {
+			_ = tx.Rollback()
+		}
+		return fmt.Errorf("postgresql: failed to delete large object %d: %w", oid, err)
+	}
+	if owned {
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("postgresql: failed to commit large object %d deletion: %w", oid, err)
+		}
+	}
+	return nil
+}
+
+// largeObjectTx returns the transaction carried by ctx, or begins one and
+// reports that the caller owns it.
+func (a *PostgreSQLAdapter) largeObjectTx(ctx context.Context) (*sql.Tx, bool, error) {
+	if ptx := a.txFromContext(ctx); ptx != nil {
+		return ptx.tx, false, nil
+	}
+	tx, err := a.beginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("postgresql: failed to begin transaction: %w", err)
+	}
+	return tx, true, nil
+}
+
+// openLargeObject opens oid in mode and returns its descriptor.
+func openLargeObject(ctx context.Context, tx *sql.Tx, oid uint32, mode int) (int32, error) {
+	var fd int32
+	if err := tx.QueryRowContext(ctx, "SELECT lo_open($1, $2)", oid, mode).Scan(&fd); err != nil {
+		return 0, fmt.Errorf("postgresql: failed to open large object %d: %w", oid, err)
+	}
+	return fd, nil
+}
+
+// largeObjectReader reads a large object through loread.
+type largeObjectReader struct {
+	ctx    context.Context
+	tx     *sql.Tx
+	owned  bool
+	oid    uint32
+	fd     int32
+	closed bool
+}
+
+// Read reads up to len(p) bytes of the object.
+func (r *largeObjectReader) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, fmt.Errorf("postgresql: large object %d is closed", r.oid)
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	size := len(p)
+	if size > largeObjectChunkSize {
+		size = largeObjectChunkSize
+	}
+	var data []byte
+	if err := r.tx.QueryRowContext(r.ctx, "SELECT loread($1, $2)", r.fd, size).Scan(&data); err != nil {
+		return 0, fmt.Errorf("postgresql: failed to read large object %d: %w", r.oid, err)
+	}
+	if len(data) == 0 {
+		return 0, io.EOF
+	}
+	return copy(p, data), nil
+}
+
+// Close closes the descriptor and ends the transaction the adapter opened
+// for the reader.
+func (r *largeObjectReader) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	_, err := r.tx.ExecContext(r.ctx, "SELECT lo_close($1)", r.fd)
+	if r.owned {
+		// Nothing was written, so rolling back only releases the connection.
+		_ = r.tx.Rollback()
+	}
+	if err != nil {
+		return fmt.Errorf("postgresql: failed to close large object %d: %w", r.oid, err)
+	}
+	return nil
+}
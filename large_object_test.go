@@ -0,0 +1,61 @@
+package postgresql
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestPostgreSQLAdapter_LargeObjectsWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	ctx := context.Background()
+
+	if _, err := a.CreateLargeObject(ctx, bytes.NewReader(nil)); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("CreateLargeObject: expected ErrNotConnected, got %v", err)
+	}
+	if _, err := a.OpenLargeObject(ctx, 1); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("OpenLargeObject: expected ErrNotConnected, got %v", err)
+	}
+	if err := a.DeleteLargeObject(ctx, 1); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("DeleteLargeObject: expected ErrNotConnected, got %v", err)
+	}
+}
+
+func TestPostgreSQLAdapter_LargeObjects(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	// Larger than one chunk, so both loops run more than once.
+	data := bytes.Repeat([]byte("0123456789abcdef"), largeObjectChunkSize/8)
+	oid, err := a.CreateLargeObject(ctx, bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("CreateLargeObject failed: %v", err)
+	}
+
+	r, err := a.OpenLargeObject(ctx, oid)
+	if err != nil {
+		t.Fatalf("OpenLargeObject failed: %v", err)
+	}
+	read, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	if !bytes.Equal(read, data) {
+		t.Errorf("expected %d bytes back, got %d", len(data), len(read))
+	}
+
+	if err := a.DeleteLargeObject(ctx, oid); err != nil {
+		t.Fatalf("DeleteLargeObject failed: %v", err)
+	}
+	if _, err := a.OpenLargeObject(ctx, oid); err == nil {
+		t.Error("expected error opening a deleted large object, got nil")
+	}
+	if err := a.DeleteLargeObject(ctx, oid); err == nil {
+		t.Error("expected error deleting a deleted large object, got nil")
+	}
+}
@@ -0,0 +1,106 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Reconnect back-off bounds of the connection opened by Listen.
+const (
+	listenMinReconnect = time.Second
+	listenMaxReconnect = time.Minute
+)
+
+// Listen subscribes to channel on a dedicated connection outside the pool and
+// calls handler with the payload of every notification, in the order they
+// arrive, from a background goroutine. It returns once the subscription is
+// active. The connection is re-established automatically if it drops;
+// notifications sent while it was down are lost. Cancelling ctx stops the
+// goroutine and closes the connection.
+//
+// The listener always uses lib/pq, whichever driver the pool uses.
+func (a *PostgreSQLAdapter) Listen(ctx context.Context, channel string, handler func(payload string)) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	dsn, err := a.connectDSN(ctx)
+	if err != nil {
+		return err
+	}
+
+	connected := make(chan error, 1)
+	var dialer pq.Dialer = pqDialer{(&net.Dialer{}).DialContext}
+	if a.dialer != nil {
+		dialer = pqDialer{a.dialer}
+	}
+	listener := pq.NewDialListener(dialer, dsn, listenMinReconnect, listenMaxReconnect, func(ev pq.ListenerEventType, err error) {
+		switch ev {
+		case pq.ListenerEventConnected:
+			select {
+			case connected <- nil:
+			default:
+			}
+		case pq.ListenerEventConnectionAttemptFailed:
+			select {
+			case connected <- err:
+			default:
+			}
+		case pq.ListenerEventDisconnected:
+			slog.Warn("postgresql: listener connection lost", "channel", channel, "error", err)
+		}
+	})
+
+	select {
+	case err := <-connected:
+		if err != nil {
+			_ = listener.Close()
+			return fmt.Errorf("postgresql: failed to open listener connection: %w", err)
+		}
+	case <-ctx.Done():
+		_ = listener.Close()
+		return ctx.Err()
+	}
+
+	if err := listener.Listen(channel); err != nil {
+		_ = listener.Close()
+		return fmt.Errorf("postgresql: failed to listen on %s: %w", channel, err)
+	}
+
+	go func() {
+		defer func() { _ = listener.Close() }()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case n, ok := <-listener.Notify:
+				if !ok {
+					return
+				}
+				// A nil notification marks a reconnect.
+				if n != nil {
+					handler(n.Extra)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Notify sends payload to every session listening on channel.
+func (a *PostgreSQLAdapter) Notify(ctx context.Context, channel, payload string) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	if _, err := a.execContext(ctx, "notify", "SELECT pg_notify($1, $2)", channel, payload); err != nil {
+		return fmt.Errorf("postgresql: failed to notify %s: %w", channel, err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestPostgreSQLAdapter_ListenNotifyWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	ctx := context.Background()
+
+	if err := a.Listen(ctx, "events", func(string) {}); err == nil {
+		t.Error("Listen: expected error when not connected, got nil")
+	}
+	if err := a.Notify(ctx, "events", "hello"); err == nil {
+		t.Error("Notify: expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_ListenNotify(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan string, 1)
+	if err := a.Listen(ctx, "listen_test", func(payload string) { received <- payload }); err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	if err := a.Notify(ctx, "listen_test", "hello"); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+
+	select {
+	case payload := <-received:
+		if payload != "hello" {
+			t.Errorf("expected payload %q, got %q", "hello", payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for notification")
+	}
+}
+
+func TestPostgreSQLAdapter_ListenConnectionFailure(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer db.Close()
+	a.db = db
+	a.dsn = "host=127.0.0.1 port=1 sslmode=disable connect_timeout=1"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := a.Listen(ctx, "events", func(string) {}); err == nil {
+		t.Error("expected error for unreachable server, got nil")
+	}
+}
@@ -0,0 +1,39 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LockMode selects the row-level lock Fetch takes on the rows it reads.
+type LockMode string
+
+// Lock modes accepted in OperationOptions.LockMode.
+const (
+	// LockNone reads rows without locking them, the default.
+	LockNone LockMode = "none"
+	// LockForUpdate appends FOR UPDATE, blocking concurrent updates,
+	// deletes and other locking reads of the rows.
+	LockForUpdate LockMode = "update"
+	// LockForShare appends FOR SHARE, blocking concurrent updates and
+	// deletes but not other shared reads.
+	LockForShare LockMode = "share"
+)
+
+// lockStatement appends the locking clause for mode to a SELECT statement.
+// Row locks last until the end of the transaction, so outside WithTx they
+// are released as soon as the statement finishes.
+func lockStatement(statement string, mode LockMode) (string, error) {
+	var clause string
+	switch mode {
+	case "", LockNone:
+		return statement, nil
+	case LockForUpdate:
+		clause = "FOR UPDATE"
+	case LockForShare:
+		clause = "FOR SHARE"
+	default:
+		return "", fmt.Errorf("postgresql: unknown lock mode %q", mode)
+	}
+	return strings.TrimRight(strings.TrimSpace(statement), ";") + " " + clause, nil
+}
@@ -0,0 +1,69 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestLockStatement(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      LockMode
+		expected  string
+		expectErr bool
+	}{
+		{name: "unset", mode: "", expected: "SELECT * FROM users WHERE id = {id};"},
+		{name: "none", mode: LockNone, expected: "SELECT * FROM users WHERE id = {id};"},
+		{name: "update", mode: LockForUpdate, expected: "SELECT * FROM users WHERE id = {id} FOR UPDATE"},
+		{name: "share", mode: LockForShare, expected: "SELECT * FROM users WHERE id = {id} FOR SHARE"},
+		{name: "unknown", mode: "exclusive", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := lockStatement("SELECT * FROM users WHERE id = {id};", tt.mode)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_FetchForUpdate(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, `CREATE TABLE lock_mode_test (id int PRIMARY KEY);
+		INSERT INTO lock_mode_test VALUES (1)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE lock_mode_test") })
+
+	tx, err := a.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("begin failed: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	lockCtx := WithOperationOptions(WithTx(ctx, tx), OperationOptions{LockMode: LockForUpdate})
+	op := &adapter.Operation{Statement: "SELECT id FROM lock_mode_test WHERE id = {id}"}
+	if _, err := a.Fetch(lockCtx, op, map[string]interface{}{"id": 1}); err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+
+	// The row is locked, so a NOWAIT lock from another session fails.
+	if _, err := a.db.ExecContext(ctx, "SELECT id FROM lock_mode_test WHERE id = 1 FOR UPDATE NOWAIT"); err == nil {
+		t.Error("expected the row to be locked by the transaction")
+	}
+}
@@ -0,0 +1,246 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/lib/pq"
+)
+
+// defaultStandbyStatusInterval is how often StreamLogical reports its
+// position to the server when StreamOptions.StatusInterval is zero.
+const defaultStandbyStatusInterval = 10 * time.Second
+
+// postgresEpoch is the zero point of replication protocol timestamps.
+var postgresEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// StreamOptions configures StreamLogical.
+type StreamOptions struct {
+	// StartLSN is the WAL position to stream from. Zero resumes from the
+	// slot's confirmed position.
+	StartLSN int64
+
+	// PluginArgs are passed to the slot's output plugin, for example
+	// {"proto_version": "1", "publication_names": "app"} for pgoutput or
+	// {"include-timestamp": "1"} for wal2json.
+	PluginArgs map[string]string
+
+	// StatusInterval is how often the position is reported to the server,
+	// which lets it recycle WAL. Defaults to 10 seconds; it must be shorter
+	// than the server's wal_sender_timeout.
+	StatusInterval time.Duration
+
+	// BufferSize is the capacity of the returned channel. Zero means
+	// unbuffered.
+	BufferSize int
+}
+
+// LogicalMessage is one message produced by a logical decoding output
+// plugin.
+type LogicalMessage struct {
+	// LSN is the WAL position the message starts at.
+	LSN int64
+	// Data is the message in the output plugin's format.
+	Data []byte
+	// ServerTime is when the server sent the message.
+	ServerTime time.Time
+}
+
+// StreamLogical streams changes from the logical replication slot slotName
+// over a replication connection, delivering each message as soon as the
+// server decodes it instead of polling pg_logical_slot_get_changes. The slot
+// must already exist; see CheckLogicalReplicationPrerequisites for the
+// server settings it needs.
+//
+// A message counts as consumed, and is confirmed to the server, once it has
+// been received from the channel, so after a crash streaming resumes after
+// the last message handed out. The channel is closed when ctx is cancelled
+// or the connection fails; failures are logged. The replication connection
+// is opened with pgx whichever driver the pool uses.
+func (a *PostgreSQLAdapter) StreamLogical(ctx context.Context, slotName string, opts StreamOptions) (<-chan LogicalMessage, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+	if err := validateIdentifier(slotName); err != nil {
+		return nil, err
+	}
+
+	dsn, err := a.connectDSN(ctx)
+	if err != nil {
+		return nil, err
+	}
+	config, err := pgconn.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: invalid DSN: %w", err)
+	}
+	config.RuntimeParams["replication"] = "database"
+	if a.dialer != nil {
+		config.DialFunc = pgconn.DialFunc(a.dialer)
+		config.LookupFunc = func(ctx context.Context, host string) ([]string, error) {
+			return []string{host}, nil
+		}
+	}
+
+	conn, err := pgconn.ConnectConfig(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to open replication connection: %w", err)
+	}
+	if err := startReplication(ctx, conn, startReplicationQuery(slotName, opts)); err != nil {
+		_ = conn.Close(context.Background())
+		return nil, err
+	}
+
+	interval := opts.StatusInterval
+	if interval <= 0 {
+		interval = defaultStandbyStatusInterval
+	}
+	messages := make(chan LogicalMessage, opts.BufferSize)
+	go func() {
+		defer close(messages)
+		defer func() { _ = conn.Close(context.Background()) }()
+		if err := streamLogical(ctx, conn, opts.StartLSN, interval, messages); err != nil && ctx.Err() == nil {
+			slog.Warn("postgresql: logical replication stream stopped", "slot", slotName, "error", err)
+		}
+	}()
+
+	return messages, nil
+}
+
+// startReplicationQuery renders the START_REPLICATION command.
+func startReplicationQuery(slotName string, opts StreamOptions) string {
+	query := fmt.Sprintf("START_REPLICATION SLOT %s LOGICAL %s", slotName, formatLSN(opts.StartLSN))
+	if len(opts.PluginArgs) == 0 {
+		return query
+	}
+
+	names := make([]string, 0, len(opts.PluginArgs))
+	for name := range opts.PluginArgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := make([]string, len(names))
+	for i, name := range names {
+		args[i] = pq.QuoteIdentifier(name) + " " + pq.QuoteLiteral(opts.PluginArgs[name])
+	}
+	return query + " (" + strings.Join(args, ", ") + ")"
+}
+
+// formatLSN renders a WAL position in the textual pg_lsn form, the inverse
+// of parseLSN.
+func formatLSN(lsn int64) string {
+	return fmt.Sprintf("%X/%X", uint64(lsn)>>32, uint32(lsn))
+}
+
+// startReplication sends query and waits for the server to switch the
+// connection into COPY BOTH mode.
+func startReplication(ctx context.Context, conn *pgconn.PgConn, query string) error {
+	conn.Frontend().Send(&pgproto3.Query{String: query})
+	if err := conn.Frontend().Flush(); err != nil {
+		return fmt.Errorf("postgresql: failed to start replication: %w", err)
+	}
+
+	for {
+		msg, err := conn.ReceiveMessage(ctx)
+		if err != nil {
+			return fmt.Errorf("postgresql: failed to start replication: %w", err)
+		}
+		switch msg := msg.(type) {
+		case *pgproto3.CopyBothResponse:
+			return nil
+		case *pgproto3.ErrorResponse:
+			return fmt.Errorf("postgresql: failed to start replication: %w", pgconn.ErrorResponseToPgError(msg))
+		}
+	}
+}
+
+// streamLogical reads replication messages from conn into messages until
+// ctx is done, reporting the position of the last delivered message every
+// interval and whenever the server asks for it. lsn is the position
+// confirmed so far: the end of the last message received from messages.
+func streamLogical(ctx context.Context, conn *pgconn.PgConn, lsn int64, interval time.Duration, messages chan<- LogicalMessage) error {
+	nextStatus := time.Now().Add(interval)
+	for {
+		if !time.Now().Before(nextStatus) {
+			if err := sendStandbyStatus(conn, lsn); err != nil {
+				return err
+			}
+			nextStatus = time.Now().Add(interval)
+		}
+
+		recvCtx, cancel := context.WithDeadline(ctx, nextStatus)
+		msg, err := conn.ReceiveMessage(recvCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if pgconn.Timeout(err) {
+				continue
+			}
+			return err
+		}
+
+		var data []byte
+		switch msg := msg.(type) {
+		case *pgproto3.CopyData:
+			data = msg.Data
+		case *pgproto3.ErrorResponse:
+			return pgconn.ErrorResponseToPgError(msg)
+		default:
+			continue
+		}
+
+		switch {
+		case len(data) >= 18 && data[0] == 'k':
+			// Primary keepalive: walEnd, serverTime, replyRequested.
+			if data[17] == 1 {
+				if err := sendStandbyStatus(conn, lsn); err != nil {
+					return err
+				}
+				nextStatus = time.Now().Add(interval)
+			}
+		case len(data) >= 25 && data[0] == 'w':
+			// XLogData: walStart, walEnd, serverTime, payload.
+			m := LogicalMessage{
+				LSN:        int64(binary.BigEndian.Uint64(data[1:])),
+				Data:       data[25:],
+				ServerTime: postgresEpoch.Add(time.Duration(int64(binary.BigEndian.Uint64(data[17:]))) * time.Microsecond),
+			}
+			select {
+			case messages <- m:
+				lsn = m.LSN + int64(len(m.Data))
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// sendStandbyStatus reports lsn as written, flushed and applied.
+func sendStandbyStatus(conn *pgconn.PgConn, lsn int64) error {
+	conn.Frontend().Send(&pgproto3.CopyData{Data: standbyStatusUpdate(lsn, time.Now())})
+	if err := conn.Frontend().Flush(); err != nil {
+		return fmt.Errorf("failed to send standby status: %w", err)
+	}
+	return nil
+}
+
+// standbyStatusUpdate encodes a standby status update message. The final
+// reply-requested byte is left 0.
+func standbyStatusUpdate(lsn int64, now time.Time) []byte {
+	buf := make([]byte, 34)
+	buf[0] = 'r'
+	binary.BigEndian.PutUint64(buf[1:], uint64(lsn))
+	binary.BigEndian.PutUint64(buf[9:], uint64(lsn))
+	binary.BigEndian.PutUint64(buf[17:], uint64(lsn))
+	binary.BigEndian.PutUint64(buf[25:], uint64(now.Sub(postgresEpoch).Microseconds()))
+	return buf
+}
@@ -0,0 +1,111 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartReplicationQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     StreamOptions
+		expected string
+	}{
+		{
+			name:     "slot position",
+			expected: "START_REPLICATION SLOT app_slot LOGICAL 0/0",
+		},
+		{
+			name: "start lsn and plugin args",
+			opts: StreamOptions{
+				StartLSN:   0x16B3748,
+				PluginArgs: map[string]string{"publication_names": "app", "proto_version": "1"},
+			},
+			expected: `START_REPLICATION SLOT app_slot LOGICAL 0/16B3748 ("proto_version" '1', "publication_names" 'app')`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := startReplicationQuery("app_slot", tt.opts); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatLSN(t *testing.T) {
+	for _, lsn := range []string{"0/0", "0/16B3748", "1A/FF000028"} {
+		n, err := parseLSN(lsn)
+		if err != nil {
+			t.Fatalf("parseLSN(%q): unexpected error: %v", lsn, err)
+		}
+		if result := formatLSN(n); result != lsn {
+			t.Errorf("expected %q, got %q", lsn, result)
+		}
+	}
+}
+
+func TestStandbyStatusUpdate(t *testing.T) {
+	now := postgresEpoch.Add(90 * time.Second)
+	msg := standbyStatusUpdate(0x1000, now)
+
+	if len(msg) != 34 || msg[0] != 'r' {
+		t.Fatalf("unexpected message header: %v", msg)
+	}
+	for _, offset := range []int{1, 9, 17} {
+		if lsn := binary.BigEndian.Uint64(msg[offset:]); lsn != 0x1000 {
+			t.Errorf("offset %d: expected LSN 0x1000, got %#x", offset, lsn)
+		}
+	}
+	if ts := binary.BigEndian.Uint64(msg[25:]); ts != 90_000_000 {
+		t.Errorf("expected 90000000 microseconds, got %d", ts)
+	}
+}
+
+func TestPostgreSQLAdapter_StreamLogicalWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if _, err := a.StreamLogical(context.Background(), "app_slot", StreamOptions{}); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_StreamLogical(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := a.CheckLogicalReplicationPrerequisites(ctx); err != nil {
+		t.Skipf("server not configured for logical replication: %v", err)
+	}
+	if _, err := a.db.ExecContext(ctx, "SELECT pg_create_logical_replication_slot('stream_logical_test', 'test_decoding')"); err != nil {
+		t.Skipf("failed to create replication slot: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("SELECT pg_drop_replication_slot('stream_logical_test')") })
+
+	streamCtx, stop := context.WithCancel(ctx)
+	messages, err := a.StreamLogical(streamCtx, "stream_logical_test", StreamOptions{})
+	if err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+
+	if _, err := a.EmitReplicationMessage(ctx, "stream_test", []byte("hello"), false); err != nil {
+		t.Fatalf("failed to emit message: %v", err)
+	}
+
+	select {
+	case m := <-messages:
+		if !strings.Contains(string(m.Data), "hello") {
+			t.Errorf("expected the emitted message, got %q", m.Data)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for a message")
+	}
+
+	stop()
+	for range messages {
+	}
+}
@@ -0,0 +1,205 @@
+// Package maintenance provides database maintenance helpers that shell out to
+// the PostgreSQL client tools (pg_restore and friends).
+package maintenance
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// DumpFormat is the output format of pg_dump.
+type DumpFormat string
+
+const (
+	// FormatAuto detects the format from the input, see DetectFormat.
+	FormatAuto DumpFormat = ""
+	// FormatCustom is pg_dump --format=custom.
+	FormatCustom DumpFormat = "custom"
+	// FormatDirectory is pg_dump --format=directory.
+	FormatDirectory DumpFormat = "directory"
+	// FormatTar is pg_dump --format=tar.
+	FormatTar DumpFormat = "tar"
+	// FormatPlain is a plain SQL script, restored with psql.
+	FormatPlain DumpFormat = "plain"
+)
+
+// customDumpMagic starts every custom-format archive and the toc.dat of a
+// directory-format dump.
+var customDumpMagic = []byte("PGDMP")
+
+// RestoreOptions configures Restore.
+type RestoreOptions struct {
+	// DSN identifies the target database. It is passed to pg_restore as
+	// --dbname and accepts both key=value and postgres:// URL forms.
+	DSN string
+
+	// Tables restricts the restore to the named tables. Empty restores everything.
+	Tables []string
+
+	// ProgressFn, when set, receives each progress line pg_restore reports.
+	ProgressFn func(string)
+
+	// BinaryPath overrides the pg_restore executable. Defaults to "pg_restore"
+	// looked up on PATH.
+	BinaryPath string
+
+	// PsqlPath overrides the psql executable used for plain SQL dumps.
+	// Defaults to "psql" looked up on PATH.
+	PsqlPath string
+
+	// Format is the format of the dump. Defaults to FormatAuto.
+	Format DumpFormat
+}
+
+// Restore loads a dump produced by pg_dump from inputPath into the database
+// identified by opts.DSN. Archives are restored with pg_restore: existing
+// objects are dropped first (--clean) and ownership and privileges from the
+// dump are ignored (--no-owner, --no-acl) so the dump can be restored under a
+// different role. Plain SQL dumps are run with psql, stopping at the first
+// error; they cannot be restricted to opts.Tables.
+func Restore(ctx context.Context, inputPath string, opts RestoreOptions) error {
+	if err := checkReadable(inputPath); err != nil {
+		return err
+	}
+
+	format := opts.Format
+	if format == FormatAuto {
+		detected, err := DetectFormat(inputPath)
+		if err != nil {
+			return err
+		}
+		format = detected
+	}
+
+	if format == FormatPlain {
+		if len(opts.Tables) > 0 {
+			return fmt.Errorf("maintenance: plain SQL dumps cannot be restored selectively")
+		}
+		bin := opts.PsqlPath
+		if bin == "" {
+			bin = "psql"
+		}
+		cmd := exec.CommandContext(ctx, bin, psqlArgs(inputPath, opts)...)
+		return run(cmd, opts.ProgressFn)
+	}
+
+	bin := opts.BinaryPath
+	if bin == "" {
+		bin = "pg_restore"
+	}
+
+	cmd := exec.CommandContext(ctx, bin, restoreArgs(inputPath, format, opts)...)
+	return run(cmd, opts.ProgressFn)
+}
+
+// DetectFormat reports the format of the dump at path: a directory is a
+// directory-format dump, a file starting with "PGDMP" is a custom-format
+// archive, a file with a tar header is a tar archive, and anything else is
+// taken to be a plain SQL script.
+func DetectFormat(path string) (DumpFormat, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("maintenance: input %s is not readable: %w", path, err)
+	}
+	if info.IsDir() {
+		return FormatDirectory, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("maintenance: input %s is not readable: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	header := make([]byte, 512)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", fmt.Errorf("maintenance: failed to read %s: %w", path, err)
+	}
+	return detectFormat(header[:n]), nil
+}
+
+// detectFormat identifies a dump file from its first bytes.
+func detectFormat(header []byte) DumpFormat {
+	switch {
+	case bytes.HasPrefix(header, customDumpMagic):
+		return FormatCustom
+	case len(header) >= 262 && bytes.Equal(header[257:262], []byte("ustar")):
+		return FormatTar
+	default:
+		return FormatPlain
+	}
+}
+
+// restoreArgs builds the pg_restore command line.
+func restoreArgs(inputPath string, format DumpFormat, opts RestoreOptions) []string {
+	args := []string{"--clean", "--if-exists", "--no-acl", "--no-owner"}
+	if format != FormatAuto {
+		args = append(args, "--format="+string(format))
+	}
+	if opts.DSN != "" {
+		args = append(args, "--dbname="+opts.DSN)
+	}
+	if opts.ProgressFn != nil {
+		args = append(args, "--verbose")
+	}
+	for _, table := range opts.Tables {
+		args = append(args, "--table="+table)
+	}
+	return append(args, inputPath)
+}
+
+// psqlArgs builds the psql command line for a plain SQL dump.
+func psqlArgs(inputPath string, opts RestoreOptions) []string {
+	args := []string{"--no-psqlrc", "--quiet", "--set=ON_ERROR_STOP=1"}
+	if opts.DSN != "" {
+		args = append(args, "--dbname="+opts.DSN)
+	}
+	return append(args, "--file="+inputPath)
+}
+
+// checkReadable verifies that path exists and can be opened for reading.
+func checkReadable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("maintenance: input %s is not readable: %w", path, err)
+	}
+	return f.Close()
+}
+
+// run executes cmd, forwarding each line of its stderr to progressFn.
+func run(cmd *exec.Cmd, progressFn func(string)) error {
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("maintenance: failed to capture output: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("maintenance: failed to start %s: %w", cmd.Path, err)
+	}
+
+	var lastLine string
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		lastLine = scanner.Text()
+		if progressFn != nil {
+			progressFn(lastLine)
+		}
+	}
+	// Drain whatever the scanner left behind so the process never blocks on a full pipe.
+	_, _ = io.Copy(io.Discard, stderr)
+
+	if err := cmd.Wait(); err != nil {
+		if lastLine != "" {
+			return fmt.Errorf("maintenance: %s failed: %w: %s", cmd.Path, err, lastLine)
+		}
+		return fmt.Errorf("maintenance: %s failed: %w", cmd.Path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package maintenance
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRestore_MissingInput(t *testing.T) {
+	err := Restore(context.Background(), filepath.Join(t.TempDir(), "missing.dump"), RestoreOptions{})
+	if err == nil {
+		t.Error("expected error for missing input file, got nil")
+	}
+}
+
+func TestRestoreArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   DumpFormat
+		opts     RestoreOptions
+		expected []string
+	}{
+		{
+			name:     "defaults",
+			opts:     RestoreOptions{},
+			expected: []string{"--clean", "--if-exists", "--no-acl", "--no-owner", "db.dump"},
+		},
+		{
+			name:   "selective restore with progress",
+			format: FormatCustom,
+			opts: RestoreOptions{
+				DSN:        "postgres://localhost/app",
+				Tables:     []string{"users", "orders"},
+				ProgressFn: func(string) {},
+			},
+			expected: []string{
+				"--clean", "--if-exists", "--no-acl", "--no-owner", "--format=custom",
+				"--dbname=postgres://localhost/app", "--verbose",
+				"--table=users", "--table=orders", "db.dump",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := restoreArgs("db.dump", tt.format, tt.opts)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestRestore_PlainWithTables(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.sql")
+	if err := os.WriteFile(path, []byte("CREATE TABLE users (id int);\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	err := Restore(context.Background(), path, RestoreOptions{Tables: []string{"users"}})
+	if err == nil {
+		t.Error("expected error for selective restore of a plain dump, got nil")
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tarHeader := make([]byte, 512)
+	copy(tarHeader[257:], "ustar")
+
+	tests := []struct {
+		name     string
+		header   []byte
+		expected DumpFormat
+	}{
+		{name: "custom", header: []byte("PGDMP\x01\x0e\x00"), expected: FormatCustom},
+		{name: "tar", header: tarHeader, expected: FormatTar},
+		{name: "plain", header: []byte("--\n-- PostgreSQL database dump\n"), expected: FormatPlain},
+		{name: "empty", header: nil, expected: FormatPlain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "db.dump")
+			if err := os.WriteFile(path, tt.header, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			result, err := DetectFormat(path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+
+	t.Run("directory", func(t *testing.T) {
+		result, err := DetectFormat(t.TempDir())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != FormatDirectory {
+			t.Errorf("expected %q, got %q", FormatDirectory, result)
+		}
+	})
+}
+
+func TestPsqlArgs(t *testing.T) {
+	result := psqlArgs("db.sql", RestoreOptions{DSN: "postgres://localhost/app"})
+	expected := []string{"--no-psqlrc", "--quiet", "--set=ON_ERROR_STOP=1", "--dbname=postgres://localhost/app", "--file=db.sql"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+}
@@ -0,0 +1,115 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+)
+
+// memoryGuardInterval is how often the memory guard inspects running queries.
+const memoryGuardInterval = time.Second
+
+// memoryGuardQuery cancels this application's running statements that have,
+// on average, touched more than $1 bytes of shared buffers per execution
+// according to pg_stat_statements.
+const memoryGuardQuery = `SELECT a.pid, pg_cancel_backend(a.pid)
+	FROM pg_stat_activity a
+	WHERE a.state = 'active'
+		AND a.pid <> pg_backend_pid()
+		AND a.datname = current_database()
+		AND a.usename = current_user
+		AND a.application_name = current_setting('application_name')
+		AND EXISTS (
+			SELECT 1 FROM pg_stat_statements s
+			WHERE s.queryid = a.query_id
+				AND s.userid = a.usesysid
+				AND s.dbid = a.datid
+				AND s.calls > 0
+				AND s.shared_blks_hit / s.calls * 8192 > $1
+		)`
+
+// WithMemoryGuard starts a watchdog, once connected, that checks the
+// application's running queries every second and cancels with
+// pg_cancel_backend those estimated to use more than maxMB megabytes.
+//
+// The check is approximate. PostgreSQL does not report the memory of a
+// running query, so the estimate is the shared buffer blocks (8 KiB each)
+// that statements with the same query ID have hit per execution, as recorded
+// by pg_stat_statements once previous executions finished. A query is
+// therefore only recognised as heavy after it has completed at least once,
+// and buffer hits measure data read rather than memory held. The guard needs
+// the pg_stat_statements extension and PostgreSQL 14 or later; when the
+// check fails it logs a warning and keeps trying.
+//
+// Queries are matched by database, user and application_name, so set
+// WithApplicationName to keep the guard away from other programs connecting
+// as the same user.
+func WithMemoryGuard(maxMB int) Option {
+	return func(a *PostgreSQLAdapter) {
+		a.memoryGuardMB = maxMB
+	}
+}
+
+// startMemoryGuard starts the WithMemoryGuard watchdog on a.db, if enabled.
+func (a *PostgreSQLAdapter) startMemoryGuard() {
+	a.stopMemoryGuard()
+	if a.memoryGuardMB <= 0 || a.db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.memoryGuardCancel = cancel
+	go a.runMemoryGuard(ctx, a.db, int64(a.memoryGuardMB)*1024*1024)
+}
+
+// stopMemoryGuard stops the memory guard watchdog if it is running.
+func (a *PostgreSQLAdapter) stopMemoryGuard() {
+	if a.memoryGuardCancel != nil {
+		a.memoryGuardCancel()
+		a.memoryGuardCancel = nil
+	}
+}
+
+// runMemoryGuard checks db every memoryGuardInterval until ctx is done.
+func (a *PostgreSQLAdapter) runMemoryGuard(ctx context.Context, db *sql.DB, maxBytes int64) {
+	ticker := time.NewTicker(memoryGuardInterval)
+	defer ticker.Stop()
+
+	failing := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := a.cancelHeavyQueries(ctx, db, maxBytes)
+		if err != nil && !failing && ctx.Err() == nil {
+			// Warn once per failure streak rather than every second.
+			slog.Warn("postgresql: memory guard check failed", "error", err)
+		}
+		failing = err != nil
+	}
+}
+
+// cancelHeavyQueries runs one memory guard check.
+func (a *PostgreSQLAdapter) cancelHeavyQueries(ctx context.Context, db *sql.DB, maxBytes int64) error {
+	rows, err := db.QueryContext(ctx, a.annotate(memoryGuardQuery, "memory_guard"), maxBytes)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var pid int
+		var cancelled bool
+		if err := rows.Scan(&pid, &cancelled); err != nil {
+			return err
+		}
+		if cancelled {
+			slog.Warn("postgresql: memory guard cancelled query", "pid", pid, "max_mb", maxBytes/(1024*1024))
+		}
+	}
+	return rows.Err()
+}
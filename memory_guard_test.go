@@ -0,0 +1,41 @@
+package postgresql
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestWithMemoryGuard(t *testing.T) {
+	a := NewPostgreSQLAdapter(WithMemoryGuard(512))
+	if a.memoryGuardMB != 512 {
+		t.Errorf("expected memory guard of 512 MB, got %d", a.memoryGuardMB)
+	}
+}
+
+func TestPostgreSQLAdapter_MemoryGuardLifecycle(t *testing.T) {
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	disabled := NewPostgreSQLAdapter()
+	disabled.db = db
+	disabled.startMemoryGuard()
+	if disabled.memoryGuardCancel != nil {
+		t.Error("expected no watchdog without WithMemoryGuard")
+	}
+
+	a := NewPostgreSQLAdapter(WithMemoryGuard(256))
+	a.db = db
+	a.startMemoryGuard()
+	if a.memoryGuardCancel == nil {
+		t.Fatal("expected the watchdog to start")
+	}
+	if err := a.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	if a.memoryGuardCancel != nil {
+		t.Error("expected Close to stop the watchdog")
+	}
+}
@@ -0,0 +1,112 @@
+package postgresql
+
+import (
+	"errors"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// statementTableRe finds the table a statement reads or writes first.
+var statementTableRe = regexp.MustCompile(`(?i)\b(?:FROM|INTO|UPDATE)\s+((?:"[^"]+"|[\w$]+)(?:\.(?:"[^"]+"|[\w$]+))?)`)
+
+// queryMetrics holds the collectors registered by WithMetricsRegisterer.
+type queryMetrics struct {
+	registerer prometheus.Registerer
+	duration   *prometheus.HistogramVec
+	errors     *prometheus.CounterVec
+}
+
+// WithMetricsRegisterer records Prometheus metrics for Fetch, Insert,
+// Update, Delete and Execute on r:
+//
+//   - postgresql_query_duration_seconds, a histogram labelled by operation
+//     and table;
+//   - postgresql_query_errors_total, a counter labelled by operation, table
+//     and error_code, the SQLSTATE of the failure, "not_found" for
+//     adapter.ErrNotFound or "other".
+//
+// table is the first table named after FROM, INTO or UPDATE, or the
+// statement itself for operations that take a bare table name. Adapters
+// sharing r share the collectors. Call UnregisterMetrics to remove them.
+func WithMetricsRegisterer(r prometheus.Registerer) Option {
+	return func(a *PostgreSQLAdapter) {
+		m := &queryMetrics{
+			registerer: r,
+			duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Name:    "postgresql_query_duration_seconds",
+				Help:    "Duration of PostgreSQL adapter operations.",
+				Buckets: prometheus.DefBuckets,
+			}, []string{"operation", "table"}),
+			errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "postgresql_query_errors_total",
+				Help: "Failed PostgreSQL adapter operations.",
+			}, []string{"operation", "table", "error_code"}),
+		}
+		m.duration = registerCollector(r, m.duration)
+		m.errors = registerCollector(r, m.errors)
+		a.metrics = m
+	}
+}
+
+// registerCollector registers c with r, returning the collector already
+// registered under the same name if there is one.
+func registerCollector[C prometheus.Collector](r prometheus.Registerer, c C) C {
+	if err := r.Register(c); err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if errors.As(err, &already) {
+			if existing, ok := already.ExistingCollector.(C); ok {
+				return existing
+			}
+		}
+		slog.Warn("postgresql: failed to register metrics", "error", err)
+	}
+	return c
+}
+
+// UnregisterMetrics removes the collectors registered by
+// WithMetricsRegisterer and stops recording metrics, so tests can register
+// them again. It does nothing when metrics are not enabled.
+func (a *PostgreSQLAdapter) UnregisterMetrics() {
+	if a.metrics == nil {
+		return
+	}
+	a.metrics.registerer.Unregister(a.metrics.duration)
+	a.metrics.registerer.Unregister(a.metrics.errors)
+	a.metrics = nil
+}
+
+// observe records the duration and outcome of one operation.
+func (m *queryMetrics) observe(operation, statement string, elapsed time.Duration, err error) {
+	table := statementTable(statement)
+	m.duration.WithLabelValues(operation, table).Observe(elapsed.Seconds())
+	if err != nil {
+		m.errors.WithLabelValues(operation, table, errorCode(err)).Inc()
+	}
+}
+
+// statementTable returns the table label for statement.
+func statementTable(statement string) string {
+	if m := statementTableRe.FindStringSubmatch(statement); m != nil {
+		return strings.ReplaceAll(m[1], `"`, "")
+	}
+	if fields := strings.Fields(statement); len(fields) == 1 {
+		return strings.ReplaceAll(fields[0], `"`, "")
+	}
+	return ""
+}
+
+// errorCode returns the error_code label for err.
+func errorCode(err error) string {
+	if code := sqlState(err); code != "" {
+		return code
+	}
+	if errors.Is(err, adapter.ErrNotFound) {
+		return "not_found"
+	}
+	return "other"
+}
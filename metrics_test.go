@@ -0,0 +1,82 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestStatementTable(t *testing.T) {
+	tests := []struct {
+		statement string
+		expected  string
+	}{
+		{"SELECT * FROM users WHERE id = {id}", "users"},
+		{`select id from "app"."Users"`, "app.Users"},
+		{"INSERT INTO orders (id) VALUES ({id})", "orders"},
+		{"UPDATE accounts SET balance = {balance}", "accounts"},
+		{"DELETE FROM sessions", "sessions"},
+		{"public.users", "public.users"},
+		{"SELECT 1", ""},
+	}
+
+	for _, tt := range tests {
+		if result := statementTable(tt.statement); result != tt.expected {
+			t.Errorf("statementTable(%q): expected %q, got %q", tt.statement, tt.expected, result)
+		}
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{&pq.Error{Code: "23505"}, "23505"},
+		{fmt.Errorf("wrapped: %w", &pgconn.PgError{Code: "40001"}), "40001"},
+		{adapter.ErrNotFound, "not_found"},
+		{errors.New("boom"), "other"},
+	}
+
+	for _, tt := range tests {
+		if result := errorCode(tt.err); result != tt.expected {
+			t.Errorf("errorCode(%v): expected %q, got %q", tt.err, tt.expected, result)
+		}
+	}
+}
+
+func TestWithMetricsRegisterer(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	a := NewPostgreSQLAdapter(WithMetricsRegisterer(reg))
+	// A second adapter on the same registry shares the collectors.
+	b := NewPostgreSQLAdapter(WithMetricsRegisterer(reg))
+	if a.metrics.duration != b.metrics.duration || a.metrics.errors != b.metrics.errors {
+		t.Error("expected adapters on one registry to share collectors")
+	}
+
+	// Not connected, so the call fails and counts as an error.
+	_, _ = a.Fetch(context.Background(), &adapter.Operation{Statement: "SELECT * FROM users"}, nil)
+
+	if n := testutil.CollectAndCount(reg, "postgresql_query_duration_seconds"); n != 1 {
+		t.Errorf("expected 1 duration series, got %d", n)
+	}
+	if v := testutil.ToFloat64(a.metrics.errors.WithLabelValues("fetch", "users", "other")); v != 1 {
+		t.Errorf("expected 1 error, got %v", v)
+	}
+
+	a.UnregisterMetrics()
+	if a.metrics != nil {
+		t.Error("expected metrics to be disabled after UnregisterMetrics")
+	}
+	if n := testutil.CollectAndCount(reg); n != 0 {
+		t.Errorf("expected no collectors after UnregisterMetrics, got %d series", n)
+	}
+	NewPostgreSQLAdapter().UnregisterMetrics()
+}
@@ -0,0 +1,165 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// migrationsTable records the migrations applied by RunMigrations.
+const migrationsTable = "schema_migrations"
+
+// migrationLockKey is the transaction-level advisory lock taken around each
+// migration, so that instances starting together apply it only once.
+const migrationLockKey int64 = 0x6d6967726174650a // "migrate\n"
+
+// RunMigrations applies the .sql files in dir of fsys that have not been
+// applied yet, in lexicographic order, so name them with a sortable prefix
+// such as 0001_create_users.sql. Files ending in .down.sql are skipped; see
+// RollbackMigration. Each migration runs in its own transaction together
+// with the insert recording its file name in schema_migrations, which is
+// created if absent, so a failed migration leaves no trace and stops the
+// run. An advisory lock keeps concurrent callers from applying the same
+// migration twice.
+//
+// fsys is typically an embed.FS:
+//
+//	//go:embed migrations/*.sql
+//	var migrations embed.FS
+//
+//	err := a.RunMigrations(ctx, migrations, "migrations")
+func (a *PostgreSQLAdapter) RunMigrations(ctx context.Context, fsys fs.FS, dir string) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	names, err := migrationFiles(fsys, dir)
+	if err != nil {
+		return err
+	}
+	if err := a.ensureMigrationsTable(ctx); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		body, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return fmt.Errorf("postgresql: failed to read migration %s: %w", name, err)
+		}
+		err = a.inMigrationTx(ctx, func(tx *sql.Tx) error {
+			var applied bool
+			query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE filename = $1)", migrationsTable)
+			if err := tx.QueryRowContext(ctx, query, name).Scan(&applied); err != nil {
+				return err
+			}
+			if applied {
+				return nil
+			}
+			if _, err := tx.ExecContext(ctx, string(body)); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (filename) VALUES ($1)", migrationsTable), name)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("postgresql: migration %s failed: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// RollbackMigration undoes the last applied migration, the one with the
+// highest file name, by running its down file in dir of fsys and removing
+// it from schema_migrations, in one transaction. The down file of
+// 0002_add_email.sql or 0002_add_email.up.sql is 0002_add_email.down.sql.
+// Rolling back with no migrations applied does nothing.
+func (a *PostgreSQLAdapter) RollbackMigration(ctx context.Context, fsys fs.FS, dir string) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+	if err := a.ensureMigrationsTable(ctx); err != nil {
+		return err
+	}
+
+	return a.inMigrationTx(ctx, func(tx *sql.Tx) error {
+		var name string
+		query := fmt.Sprintf("SELECT filename FROM %s ORDER BY filename DESC LIMIT 1", migrationsTable)
+		if err := tx.QueryRowContext(ctx, query).Scan(&name); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return fmt.Errorf("postgresql: failed to find the last migration: %w", err)
+		}
+
+		down := downMigrationName(name)
+		body, err := fs.ReadFile(fsys, path.Join(dir, down))
+		if err != nil {
+			return fmt.Errorf("postgresql: failed to read down migration %s: %w", down, err)
+		}
+		if _, err := tx.ExecContext(ctx, string(body)); err != nil {
+			return fmt.Errorf("postgresql: down migration %s failed: %w", down, err)
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE filename = $1", migrationsTable), name); err != nil {
+			return fmt.Errorf("postgresql: failed to unrecord migration %s: %w", name, err)
+		}
+		return nil
+	})
+}
+
+// ensureMigrationsTable creates schema_migrations if it does not exist.
+func (a *PostgreSQLAdapter) ensureMigrationsTable(ctx context.Context) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		filename text PRIMARY KEY,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`, migrationsTable)
+	if _, err := a.execContext(ctx, "create_migrations_table", query); err != nil {
+		return fmt.Errorf("postgresql: failed to create %s: %w", migrationsTable, err)
+	}
+	return nil
+}
+
+// inMigrationTx runs fn in a transaction holding the migration lock,
+// committing if it succeeds.
+func (a *PostgreSQLAdapter) inMigrationTx(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := a.beginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("postgresql: failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("postgresql: failed to take migration lock: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// migrationFiles returns the names of the up migrations in dir, sorted.
+func migrationFiles(fsys fs.FS, dir string) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to read migrations directory %s: %w", dir, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".sql") || strings.HasSuffix(name, ".down.sql") {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// downMigrationName returns the down file of the migration name.
+func downMigrationName(name string) string {
+	base := strings.TrimSuffix(strings.TrimSuffix(name, ".sql"), ".up")
+	return base + ".down.sql"
+}
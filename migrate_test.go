@@ -0,0 +1,107 @@
+package postgresql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMigrationFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/0002_add_email.up.sql":   {Data: []byte("ALTER TABLE users ADD email text")},
+		"migrations/0002_add_email.down.sql": {Data: []byte("ALTER TABLE users DROP email")},
+		"migrations/0001_users.sql":          {Data: []byte("CREATE TABLE users (id int)")},
+		"migrations/README.md":               {Data: []byte("notes")},
+	}
+
+	names, err := migrationFiles(fsys, "migrations")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"0001_users.sql", "0002_add_email.up.sql"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+
+	if _, err := migrationFiles(fsys, "missing"); err == nil {
+		t.Error("expected error for a missing directory, got nil")
+	}
+}
+
+func TestDownMigrationName(t *testing.T) {
+	tests := map[string]string{
+		"0001_users.sql":        "0001_users.down.sql",
+		"0002_add_email.up.sql": "0002_add_email.down.sql",
+	}
+	for name, expected := range tests {
+		if result := downMigrationName(name); result != expected {
+			t.Errorf("downMigrationName(%q): expected %q, got %q", name, expected, result)
+		}
+	}
+}
+
+func TestPostgreSQLAdapter_MigrationsWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	ctx := context.Background()
+	fsys := fstest.MapFS{}
+
+	if err := a.RunMigrations(ctx, fsys, "."); err == nil {
+		t.Error("RunMigrations: expected error when not connected, got nil")
+	}
+	if err := a.RollbackMigration(ctx, fsys, "."); err == nil {
+		t.Error("RollbackMigration: expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_RunMigrations(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE IF EXISTS migrate_test, schema_migrations") })
+
+	fsys := fstest.MapFS{
+		"m/0001_create.sql":      {Data: []byte("CREATE TABLE migrate_test (id int)")},
+		"m/0001_create.down.sql": {Data: []byte("DROP TABLE migrate_test")},
+		"m/0002_column.sql":      {Data: []byte("ALTER TABLE migrate_test ADD name text; INSERT INTO migrate_test VALUES (1, 'a')")},
+		"m/0002_column.down.sql": {Data: []byte("ALTER TABLE migrate_test DROP name")},
+	}
+
+	// Running twice applies each migration once.
+	for i := 0; i < 2; i++ {
+		if err := a.RunMigrations(ctx, fsys, "m"); err != nil {
+			t.Fatalf("RunMigrations failed: %v", err)
+		}
+	}
+	var rows int
+	if err := a.db.QueryRowContext(ctx, "SELECT count(*) FROM migrate_test").Scan(&rows); err != nil {
+		t.Fatalf("count failed: %v", err)
+	}
+	if rows != 1 {
+		t.Errorf("expected 1 row, got %d", rows)
+	}
+
+	if err := a.RollbackMigration(ctx, fsys, "m"); err != nil {
+		t.Fatalf("RollbackMigration failed: %v", err)
+	}
+	var applied []string
+	r, err := a.db.QueryContext(ctx, "SELECT filename FROM schema_migrations ORDER BY filename")
+	if err != nil {
+		t.Fatalf("select failed: %v", err)
+	}
+	defer r.Close()
+	for r.Next() {
+		var name string
+		if err := r.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		applied = append(applied, name)
+	}
+	if !reflect.DeepEqual(applied, []string{"0001_create.sql"}) {
+		t.Errorf("expected only the first migration applied, got %v", applied)
+	}
+
+	// A failing migration is not recorded.
+	fsys["m/0003_broken.sql"] = &fstest.MapFile{Data: []byte("SELECT * FROM missing_table")}
+	if err := a.RunMigrations(ctx, fsys, "m"); err == nil {
+		t.Error("expected error for a broken migration, got nil")
+	}
+}
@@ -0,0 +1,85 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// OperationOptions carries PostgreSQL-specific behaviour for a single call.
+// adapter.Operation is shared by every adapter and has no room for
+// PostgreSQL-only settings, so they travel in the context instead:
+//
+//	ctx = postgresql.WithOperationOptions(ctx, postgresql.OperationOptions{
+//	    UseDefault: []string{"created_at"},
+//	})
+//	err := mapper.Insert(ctx, "users.insert", user)
+type OperationOptions struct {
+	// UseDefault lists data fields that are left out of INSERT statements so
+	// the column's DEFAULT expression (now(), uuid_generate_v4(), ...) fires.
+	UseDefault []string
+
+	// Upsert turns inserts into INSERT ... ON CONFLICT statements.
+	Upsert *UpsertOptions
+
+	// OptimisticLock makes Update check and bump a version column.
+	OptimisticLock *OptimisticLockOption
+
+	// SoftDelete makes Delete mark rows as deleted instead of removing them.
+	SoftDelete *SoftDeleteOption
+
+	// ExcludeSoftDeleted makes Fetch skip rows marked by SoftDelete, whose
+	// Column must be set.
+	ExcludeSoftDeleted bool
+
+	// LockMode makes Fetch lock the rows it reads with FOR UPDATE or FOR
+	// SHARE. The locks are held until the transaction carried by the context
+	// ends, so it is only useful together with WithTx.
+	LockMode LockMode
+
+	// ColumnTransform renames the columns read by InsertFromAdapter before
+	// they are inserted, e.g. to map one schema's names onto another's.
+	ColumnTransform func(column string) string
+
+	// UseTablesample makes FetchRandom sample with TABLESAMPLE BERNOULLI
+	// when true, or sort the whole result by random() when false. When nil,
+	// FetchRandom picks TABLESAMPLE for large tables.
+	UseTablesample *bool
+}
+
+type operationOptionsKey struct{}
+
+// WithOperationOptions returns a copy of ctx carrying opts. Adapter methods
+// called with the returned context apply them to the operation they run.
+func WithOperationOptions(ctx context.Context, opts OperationOptions) context.Context {
+	return context.WithValue(ctx, operationOptionsKey{}, opts)
+}
+
+// OperationOptionsFromContext returns the options stored in ctx, or the zero
+// value when none were set.
+func OperationOptionsFromContext(ctx context.Context) OperationOptions {
+	opts, _ := ctx.Value(operationOptionsKey{}).(OperationOptions)
+	return opts
+}
+
+// usesDefault reports whether dataField should be left to its column default.
+func (o OperationOptions) usesDefault(dataField string) bool {
+	return containsString(o.UseDefault, dataField)
+}
+
+// insertProperties returns the properties of op that are written by an
+// INSERT, leaving out those whose column default should fire instead.
+func insertProperties(ctx context.Context, op *adapter.Operation) []adapter.PropertyMapping {
+	opts := OperationOptionsFromContext(ctx)
+	if len(opts.UseDefault) == 0 {
+		return op.Properties
+	}
+
+	props := make([]adapter.PropertyMapping, 0, len(op.Properties))
+	for _, prop := range op.Properties {
+		if !opts.usesDefault(prop.DataField) {
+			props = append(props, prop)
+		}
+	}
+	return props
+}
@@ -0,0 +1,53 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestOperationOptionsFromContext_Unset(t *testing.T) {
+	opts := OperationOptionsFromContext(context.Background())
+	if len(opts.UseDefault) != 0 {
+		t.Errorf("expected zero options, got %+v", opts)
+	}
+}
+
+func TestInsertProperties(t *testing.T) {
+	op := &adapter.Operation{
+		Statement: "users",
+		Properties: []adapter.PropertyMapping{
+			{ObjectField: "Name", DataField: "name"},
+			{ObjectField: "CreatedAt", DataField: "created_at"},
+			{ObjectField: "Token", DataField: "token"},
+		},
+	}
+
+	props := insertProperties(context.Background(), op)
+	if len(props) != 3 {
+		t.Fatalf("expected all 3 properties without options, got %d", len(props))
+	}
+
+	ctx := WithOperationOptions(context.Background(), OperationOptions{
+		UseDefault: []string{"created_at", "token"},
+	})
+	props = insertProperties(ctx, op)
+	if len(props) != 1 || props[0].DataField != "name" {
+		t.Errorf("expected only 'name' to be inserted, got %+v", props)
+	}
+	if len(op.Properties) != 3 {
+		t.Error("expected operation properties to be left untouched")
+	}
+}
+
+func TestInsertValuesClause(t *testing.T) {
+	if result := insertValuesClause(nil, nil); result != "DEFAULT VALUES" {
+		t.Errorf("expected DEFAULT VALUES, got %q", result)
+	}
+
+	result := insertValuesClause([]string{"name", "email"}, []string{"$1", "$2"})
+	if expected := "(name, email) VALUES ($1, $2)"; result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
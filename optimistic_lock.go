@@ -0,0 +1,153 @@
+package postgresql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// OptimisticLockOption enables optimistic locking for Update. Attach it to a
+// call through OperationOptions.OptimisticLock:
+//
+//	ctx = postgresql.WithOperationOptions(ctx, postgresql.OperationOptions{
+//	    OptimisticLock: &postgresql.OptimisticLockOption{VersionField: "version"},
+//	})
+//
+// The update statement is extended to bump the version column and to match
+// only the version the caller read:
+//
+//	UPDATE users SET name = {name} WHERE id = {id}
+//
+// becomes
+//
+//	UPDATE users SET name = {name}, "version" = users."version" + 1
+//	WHERE (id = {id}) AND users."version" = {version}
+//
+// The version column is qualified with the target table, or its alias, so
+// it stays unambiguous when a FROM clause joins tables with a column of the
+// same name.
+//
+// Each object passed to Update must carry the version it was read with
+// under VersionField. When no row matches, Update returns
+// ErrOptimisticLockConflict; callers typically re-read the row and retry.
+type OptimisticLockOption struct {
+	// VersionField is the integer version column, which is also the key
+	// holding the expected version in each updated object.
+	VersionField string
+}
+
+var (
+	whereKeyword     = regexp.MustCompile(`(?i)\bWHERE\b`)
+	returningKeyword = regexp.MustCompile(`(?i)\bRETURNING\b`)
+	distinctSuffix   = regexp.MustCompile(`(?i)\bDISTINCT\s+$`)
+)
+
+// sqlName matches a plain or double-quoted SQL name.
+const sqlName = `(?:"(?:[^"]|"")*"|[A-Za-z_][A-Za-z0-9_$]*)`
+
+// updateTarget matches the start of an UPDATE statement up to SET,
+// capturing the target table and its alias, if any.
+var updateTarget = regexp.MustCompile(`(?is)^\s*UPDATE\s+(?:ONLY\s+)?(` + sqlName + `(?:\.` + sqlName + `)?)(?:\s+(?:AS\s+)?(` + sqlName + `))?\s+SET\b`)
+
+// optimisticLockStatement rewrites the named update statement so it
+// increments versionField and only matches rows still at the version held
+// in the {versionField} parameter.
+func optimisticLockStatement(statement, versionField string) (string, error) {
+	loc := lastMatch(whereKeyword, statement)
+	if loc == nil {
+		return "", fmt.Errorf("postgresql: optimistic locking requires an UPDATE statement with a WHERE clause")
+	}
+
+	set, from := splitUpdateFrom(statement[:loc[0]])
+	where := statement[loc[1]:]
+	returning := ""
+	if r := lastMatch(returningKeyword, where); r != nil {
+		where, returning = where[:r[0]], " "+where[r[0]:]
+	}
+
+	column := QuoteIdentifier(versionField)
+	qualified := updateQualifier(statement) + column
+	return fmt.Sprintf("%s, %s = %s + 1%s WHERE (%s) AND %s = {%s}%s",
+		set, column, qualified, from, strings.TrimSpace(where), qualified, versionField, returning), nil
+}
+
+// splitUpdateFrom splits the part of an UPDATE statement before its WHERE
+// into the SET list and the FROM clause, if any, so that assignments can be
+// appended to the SET list. The SET list is returned without trailing
+// space and the FROM clause with a leading one.
+func splitUpdateFrom(statement string) (set, from string) {
+	set = statement
+	for _, m := range topLevelMatches(fromKeyword, statement) {
+		// IS [NOT] DISTINCT FROM is an operator, not a clause.
+		if !distinctSuffix.MatchString(statement[:m[0]]) {
+			set, from = statement[:m[0]], " "+strings.TrimSpace(statement[m[0]:])
+			break
+		}
+	}
+	return strings.TrimRight(set, " \t\r\n"), from
+}
+
+// updateQualifier returns the alias, or else the table, that an UPDATE
+// statement updates, followed by a dot, for qualifying its columns. It
+// returns "" when the statement does not start with UPDATE.
+func updateQualifier(statement string) string {
+	m := updateTarget.FindStringSubmatch(statement)
+	switch {
+	case m == nil:
+		return ""
+	case m[2] != "":
+		return m[2] + "."
+	default:
+		return m[1] + "."
+	}
+}
+
+// lastMatch returns the location of the last match of re in s outside
+// parentheses and quotes, or nil, so keywords of subqueries are skipped.
+func lastMatch(re *regexp.Regexp, s string) []int {
+	matches := topLevelMatches(re, s)
+	if len(matches) == 0 {
+		return nil
+	}
+	return matches[len(matches)-1]
+}
+
+// firstMatch is lastMatch for the first match.
+func firstMatch(re *regexp.Regexp, s string) []int {
+	matches := topLevelMatches(re, s)
+	if len(matches) == 0 {
+		return nil
+	}
+	return matches[0]
+}
+
+// topLevelMatches returns the matches of re in s that start outside
+// parentheses, string literals and quoted identifiers.
+func topLevelMatches(re *regexp.Regexp, s string) [][]int {
+	topLevel := make([]bool, len(s)+1)
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		topLevel[i] = depth == 0 && quote == 0
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		}
+	}
+
+	var matches [][]int
+	for _, m := range re.FindAllStringIndex(s, -1) {
+		if topLevel[m[0]] {
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
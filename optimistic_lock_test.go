@@ -0,0 +1,92 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestOptimisticLockStatement(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		expected  string
+	}{
+		{
+			"simple",
+			"UPDATE users SET name = {name} WHERE id = {id}",
+			`UPDATE users SET name = {name}, "version" = users."version" + 1 WHERE (id = {id}) AND users."version" = {version}`,
+		},
+		{
+			"disjunction",
+			"update users set name = {name} where id = {id} or email = {email}",
+			`update users set name = {name}, "version" = users."version" + 1 WHERE (id = {id} or email = {email}) AND users."version" = {version}`,
+		},
+		{
+			"returning",
+			"UPDATE users SET name = {name} WHERE id = {id} RETURNING updated_at",
+			`UPDATE users SET name = {name}, "version" = users."version" + 1 WHERE (id = {id}) AND users."version" = {version} RETURNING updated_at`,
+		},
+		{
+			"from",
+			"UPDATE users u SET name = o.name FROM orders o WHERE u.id = o.user_id AND o.id = {id}",
+			`UPDATE users u SET name = o.name, "version" = u."version" + 1 FROM orders o WHERE (u.id = o.user_id AND o.id = {id}) AND u."version" = {version}`,
+		},
+		{
+			"schema-qualified target with alias",
+			`UPDATE ONLY public."Users" AS x SET active = a IS DISTINCT FROM b FROM orders WHERE x.id = {id}`,
+			`UPDATE ONLY public."Users" AS x SET active = a IS DISTINCT FROM b, "version" = x."version" + 1 FROM orders WHERE (x.id = {id}) AND x."version" = {version}`,
+		},
+		{
+			"subquery",
+			"UPDATE public.users SET total = (SELECT sum(n) FROM orders) WHERE id = {id}",
+			`UPDATE public.users SET total = (SELECT sum(n) FROM orders), "version" = public.users."version" + 1 WHERE (id = {id}) AND public.users."version" = {version}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := optimisticLockStatement(tt.statement, "version")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestOptimisticLockStatement_NoWhere(t *testing.T) {
+	if _, err := optimisticLockStatement("UPDATE users SET name = {name}", "version"); err == nil {
+		t.Error("expected error for statement without WHERE, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_UpdateOptimisticLock(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, `CREATE TABLE lock_test (id int PRIMARY KEY, name text, version int NOT NULL DEFAULT 1);
+		INSERT INTO lock_test (id, name) VALUES (1, 'a')`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE lock_test") })
+
+	ctx = WithOperationOptions(ctx, OperationOptions{
+		OptimisticLock: &OptimisticLockOption{VersionField: "version"},
+	})
+	op := &adapter.Operation{Statement: "UPDATE lock_test SET name = {name} WHERE id = {id}"}
+
+	obj := map[string]interface{}{"id": 1, "name": "b", "version": 1}
+	if err := a.Update(ctx, op, []interface{}{obj}); err != nil {
+		t.Fatalf("first update failed: %v", err)
+	}
+
+	err := a.Update(ctx, op, []interface{}{obj})
+	if !errors.Is(err, ErrOptimisticLockConflict) {
+		t.Errorf("expected ErrOptimisticLockConflict on stale version, got %v", err)
+	}
+}
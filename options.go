@@ -0,0 +1,77 @@
+package postgresql
+
+import "time"
+
+// Option configures a PostgreSQLAdapter at construction time.
+type Option func(*PostgreSQLAdapter)
+
+// WithApplicationName sets the application_name reported to the server,
+// visible in pg_stat_activity and the server log. It is also used as the
+// app= marker of SQL annotations. The application_name config key takes
+// precedence; without either, Connect uses the name of the running binary.
+func WithApplicationName(name string) Option {
+	return func(a *PostgreSQLAdapter) {
+		a.applicationName = name
+	}
+}
+
+// WithSQLAnnotations enables or disables the /* app=<name> op=<operation> */
+// comment prefixed to every emitted query when an application name is set.
+// Annotations let pg_stat_activity and pg_stat_statements be grouped by
+// operation rather than by exact query text. They are enabled by default.
+func WithSQLAnnotations(enabled bool) Option {
+	return func(a *PostgreSQLAdapter) {
+		a.sqlAnnotations = enabled
+	}
+}
+
+// WithDriver selects the database/sql driver: DriverPQ ("postgres", the
+// default) for lib/pq or DriverPGX ("pgx") for pgx/v5. Both accept the same
+// connection string. pgx offers the binary protocol, richer type support and
+// reliable context cancellation, and lets BatchExecute send all statements in
+// a single round trip.
+func WithDriver(driverName string) Option {
+	return func(a *PostgreSQLAdapter) {
+		a.driverName = driverName
+	}
+}
+
+// WithStatementTimeout makes the server cancel any statement that runs longer
+// than d, whether or not the caller's context has a deadline. The limit is
+// sent as the statement_timeout setting when each connection starts, so it
+// costs no extra round trips. The statement_timeout_ms config key overrides
+// it. Zero, the default, leaves the server's setting in place.
+func WithStatementTimeout(d time.Duration) Option {
+	return func(a *PostgreSQLAdapter) {
+		a.statementTimeout = d
+	}
+}
+
+// WithPreparedStatements makes Fetch, Update and Delete prepare each distinct
+// statement once and reuse it, so PostgreSQL parses and plans it only once
+// per connection. Statements run inside a transaction are not cached. pgx
+// already caches prepared statements on its own, so this mostly benefits
+// lib/pq. It is disabled by default; see also FlushPreparedStatements.
+func WithPreparedStatements(enabled bool) Option {
+	return func(a *PostgreSQLAdapter) {
+		a.preparedStatements = enabled
+	}
+}
+
+// WithAutoArrayScan makes Fetch, Execute and the other row-returning methods
+// decode array columns into Go slices ([]int64, []float64, []bool or
+// []string) with ArrayScanner, instead of returning the raw "{1,2,3}"
+// literal. It is disabled by default because it changes the result types.
+func WithAutoArrayScan(enabled bool) Option {
+	return func(a *PostgreSQLAdapter) {
+		a.autoArrayScan = enabled
+	}
+}
+
+// WithJSONUnmarshal makes json and jsonb columns arrive decoded, objects as
+// map[string]interface{}, instead of as json.RawMessage. See JSONBScanner.
+func WithJSONUnmarshal(enabled bool) Option {
+	return func(a *PostgreSQLAdapter) {
+		a.jsonUnmarshal = enabled
+	}
+}
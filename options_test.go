@@ -0,0 +1,116 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestNewPostgreSQLAdapter_Options(t *testing.T) {
+	a := NewPostgreSQLAdapter(WithApplicationName("billing"), WithSQLAnnotations(false))
+	if a.applicationName != "billing" {
+		t.Errorf("expected applicationName 'billing', got %q", a.applicationName)
+	}
+	if a.sqlAnnotations {
+		t.Error("expected SQL annotations to be disabled")
+	}
+}
+
+func TestPostgreSQLAdapter_Annotate(t *testing.T) {
+	query := "SELECT * FROM users WHERE id = $1"
+
+	tests := []struct {
+		name     string
+		adapter  *PostgreSQLAdapter
+		op       string
+		expected string
+	}{
+		{
+			name:     "no application name",
+			adapter:  NewPostgreSQLAdapter(),
+			op:       "fetch",
+			expected: query,
+		},
+		{
+			name:     "application name set",
+			adapter:  NewPostgreSQLAdapter(WithApplicationName("billing")),
+			op:       "fetch",
+			expected: "/* app=billing op=fetch */ " + query,
+		},
+		{
+			name:     "annotations disabled",
+			adapter:  NewPostgreSQLAdapter(WithApplicationName("billing"), WithSQLAnnotations(false)),
+			op:       "fetch",
+			expected: query,
+		},
+		{
+			name:     "comment terminator in name",
+			adapter:  NewPostgreSQLAdapter(WithApplicationName("bill*/ing")),
+			op:       "get/*user",
+			expected: "/* app=bill* /ing op=get/ *user */ " + query,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.adapter.annotate(query, tt.op); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestOperationName(t *testing.T) {
+	if name := operationName(&adapter.Operation{Type: adapter.OpUpdate}, "fallback"); name != "update" {
+		t.Errorf("expected 'update', got %q", name)
+	}
+	if name := operationName(&adapter.Operation{}, "fetch"); name != "fetch" {
+		t.Errorf("expected fallback 'fetch', got %q", name)
+	}
+}
+
+func TestEscapeDSNValue(t *testing.T) {
+	if result := escapeDSNValue(`it's a\b`); result != `it\'s a\\b` {
+		t.Errorf("unexpected escaping: %q", result)
+	}
+}
+
+func TestWithDriver(t *testing.T) {
+	if a := NewPostgreSQLAdapter(); a.driverName != DriverPQ {
+		t.Errorf("expected default driver %q, got %q", DriverPQ, a.driverName)
+	}
+	if a := NewPostgreSQLAdapter(WithDriver(DriverPGX)); a.driverName != DriverPGX {
+		t.Errorf("expected driver %q, got %q", DriverPGX, a.driverName)
+	}
+}
+
+func TestPostgreSQLAdapter_ConnectUnsupportedDriver(t *testing.T) {
+	a := NewPostgreSQLAdapter(WithDriver("mysql"))
+	if err := a.Connect(context.Background(), map[string]interface{}{}); err == nil {
+		t.Error("expected error for unsupported driver, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_ConnectFailsForBothDrivers(t *testing.T) {
+	for _, driver := range []string{DriverPQ, DriverPGX} {
+		t.Run(driver, func(t *testing.T) {
+			a := NewPostgreSQLAdapter(WithDriver(driver))
+			config := map[string]interface{}{"host": "127.0.0.1", "port": 1}
+			if err := a.Connect(context.Background(), config); err == nil {
+				t.Error("expected error connecting to an unreachable server, got nil")
+			}
+		})
+	}
+}
+
+func TestWithStatementTimeout(t *testing.T) {
+	a := NewPostgreSQLAdapter(WithStatementTimeout(3 * time.Second))
+	if a.statementTimeout != 3*time.Second {
+		t.Errorf("expected statement timeout 3s, got %v", a.statementTimeout)
+	}
+	if NewPostgreSQLAdapter().statementTimeout != 0 {
+		t.Error("expected no statement timeout by default")
+	}
+}
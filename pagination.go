@@ -0,0 +1,346 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// PageWithEstimate is one page of results together with the planner's
+// estimate of the total number of rows.
+type PageWithEstimate struct {
+	Results  []interface{}
+	Page     int
+	PageSize int
+	// EstimatedTotal comes from the query planner's statistics and may be
+	// off, sometimes considerably, until the tables are analyzed. It is meant
+	// for display ("about 12,000 results"), not for exact arithmetic.
+	EstimatedTotal int64
+}
+
+// FetchWithRowEstimate returns page (1-based) of op's results, pageSize rows
+// per page, along with an estimate of the total row count taken from the
+// query plan. This avoids running an exact COUNT(*), which has to visit
+// every matching row.
+func (a *PostgreSQLAdapter) FetchWithRowEstimate(ctx context.Context, op *adapter.Operation, params map[string]interface{}, page, pageSize int) (*PageWithEstimate, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	if page < 1 || pageSize < 1 {
+		return nil, fmt.Errorf("postgresql: invalid page %d of size %d", page, pageSize)
+	}
+
+	statement, err := fetchStatement(ctx, op.Statement)
+	if err != nil {
+		return nil, err
+	}
+	args, err := extractArgs(statement, params)
+	if err != nil {
+		return nil, err
+	}
+	query := replaceNamedParams(statement)
+	name := operationName(op, "fetch")
+
+	var plan []byte
+	if err := a.queryRowContext(ctx, name, "EXPLAIN (FORMAT JSON) "+query, args...).Scan(&plan); err != nil {
+		return nil, fmt.Errorf("postgresql: explain failed: %w", err)
+	}
+	estimate, err := parsePlanRows(plan)
+	if err != nil {
+		return nil, err
+	}
+
+	pageQuery := fmt.Sprintf("SELECT * FROM (%s) AS _page LIMIT $%d OFFSET $%d", query, len(args)+1, len(args)+2)
+	args = append(args, pageSize, (page-1)*pageSize)
+
+	rows, err := a.queryContext(ctx, name, pageQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: query failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	results, err := a.scanRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PageWithEstimate{
+		Results:        results,
+		Page:           page,
+		PageSize:       pageSize,
+		EstimatedTotal: estimate,
+	}, nil
+}
+
+// totalCountColumn is the window-function column added by FetchWithCount.
+const totalCountColumn = "_total_count"
+
+// CountedPage is one page of results together with the exact number of rows
+// the query matches across all pages.
+type CountedPage struct {
+	Results []interface{}
+	Limit   int
+	Offset  int
+	Total   int64
+}
+
+// FetchWithCount returns up to limit of op's results starting at offset,
+// together with the total number of matching rows. Both come from a single
+// query using COUNT(*) OVER (), so no separate count query is needed.
+// Only when offset is past the last row, leaving no row to carry the total,
+// is the total counted separately. As with FetchCount, soft-deleted rows
+// are left out when ExcludeSoftDeleted is set and LockMode is ignored.
+func (a *PostgreSQLAdapter) FetchWithCount(ctx context.Context, op *adapter.Operation, params map[string]interface{}, limit, offset int) (*CountedPage, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	if limit < 1 || offset < 0 {
+		return nil, fmt.Errorf("postgresql: invalid limit %d or offset %d", limit, offset)
+	}
+
+	statement, err := fetchStatement(ctx, op.Statement)
+	if err != nil {
+		return nil, err
+	}
+	args, err := extractArgs(statement, params)
+	if err != nil {
+		return nil, err
+	}
+	query := replaceNamedParams(statement)
+	name := operationName(op, "fetch")
+
+	pageQuery := fmt.Sprintf("SELECT *, COUNT(*) OVER () AS %s FROM (%s) AS sub LIMIT $%d OFFSET $%d",
+		totalCountColumn, query, len(args)+1, len(args)+2)
+
+	rows, err := a.queryContext(ctx, name, pageQuery, append(args, limit, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: query failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	results, err := a.scanRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := takeTotalCount(results)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 && offset > 0 {
+		if err := a.queryRowContext(ctx, name, countQuery(query), args...).Scan(&total); err != nil {
+			return nil, fmt.Errorf("postgresql: count failed: %w", err)
+		}
+	}
+
+	return &CountedPage{
+		Results: results,
+		Limit:   limit,
+		Offset:  offset,
+		Total:   total,
+	}, nil
+}
+
+// takeTotalCount reads the window-function total from the first row and
+// removes the column from every row. It returns zero for an empty page.
+func takeTotalCount(results []interface{}) (int64, error) {
+	if len(results) == 0 {
+		return 0, nil
+	}
+
+	total, ok := results[0].(map[string]interface{})[totalCountColumn].(int64)
+	if !ok {
+		return 0, fmt.Errorf("postgresql: missing %s column in results", totalCountColumn)
+	}
+	for _, row := range results {
+		delete(row.(map[string]interface{}), totalCountColumn)
+	}
+	return total, nil
+}
+
+// parsePlanRows extracts the top-level "Plan Rows" estimate from the output
+// of EXPLAIN (FORMAT JSON).
+func parsePlanRows(plan []byte) (int64, error) {
+	var explained []struct {
+		Plan struct {
+			PlanRows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+
+	if err := json.Unmarshal(plan, &explained); err != nil {
+		return 0, fmt.Errorf("postgresql: failed to parse query plan: %w", err)
+	}
+	if len(explained) == 0 {
+		return 0, fmt.Errorf("postgresql: empty query plan")
+	}
+
+	return int64(explained[0].Plan.PlanRows), nil
+}
+
+// PageDirection is the way FetchPage moves from its cursor.
+type PageDirection int
+
+// Directions for PageCursor.
+const (
+	// PageForward fetches the rows that follow the cursor in sort order.
+	PageForward PageDirection = iota
+	// PageBackward fetches the rows that precede the cursor.
+	PageBackward
+)
+
+// PageCursor positions FetchPage in a keyset-paginated result. Start with
+// a cursor whose Value is nil and follow Page.Next and Page.Prev from there.
+type PageCursor struct {
+	// Column is the result column the pages are sorted by. Its values must
+	// be unique, or rows sharing a value across a page boundary are skipped;
+	// a primary key or a timestamp with a unique tie-breaker works well.
+	Column string
+	// Value is the Column value of the row the page starts after (forward)
+	// or ends before (backward). nil starts at the first row.
+	Value interface{}
+	// Descending sorts by Column from high to low.
+	Descending bool
+	// Direction is PageForward or PageBackward.
+	Direction PageDirection
+	// PageSize is the number of rows per page.
+	PageSize int
+}
+
+// Page is one page of keyset-paginated results, in sort order.
+type Page struct {
+	Results []interface{}
+	// Next fetches the following page; nil when this is the last one.
+	Next *PageCursor
+	// Prev fetches the preceding page; nil when this is the first one.
+	Prev *PageCursor
+}
+
+// FetchPage returns one page of op's results using keyset pagination: the
+// query filters on the sort column instead of skipping rows with OFFSET, so
+// every page costs the same however deep it is, given an index on the
+// column. op's statement is wrapped as
+//
+//	SELECT * FROM (<statement>) AS _page WHERE col > $n ORDER BY col LIMIT $m
+//
+// with the comparison and order flipped for descending or backward paging,
+// so any ORDER BY or LIMIT in the statement itself has no effect on paging.
+// The ExcludeSoftDeleted and LockMode operation options apply as for Fetch.
+func (a *PostgreSQLAdapter) FetchPage(ctx context.Context, op *adapter.Operation, params map[string]interface{}, cursor *PageCursor) (*Page, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	if cursor == nil || cursor.PageSize < 1 {
+		return nil, fmt.Errorf("postgresql: FetchPage requires a cursor with a positive page size")
+	}
+	if err := validateIdentifier(cursor.Column); err != nil || strings.Contains(cursor.Column, ".") {
+		return nil, fmt.Errorf("postgresql: invalid sort column %q", cursor.Column)
+	}
+
+	query, args, err := pageQuery(ctx, op.Statement, params, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := a.queryContext(ctx, operationName(op, "fetch"), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: query failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	results, err := a.scanRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return keysetPage(results, cursor), nil
+}
+
+// pageQuery builds the FetchPage query and arguments for statement,
+// applying the ExcludeSoftDeleted and LockMode operation options in ctx.
+// The lock is taken on the outer query, so only the page's rows are locked.
+func pageQuery(ctx context.Context, statement string, params map[string]interface{}, cursor *PageCursor) (string, []interface{}, error) {
+	statement, err := fetchStatement(ctx, statement)
+	if err != nil {
+		return "", nil, err
+	}
+	args, err := extractArgs(statement, params)
+	if err != nil {
+		return "", nil, err
+	}
+	// One row more than the page tells whether another page follows.
+	query, args := keysetQuery(replaceNamedParams(statement), args, cursor)
+	query, err = lockStatement(query, OperationOptionsFromContext(ctx).LockMode)
+	if err != nil {
+		return "", nil, err
+	}
+	return query, args, nil
+}
+
+// keysetQuery wraps query to fetch the page after (or before) cursor, plus
+// one extra row, appending the cursor value and limit to args.
+func keysetQuery(query string, args []interface{}, cursor *PageCursor) (string, []interface{}) {
+	// Backward paging walks the opposite order and keysetPage reverses it.
+	descending := cursor.Descending != (cursor.Direction == PageBackward)
+	op, order := ">", "ASC"
+	if descending {
+		op, order = "<", "DESC"
+	}
+
+	col := QuoteIdentifier(cursor.Column)
+	where := ""
+	if cursor.Value != nil {
+		args = append(args, cursor.Value)
+		where = fmt.Sprintf(" WHERE %s %s $%d", col, op, len(args))
+	}
+	args = append(args, cursor.PageSize+1)
+
+	return fmt.Sprintf("SELECT * FROM (%s) AS _page%s ORDER BY %s %s LIMIT $%d",
+		query, where, col, order, len(args)), args
+}
+
+// keysetPage trims the extra row fetched by keysetQuery, restores sort order
+// for backward pages and builds the cursors of the neighbouring pages.
+func keysetPage(results []interface{}, cursor *PageCursor) *Page {
+	more := len(results) > cursor.PageSize
+	if more {
+		results = results[:cursor.PageSize]
+	}
+	if cursor.Direction == PageBackward {
+		for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+			results[i], results[j] = results[j], results[i]
+		}
+	}
+
+	page := &Page{Results: results}
+	if len(results) == 0 {
+		return page
+	}
+
+	at := func(row interface{}, direction PageDirection) *PageCursor {
+		next := *cursor
+		next.Value = row.(map[string]interface{})[cursor.Column]
+		next.Direction = direction
+		return &next
+	}
+
+	// A forward page has a predecessor whenever it started after a row, and
+	// a backward page has a successor whenever it ended before one.
+	hasNext, hasPrev := more, cursor.Value != nil
+	if cursor.Direction == PageBackward {
+		hasNext, hasPrev = cursor.Value != nil, more
+	}
+	if hasNext {
+		page.Next = at(results[len(results)-1], PageForward)
+	}
+	if hasPrev {
+		page.Prev = at(results[0], PageBackward)
+	}
+	return page
+}
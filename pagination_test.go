@@ -0,0 +1,299 @@
+package postgresql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestParsePlanRows(t *testing.T) {
+	plan := []byte(`[{"Plan": {"Node Type": "Seq Scan", "Relation Name": "users", "Plan Rows": 12840, "Plan Width": 36}}]`)
+	rows, err := parsePlanRows(plan)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rows != 12840 {
+		t.Errorf("expected 12840, got %d", rows)
+	}
+
+	for _, bad := range []string{`not json`, `[]`} {
+		if _, err := parsePlanRows([]byte(bad)); err == nil {
+			t.Errorf("expected error for plan %q, got nil", bad)
+		}
+	}
+}
+
+func TestPostgreSQLAdapter_FetchWithRowEstimateWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	op := &adapter.Operation{Statement: "SELECT * FROM users"}
+	if _, err := a.FetchWithRowEstimate(context.Background(), op, nil, 1, 20); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestTakeTotalCount(t *testing.T) {
+	results := []interface{}{
+		map[string]interface{}{"id": int64(1), "_total_count": int64(42)},
+		map[string]interface{}{"id": int64(2), "_total_count": int64(42)},
+	}
+
+	total, err := takeTotalCount(results)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 42 {
+		t.Errorf("expected total 42, got %d", total)
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{"id": int64(1)},
+		map[string]interface{}{"id": int64(2)},
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("expected the count column to be removed, got %v", results)
+	}
+
+	if total, err := takeTotalCount(nil); err != nil || total != 0 {
+		t.Errorf("expected 0 for an empty page, got %d, %v", total, err)
+	}
+	if _, err := takeTotalCount([]interface{}{map[string]interface{}{"id": int64(1)}}); err == nil {
+		t.Error("expected error for a row without the count column, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_FetchWithCountWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	op := &adapter.Operation{Statement: "SELECT * FROM users"}
+	if _, err := a.FetchWithCount(context.Background(), op, nil, 20, 0); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_FetchWithCount(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+	op := &adapter.Operation{Statement: "SELECT g AS n FROM generate_series(1, 25) g"}
+
+	page, err := a.FetchWithCount(ctx, op, nil, 10, 20)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if page.Total != 25 || len(page.Results) != 5 {
+		t.Errorf("expected 5 of 25 rows, got %d of %d", len(page.Results), page.Total)
+	}
+
+	page, err = a.FetchWithCount(ctx, op, nil, 10, 30)
+	if err != nil {
+		t.Fatalf("fetch past the end failed: %v", err)
+	}
+	if page.Total != 25 || len(page.Results) != 0 {
+		t.Errorf("expected 0 of 25 rows, got %d of %d", len(page.Results), page.Total)
+	}
+}
+
+func TestPostgreSQLAdapter_FetchWithCountExcludeSoftDeleted(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, `CREATE TABLE fetch_with_count_test (id int PRIMARY KEY, deleted_at timestamptz);
+		INSERT INTO fetch_with_count_test SELECT i, CASE WHEN i % 5 = 0 THEN now() END FROM generate_series(1, 25) AS i`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE fetch_with_count_test") })
+
+	op := &adapter.Operation{Statement: "SELECT id FROM fetch_with_count_test"}
+	ctx = WithOperationOptions(ctx, OperationOptions{
+		SoftDelete:         &SoftDeleteOption{Column: "deleted_at"},
+		ExcludeSoftDeleted: true,
+	})
+
+	page, err := a.FetchWithCount(ctx, op, nil, 10, 10)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if page.Total != 20 || len(page.Results) != 10 {
+		t.Errorf("expected 10 of 20 live rows, got %d of %d", len(page.Results), page.Total)
+	}
+
+	page, err = a.FetchWithCount(ctx, op, nil, 10, 30)
+	if err != nil {
+		t.Fatalf("fetch past the end failed: %v", err)
+	}
+	if page.Total != 20 {
+		t.Errorf("expected a total of 20 live rows, got %d", page.Total)
+	}
+}
+
+func TestKeysetQuery(t *testing.T) {
+	base := "SELECT * FROM users WHERE active = $1"
+
+	tests := []struct {
+		name         string
+		cursor       PageCursor
+		expected     string
+		expectedArgs []interface{}
+	}{
+		{
+			name:         "first page",
+			cursor:       PageCursor{Column: "id", PageSize: 10},
+			expected:     `SELECT * FROM (SELECT * FROM users WHERE active = $1) AS _page ORDER BY "id" ASC LIMIT $2`,
+			expectedArgs: []interface{}{true, 11},
+		},
+		{
+			name:         "forward",
+			cursor:       PageCursor{Column: "id", Value: 42, PageSize: 10},
+			expected:     `SELECT * FROM (SELECT * FROM users WHERE active = $1) AS _page WHERE "id" > $2 ORDER BY "id" ASC LIMIT $3`,
+			expectedArgs: []interface{}{true, 42, 11},
+		},
+		{
+			name:         "backward",
+			cursor:       PageCursor{Column: "id", Value: 42, Direction: PageBackward, PageSize: 10},
+			expected:     `SELECT * FROM (SELECT * FROM users WHERE active = $1) AS _page WHERE "id" < $2 ORDER BY "id" DESC LIMIT $3`,
+			expectedArgs: []interface{}{true, 42, 11},
+		},
+		{
+			name:         "descending forward",
+			cursor:       PageCursor{Column: "id", Value: 42, Descending: true, PageSize: 10},
+			expected:     `SELECT * FROM (SELECT * FROM users WHERE active = $1) AS _page WHERE "id" < $2 ORDER BY "id" DESC LIMIT $3`,
+			expectedArgs: []interface{}{true, 42, 11},
+		},
+		{
+			name:         "descending backward",
+			cursor:       PageCursor{Column: "id", Value: 42, Descending: true, Direction: PageBackward, PageSize: 10},
+			expected:     `SELECT * FROM (SELECT * FROM users WHERE active = $1) AS _page WHERE "id" > $2 ORDER BY "id" ASC LIMIT $3`,
+			expectedArgs: []interface{}{true, 42, 11},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := keysetQuery(base, []interface{}{true}, &tt.cursor)
+			if query != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, query)
+			}
+			if !reflect.DeepEqual(args, tt.expectedArgs) {
+				t.Errorf("expected args %v, got %v", tt.expectedArgs, args)
+			}
+		})
+	}
+}
+
+func TestPageQuery(t *testing.T) {
+	ctx := WithOperationOptions(context.Background(), OperationOptions{
+		SoftDelete:         &SoftDeleteOption{Column: "deleted_at"},
+		ExcludeSoftDeleted: true,
+		LockMode:           LockForUpdate,
+	})
+	cursor := &PageCursor{Column: "id", Value: 42, PageSize: 10}
+
+	query, args, err := pageQuery(ctx, "SELECT * FROM users WHERE active = {active}", map[string]interface{}{"active": true}, cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `SELECT * FROM (SELECT * FROM users WHERE (active = $1) AND "deleted_at" IS NULL) AS _page WHERE "id" > $2 ORDER BY "id" ASC LIMIT $3 FOR UPDATE`
+	if query != expected {
+		t.Errorf("expected %q, got %q", expected, query)
+	}
+	if !reflect.DeepEqual(args, []interface{}{true, 42, 11}) {
+		t.Errorf("unexpected args %v", args)
+	}
+
+	ctx = WithOperationOptions(context.Background(), OperationOptions{ExcludeSoftDeleted: true})
+	if _, _, err := pageQuery(ctx, "SELECT * FROM users", nil, cursor); err == nil {
+		t.Error("expected error for ExcludeSoftDeleted without a column, got nil")
+	}
+}
+
+func keysetRows(ids ...int) []interface{} {
+	rows := make([]interface{}, len(ids))
+	for i, id := range ids {
+		rows[i] = map[string]interface{}{"id": id}
+	}
+	return rows
+}
+
+func TestKeysetPage_Forward(t *testing.T) {
+	cursor := &PageCursor{Column: "id", PageSize: 2}
+
+	page := keysetPage(keysetRows(1, 2, 3), cursor)
+	if !reflect.DeepEqual(page.Results, keysetRows(1, 2)) {
+		t.Errorf("expected rows 1 and 2, got %v", page.Results)
+	}
+	if page.Prev != nil {
+		t.Errorf("expected no previous page, got %+v", page.Prev)
+	}
+	if page.Next == nil || page.Next.Value != 2 || page.Next.Direction != PageForward {
+		t.Fatalf("expected next cursor after 2, got %+v", page.Next)
+	}
+
+	page = keysetPage(keysetRows(3), page.Next)
+	if page.Next != nil {
+		t.Errorf("expected no next page, got %+v", page.Next)
+	}
+	if page.Prev == nil || page.Prev.Value != 3 || page.Prev.Direction != PageBackward {
+		t.Errorf("expected previous cursor before 3, got %+v", page.Prev)
+	}
+}
+
+func TestKeysetPage_Backward(t *testing.T) {
+	// Backward queries return rows in reverse order.
+	cursor := &PageCursor{Column: "id", Value: 5, Direction: PageBackward, PageSize: 2}
+
+	page := keysetPage(keysetRows(4, 3, 2), cursor)
+	if !reflect.DeepEqual(page.Results, keysetRows(3, 4)) {
+		t.Errorf("expected rows 3 and 4 in order, got %v", page.Results)
+	}
+	if page.Prev == nil || page.Prev.Value != 3 {
+		t.Errorf("expected previous cursor before 3, got %+v", page.Prev)
+	}
+	if page.Next == nil || page.Next.Value != 4 {
+		t.Errorf("expected next cursor after 4, got %+v", page.Next)
+	}
+
+	page = keysetPage(keysetRows(1), page.Prev)
+	if page.Prev != nil {
+		t.Errorf("expected no previous page, got %+v", page.Prev)
+	}
+}
+
+func TestPostgreSQLAdapter_FetchPageWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	op := &adapter.Operation{Statement: "SELECT * FROM users"}
+	if _, err := a.FetchPage(context.Background(), op, nil, &PageCursor{Column: "id", PageSize: 10}); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_FetchPage(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	op := &adapter.Operation{Statement: "SELECT g AS id FROM generate_series(1, 5) g"}
+	ctx := context.Background()
+
+	var ids []int64
+	cursor := &PageCursor{Column: "id", PageSize: 2}
+	for cursor != nil {
+		page, err := a.FetchPage(ctx, op, nil, cursor)
+		if err != nil {
+			t.Fatalf("fetch page failed: %v", err)
+		}
+		for _, row := range page.Results {
+			ids = append(ids, row.(map[string]interface{})["id"].(int64))
+		}
+		if page.Next == nil {
+			prev, err := a.FetchPage(ctx, op, nil, page.Prev)
+			if err != nil {
+				t.Fatalf("fetch previous page failed: %v", err)
+			}
+			if len(prev.Results) != 2 || prev.Results[0].(map[string]interface{})["id"] != int64(3) {
+				t.Errorf("expected backward page [3 4], got %v", prev.Results)
+			}
+		}
+		cursor = page.Next
+	}
+
+	if !reflect.DeepEqual(ids, []int64{1, 2, 3, 4, 5}) {
+		t.Errorf("expected ids 1-5, got %v", ids)
+	}
+}
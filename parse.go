@@ -0,0 +1,101 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// astStatementTypes maps the parse tree node of common statements to the
+// SQL command they run.
+var astStatementTypes = map[string]string{
+	"SelectStmt":      "SELECT",
+	"InsertStmt":      "INSERT",
+	"UpdateStmt":      "UPDATE",
+	"DeleteStmt":      "DELETE",
+	"MergeStmt":       "MERGE",
+	"CreateStmt":      "CREATE TABLE",
+	"IndexStmt":       "CREATE INDEX",
+	"ViewStmt":        "CREATE VIEW",
+	"AlterTableStmt":  "ALTER TABLE",
+	"DropStmt":        "DROP",
+	"TruncateStmt":    "TRUNCATE",
+	"CopyStmt":        "COPY",
+	"ExplainStmt":     "EXPLAIN",
+	"TransactionStmt": "TRANSACTION",
+}
+
+// ParseTree is the parse tree of the first statement of a query, as
+// returned by ParseQuery.
+type ParseTree struct {
+	// ASTType is the node type of the statement, e.g. "SelectStmt".
+	ASTType string
+	// StatementType is the SQL command, e.g. "SELECT" or "CREATE TABLE".
+	// Statements without an entry of their own are reported by their first
+	// keyword.
+	StatementType string
+	// Tree is the statement's node as JSON, in the format of libpg_query.
+	Tree json.RawMessage
+}
+
+// parseResult is the JSON document pg_parse_query returns.
+type parseResult struct {
+	Stmts []struct {
+		Stmt map[string]json.RawMessage `json:"stmt"`
+	} `json:"stmts"`
+}
+
+// ParseQuery parses query with the server's parser, without planning or
+// running it, so tests can check the statements an application sends. It
+// calls pg_parse_query(text), which PostgreSQL does not ship: it must be
+// installed in the database from a libpg_query based extension, or the
+// call fails with ErrExtensionNotInstalled. A syntax error in query is
+// returned as the server reports it.
+func (a *PostgreSQLAdapter) ParseQuery(ctx context.Context, query string) (*ParseTree, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	var available bool
+	check := "SELECT to_regprocedure('pg_parse_query(text)') IS NOT NULL"
+	if err := a.queryRowContext(ctx, "parse_query", check).Scan(&available); err != nil {
+		return nil, fmt.Errorf("postgresql: failed to check for pg_parse_query: %w", err)
+	}
+	if !available {
+		return nil, fmt.Errorf("%w: pg_parse_query", ErrExtensionNotInstalled)
+	}
+
+	var tree []byte
+	if err := a.queryRowContext(ctx, "parse_query", "SELECT * FROM pg_parse_query($1)", query).Scan(&tree); err != nil {
+		return nil, fmt.Errorf("postgresql: failed to parse query: %w", err)
+	}
+	return parseTreeFromJSON(tree, query)
+}
+
+// parseTreeFromJSON extracts the first statement of a pg_parse_query result.
+func parseTreeFromJSON(data []byte, query string) (*ParseTree, error) {
+	var result parseResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("postgresql: invalid parse tree: %w", err)
+	}
+	if len(result.Stmts) == 0 || len(result.Stmts[0].Stmt) == 0 {
+		return nil, fmt.Errorf("postgresql: query has no statements")
+	}
+
+	// A statement node is a single-key object naming its type.
+	stmt := result.Stmts[0].Stmt
+	types := make([]string, 0, len(stmt))
+	for astType := range stmt {
+		types = append(types, astType)
+	}
+	sort.Strings(types)
+	astType := types[0]
+
+	statementType, ok := astStatementTypes[astType]
+	if fields := strings.Fields(query); !ok && len(fields) > 0 {
+		statementType = strings.ToUpper(fields[0])
+	}
+	return &ParseTree{ASTType: astType, StatementType: statementType, Tree: stmt[astType]}, nil
+}
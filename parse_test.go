@@ -0,0 +1,82 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParseTreeFromJSON(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		query         string
+		astType       string
+		statementType string
+		wantErr       bool
+	}{
+		{
+			name:          "select",
+			data:          `{"version": 170000, "stmts": [{"stmt": {"SelectStmt": {"op": "SETOP_NONE"}}}]}`,
+			query:         "SELECT 1",
+			astType:       "SelectStmt",
+			statementType: "SELECT",
+		},
+		{
+			name:          "create table",
+			data:          `{"stmts": [{"stmt": {"CreateStmt": {}}}, {"stmt": {"SelectStmt": {}}}]}`,
+			query:         "CREATE TABLE t (id int); SELECT 1",
+			astType:       "CreateStmt",
+			statementType: "CREATE TABLE",
+		},
+		{
+			name:          "unmapped node",
+			data:          `{"stmts": [{"stmt": {"VacuumStmt": {}}}]}`,
+			query:         "vacuum",
+			astType:       "VacuumStmt",
+			statementType: "VACUUM",
+		},
+		{name: "empty", data: `{"stmts": []}`, wantErr: true},
+		{name: "invalid", data: `not json`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree, err := parseTreeFromJSON([]byte(tt.data), tt.query)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tree.ASTType != tt.astType || tree.StatementType != tt.statementType {
+				t.Errorf("expected %s/%s, got %s/%s", tt.astType, tt.statementType, tree.ASTType, tree.StatementType)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_ParseQueryWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if _, err := a.ParseQuery(context.Background(), "SELECT 1"); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_ParseQuery(t *testing.T) {
+	a := newIntegrationAdapter(t)
+
+	tree, err := a.ParseQuery(context.Background(), "SELECT 1")
+	if errors.Is(err, ErrExtensionNotInstalled) {
+		t.Skip("pg_parse_query not installed")
+	}
+	if err != nil {
+		t.Fatalf("ParseQuery failed: %v", err)
+	}
+	if tree.StatementType != "SELECT" {
+		t.Errorf("expected SELECT, got %q", tree.StatementType)
+	}
+}
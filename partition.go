@@ -0,0 +1,188 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PartitionInfo describes one child table of a partitioned table.
+type PartitionInfo struct {
+	Schema string
+	Name   string
+	// Bound is the partition bound as reported by pg_get_expr, for example
+	// "FOR VALUES FROM ('2024-01-01') TO ('2024-02-01')". It is empty for
+	// tables attached through plain inheritance.
+	Bound string
+}
+
+// ListPartitions returns the child tables of parentTable, which may be
+// schema-qualified, ordered by name.
+func (a *PostgreSQLAdapter) ListPartitions(ctx context.Context, parentTable string) ([]PartitionInfo, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	query := `SELECT n.nspname, c.relname, COALESCE(pg_get_expr(c.relpartbound, c.oid), '')
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE i.inhparent = $1::regclass
+		ORDER BY c.relname`
+
+	rows, err := a.queryContext(ctx, "list_partitions", query, parentTable)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to list partitions of %s: %w", parentTable, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var partitions []PartitionInfo
+	for rows.Next() {
+		var p PartitionInfo
+		if err := rows.Scan(&p.Schema, &p.Name, &p.Bound); err != nil {
+			return nil, fmt.Errorf("postgresql: scan failed: %w", err)
+		}
+		partitions = append(partitions, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgresql: rows iteration failed: %w", err)
+	}
+
+	return partitions, nil
+}
+
+// DetachPartition detaches child from the partitioned table parent. The
+// child table is kept as a standalone table.
+func (a *PostgreSQLAdapter) DetachPartition(ctx context.Context, parent, child string) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	query := fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %s", QuoteIdentifier(parent), QuoteIdentifier(child))
+	if _, err := a.execContext(ctx, "detach_partition", query); err != nil {
+		return fmt.Errorf("postgresql: failed to detach partition %s from %s: %w", child, parent, err)
+	}
+	return nil
+}
+
+// DropPartition drops the partition table name, discarding its rows.
+func (a *PostgreSQLAdapter) DropPartition(ctx context.Context, name string) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	query := fmt.Sprintf("DROP TABLE %s", QuoteIdentifier(name))
+	if _, err := a.execContext(ctx, "drop_partition", query); err != nil {
+		return fmt.Errorf("postgresql: failed to drop partition %s: %w", name, err)
+	}
+	return nil
+}
+
+// PartitionInterval is the span of each partition created by
+// CreateTimePartitions.
+type PartitionInterval string
+
+// Partition intervals accepted by CreateTimePartitions.
+const (
+	PartitionDaily   PartitionInterval = "daily"
+	PartitionWeekly  PartitionInterval = "weekly"
+	PartitionMonthly PartitionInterval = "monthly"
+)
+
+// TimePartitionOptions configures CreateTimePartitions.
+type TimePartitionOptions struct {
+	// ParentTable is the range-partitioned table, optionally
+	// schema-qualified. Partitions are created in the same schema.
+	ParentTable string
+	// PartitionInterval is the span of each partition.
+	PartitionInterval PartitionInterval
+	// StartPartition is the lower bound of the first partition. Only its
+	// date is used, and monthly partitions start on the first of its month.
+	StartPartition time.Time
+	// EndPartition is an exclusive limit: partitions are created until one
+	// covers the day before it.
+	EndPartition time.Time
+}
+
+// CreateTimePartitions creates range partitions of opts.ParentTable covering
+// opts.StartPartition to opts.EndPartition, one per interval, named
+// <parent>_<YYYY_MM_DD> after their lower bound. Partitions that already
+// exist are left alone, so the call can be repeated to extend the range.
+// All partitions are created in one transaction.
+func (a *PostgreSQLAdapter) CreateTimePartitions(ctx context.Context, opts TimePartitionOptions) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	statements, err := timePartitionStatements(opts)
+	if err != nil {
+		return err
+	}
+
+	tx, err := a.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	txCtx := WithTx(ctx, tx)
+
+	for _, stmt := range statements {
+		if _, err := a.execContext(txCtx, "create_time_partitions", stmt); err != nil {
+			return fmt.Errorf("postgresql: failed to create partition of %s: %w", opts.ParentTable, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// timePartitionStatements builds the CREATE TABLE statements run by
+// CreateTimePartitions.
+func timePartitionStatements(opts TimePartitionOptions) ([]string, error) {
+	if opts.ParentTable == "" {
+		return nil, fmt.Errorf("postgresql: parent table is required")
+	}
+
+	var step func(time.Time) time.Time
+	switch opts.PartitionInterval {
+	case PartitionDaily:
+		step = func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	case PartitionWeekly:
+		step = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	case PartitionMonthly:
+		step = func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+	default:
+		return nil, fmt.Errorf("postgresql: unsupported partition interval %q", opts.PartitionInterval)
+	}
+
+	start := truncateToDate(opts.StartPartition)
+	if opts.PartitionInterval == PartitionMonthly {
+		// Stepping a month from the 29th-31st would drift into the
+		// following month, so monthly partitions are aligned to the 1st.
+		start = start.AddDate(0, 0, 1-start.Day())
+	}
+	end := truncateToDate(opts.EndPartition)
+	if !start.Before(end) {
+		return nil, fmt.Errorf("postgresql: partition range is empty: %s to %s",
+			start.Format(time.DateOnly), end.Format(time.DateOnly))
+	}
+
+	var statements []string
+	for from := start; from.Before(end); from = step(from) {
+		to := step(from)
+		name := opts.ParentTable + "_" + from.Format("2006_01_02")
+		statements = append(statements, fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')",
+			QuoteIdentifier(name),
+			QuoteIdentifier(opts.ParentTable),
+			from.Format(time.DateOnly),
+			to.Format(time.DateOnly)))
+	}
+	return statements, nil
+}
+
+// truncateToDate drops the time of day from t, keeping its calendar date.
+func truncateToDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,95 @@
+package postgresql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPostgreSQLAdapter_PartitionsWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	ctx := context.Background()
+
+	if _, err := a.ListPartitions(ctx, "events"); err == nil {
+		t.Error("expected error from ListPartitions when not connected, got nil")
+	}
+	if err := a.DetachPartition(ctx, "events", "events_2024_01"); err == nil {
+		t.Error("expected error from DetachPartition when not connected, got nil")
+	}
+	if err := a.DropPartition(ctx, "events_2024_01"); err == nil {
+		t.Error("expected error from DropPartition when not connected, got nil")
+	}
+	if err := a.CreateTimePartitions(ctx, TimePartitionOptions{ParentTable: "events"}); err == nil {
+		t.Error("expected error from CreateTimePartitions when not connected, got nil")
+	}
+}
+
+func TestTimePartitionStatements(t *testing.T) {
+	stmts, err := timePartitionStatements(TimePartitionOptions{
+		ParentTable:       "archive.events",
+		PartitionInterval: PartitionMonthly,
+		StartPartition:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndPartition:      time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		`CREATE TABLE IF NOT EXISTS "archive"."events_2024_01_01" PARTITION OF "archive"."events" FOR VALUES FROM ('2024-01-01') TO ('2024-02-01')`,
+		`CREATE TABLE IF NOT EXISTS "archive"."events_2024_02_01" PARTITION OF "archive"."events" FOR VALUES FROM ('2024-02-01') TO ('2024-03-01')`,
+	}
+	if !reflect.DeepEqual(stmts, expected) {
+		t.Errorf("expected %v, got %v", expected, stmts)
+	}
+}
+
+func TestTimePartitionStatements_Intervals(t *testing.T) {
+	start := time.Date(2024, 1, 1, 15, 30, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		interval PartitionInterval
+		count    int
+	}{
+		{PartitionDaily, 14},
+		{PartitionWeekly, 2},
+		{PartitionMonthly, 1},
+	}
+
+	for _, tt := range tests {
+		stmts, err := timePartitionStatements(TimePartitionOptions{
+			ParentTable:       "events",
+			PartitionInterval: tt.interval,
+			StartPartition:    start,
+			EndPartition:      end,
+		})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.interval, err)
+			continue
+		}
+		if len(stmts) != tt.count {
+			t.Errorf("%s: expected %d partitions, got %d", tt.interval, tt.count, len(stmts))
+		}
+	}
+}
+
+func TestTimePartitionStatements_Invalid(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		opts TimePartitionOptions
+	}{
+		{"no parent", TimePartitionOptions{PartitionInterval: PartitionDaily, StartPartition: start, EndPartition: start.AddDate(0, 0, 1)}},
+		{"bad interval", TimePartitionOptions{ParentTable: "events", PartitionInterval: "hourly", StartPartition: start, EndPartition: start.AddDate(0, 0, 1)}},
+		{"empty range", TimePartitionOptions{ParentTable: "events", PartitionInterval: PartitionDaily, StartPartition: start, EndPartition: start}},
+	}
+
+	for _, tt := range tests {
+		if _, err := timePartitionStatements(tt.opts); err == nil {
+			t.Errorf("%s: expected error, got nil", tt.name)
+		}
+	}
+}
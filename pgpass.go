@@ -0,0 +1,103 @@
+package postgresql
+
+import (
+	"bufio"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// pgPassFile returns the password file libpq would read: $PGPASSFILE, or
+// .pgpass in the home directory.
+func pgPassFile() string {
+	if path := os.Getenv("PGPASSFILE"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".pgpass")
+}
+
+// lookupPgPass returns the password for the connection from the password
+// file, following libpq: each line is hostname:port:database:username:password,
+// any of the first four fields may be *, and the first matching line wins.
+// Like libpq, a file readable by group or others is ignored with a warning.
+func lookupPgPass(host, port, database, user string) (string, bool) {
+	path := pgPassFile()
+	if path == "" {
+		return "", false
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return "", false
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		slog.Warn("postgresql: password file has group or world access; permissions should be 0600 or less", "path", path)
+		return "", false
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer func() { _ = f.Close() }()
+
+	// libpq matches Unix socket directories as localhost.
+	if host == "" || strings.HasPrefix(host, "/") {
+		host = "localhost"
+	}
+	want := []string{host, port, database, user}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := splitPgPassLine(line)
+		if len(fields) != 5 {
+			continue
+		}
+		if pgPassMatches(fields[:4], want) {
+			return fields[4], true
+		}
+	}
+	return "", false
+}
+
+// splitPgPassLine splits a password file line on unescaped colons, removing
+// the backslashes that escape colons and backslashes. Everything after the
+// fourth colon is the password.
+func splitPgPassLine(line string) []string {
+	var fields []string
+	var field strings.Builder
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && i+1 < len(line):
+			i++
+			field.WriteByte(line[i])
+		case c == ':' && len(fields) < 4:
+			fields = append(fields, field.String())
+			field.Reset()
+		default:
+			field.WriteByte(c)
+		}
+	}
+	return append(fields, field.String())
+}
+
+// pgPassMatches reports whether every field is * or equal to the wanted value.
+func pgPassMatches(fields, want []string) bool {
+	for i, field := range fields {
+		if field != "*" && field != want[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package postgresql
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writePgPass writes content to a password file with mode perm and points
+// PGPASSFILE at it.
+func writePgPass(t *testing.T, content string, perm os.FileMode) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pgpass")
+	if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, perm); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PGPASSFILE", path)
+}
+
+func TestSplitPgPassLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected []string
+	}{
+		{line: "db:5432:app:alice:secret", expected: []string{"db", "5432", "app", "alice", "secret"}},
+		{line: `db:5432:app:alice:se\:cr\\et`, expected: []string{"db", "5432", "app", "alice", `se:cr\et`}},
+		{line: "db:5432:app:alice:a:b", expected: []string{"db", "5432", "app", "alice", "a:b"}},
+		{line: "db:5432", expected: []string{"db", "5432"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if result := splitPgPassLine(tt.line); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestLookupPgPass(t *testing.T) {
+	writePgPass(t, strings.Join([]string{
+		"# comment",
+		"db.example.com:5432:app:alice:first",
+		"db.example.com:*:*:alice:second",
+		"localhost:5432:*:bob:socket",
+		"*:*:*:*:fallback",
+	}, "\n"), 0o600)
+
+	tests := []struct {
+		name     string
+		host     string
+		port     string
+		database string
+		user     string
+		expected string
+	}{
+		{name: "exact", host: "db.example.com", port: "5432", database: "app", user: "alice", expected: "first"},
+		{name: "wildcards", host: "db.example.com", port: "6432", database: "other", user: "alice", expected: "second"},
+		{name: "unix socket", host: "/var/run/postgresql", port: "5432", database: "app", user: "bob", expected: "socket"},
+		{name: "fallback", host: "other", port: "5432", database: "app", user: "carol", expected: "fallback"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := lookupPgPass(tt.host, tt.port, tt.database, tt.user)
+			if !ok || result != tt.expected {
+				t.Errorf("expected %q, got %q (found %v)", tt.expected, result, ok)
+			}
+		})
+	}
+}
+
+func TestLookupPgPass_NoMatch(t *testing.T) {
+	writePgPass(t, "db:5432:app:alice:secret\n", 0o600)
+	if _, ok := lookupPgPass("db", "5432", "app", "bob"); ok {
+		t.Error("expected no match")
+	}
+}
+
+func TestLookupPgPass_InsecurePermissions(t *testing.T) {
+	writePgPass(t, "*:*:*:*:secret\n", 0o644)
+	if _, ok := lookupPgPass("db", "5432", "app", "alice"); ok {
+		t.Error("expected world-readable password file to be ignored")
+	}
+}
+
+func TestLookupPgPass_MissingFile(t *testing.T) {
+	t.Setenv("PGPASSFILE", filepath.Join(t.TempDir(), "missing"))
+	if _, ok := lookupPgPass("db", "5432", "app", "alice"); ok {
+		t.Error("expected no match for a missing file")
+	}
+}
+
+func TestBuildDSN_PgPass(t *testing.T) {
+	writePgPass(t, "db.example.com:5432:app:alice:secret\n", 0o600)
+	a := NewPostgreSQLAdapter()
+
+	dsn, err := a.buildDSN(map[string]interface{}{"host": "db.example.com", "user": "alice", "database": "app"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(dsn, "password='secret'") {
+		t.Errorf("expected password from the password file, got %q", dsn)
+	}
+
+	dsn, err = a.buildDSN(map[string]interface{}{"host": "db.example.com", "user": "alice", "database": "app", "password": "given"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(dsn, "password='given'") {
+		t.Errorf("expected configured password to win, got %q", dsn)
+	}
+}
@@ -0,0 +1,105 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// volatilePlanFields differ between runs of the same plan and are never
+// compared by ComparePlans.
+var volatilePlanFields = map[string]bool{
+	"Actual Startup Time": true,
+	"Actual Total Time":   true,
+	"Planning Time":       true,
+	"Execution Time":      true,
+	"Planning":            true,
+	"JIT":                 true,
+	"I/O Read Time":       true,
+	"I/O Write Time":      true,
+}
+
+// costPlanFields hold the planner's estimates, skipped by ComparePlans when
+// PlanCompareOptions.IgnoreCosts is set.
+var costPlanFields = map[string]bool{
+	"Startup Cost": true,
+	"Total Cost":   true,
+	"Plan Rows":    true,
+	"Plan Width":   true,
+}
+
+// PlanCompareOptions configures ComparePlans.
+type PlanCompareOptions struct {
+	// IgnoreCosts skips the planner's cost, row and width estimates, which
+	// move with table statistics without the plan changing shape.
+	IgnoreCosts bool
+
+	// IgnoreBuffers skips buffer usage counters reported by
+	// EXPLAIN (BUFFERS), which depend on what is already cached.
+	IgnoreBuffers bool
+
+	// IgnoreFields lists further plan fields to skip, such as "Actual Rows".
+	IgnoreFields []string
+}
+
+// FetchPlan returns the plan PostgreSQL chooses for op's statement with
+// params bound, as the raw EXPLAIN (FORMAT JSON) document, so tests can keep
+// it as a snapshot and check it with ComparePlans. The statement is not run.
+func (a *PostgreSQLAdapter) FetchPlan(ctx context.Context, op *adapter.Operation, params map[string]interface{}) ([]byte, error) {
+	plan, err := a.ExplainQuery(ctx, op, params, false)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(plan), nil
+}
+
+// ComparePlans reports whether the EXPLAIN (FORMAT JSON) documents a and b
+// describe the same plan. Timings and other fields that change from run to
+// run are ignored, as are the fields selected by opts. Documents that are
+// not valid JSON never match.
+func ComparePlans(a, b []byte, opts PlanCompareOptions) bool {
+	var planA, planB interface{}
+	if err := json.Unmarshal(a, &planA); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(b, &planB); err != nil {
+		return false
+	}
+
+	ignored := make(map[string]bool, len(opts.IgnoreFields))
+	for _, field := range opts.IgnoreFields {
+		ignored[field] = true
+	}
+	skip := func(field string) bool {
+		return volatilePlanFields[field] || ignored[field] ||
+			(opts.IgnoreCosts && costPlanFields[field]) ||
+			(opts.IgnoreBuffers && strings.HasSuffix(field, " Blocks"))
+	}
+	return reflect.DeepEqual(normalizePlan(planA, skip), normalizePlan(planB, skip))
+}
+
+// normalizePlan returns a copy of the decoded plan v without the fields
+// for which skip returns true.
+func normalizePlan(v interface{}, skip func(string) bool) interface{} {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(node))
+		for key, value := range node {
+			if !skip(key) {
+				out[key] = normalizePlan(value, skip)
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(node))
+		for i, value := range node {
+			out[i] = normalizePlan(value, skip)
+		}
+		return out
+	default:
+		return v
+	}
+}
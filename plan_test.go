@@ -0,0 +1,94 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestComparePlans(t *testing.T) {
+	base := `[{"Plan": {"Node Type": "Index Scan", "Index Name": "users_pkey", "Total Cost": 8.3,
+		"Actual Total Time": 0.02, "Shared Hit Blocks": 3}, "Planning Time": 0.1, "Execution Time": 0.05}]`
+
+	tests := []struct {
+		name     string
+		other    string
+		opts     PlanCompareOptions
+		expected bool
+	}{
+		{
+			name: "different timings",
+			other: `[{"Plan": {"Node Type": "Index Scan", "Index Name": "users_pkey", "Total Cost": 8.3,
+				"Actual Total Time": 1.5, "Shared Hit Blocks": 3}, "Planning Time": 2.0, "Execution Time": 3.1}]`,
+			expected: true,
+		},
+		{
+			name:     "different node",
+			other:    `[{"Plan": {"Node Type": "Seq Scan", "Total Cost": 8.3, "Shared Hit Blocks": 3}}]`,
+			expected: false,
+		},
+		{
+			name:     "different costs",
+			other:    `[{"Plan": {"Node Type": "Index Scan", "Index Name": "users_pkey", "Total Cost": 9.1, "Shared Hit Blocks": 3}}]`,
+			expected: false,
+		},
+		{
+			name:     "different costs ignored",
+			other:    `[{"Plan": {"Node Type": "Index Scan", "Index Name": "users_pkey", "Total Cost": 9.1, "Shared Hit Blocks": 3}}]`,
+			opts:     PlanCompareOptions{IgnoreCosts: true},
+			expected: true,
+		},
+		{
+			name:     "different buffers ignored",
+			other:    `[{"Plan": {"Node Type": "Index Scan", "Index Name": "users_pkey", "Total Cost": 8.3, "Shared Hit Blocks": 7}}]`,
+			opts:     PlanCompareOptions{IgnoreBuffers: true},
+			expected: true,
+		},
+		{
+			name:     "ignored field",
+			other:    `[{"Plan": {"Node Type": "Index Scan", "Index Name": "users_email_key", "Total Cost": 8.3, "Shared Hit Blocks": 3}}]`,
+			opts:     PlanCompareOptions{IgnoreFields: []string{"Index Name"}},
+			expected: true,
+		},
+		{
+			name:     "invalid JSON",
+			other:    `not json`,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ComparePlans([]byte(base), []byte(tt.other), tt.opts); result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_FetchPlanWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	op := &adapter.Operation{Statement: "SELECT 1"}
+	if _, err := a.FetchPlan(context.Background(), op, nil); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_FetchPlan(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	op := &adapter.Operation{Statement: "SELECT * FROM generate_series(1, {n}) AS s(n)"}
+	first, err := a.FetchPlan(ctx, op, map[string]interface{}{"n": 10})
+	if err != nil {
+		t.Fatalf("FetchPlan failed: %v", err)
+	}
+	second, err := a.FetchPlan(ctx, op, map[string]interface{}{"n": 10})
+	if err != nil {
+		t.Fatalf("FetchPlan failed: %v", err)
+	}
+	if !ComparePlans(first, second, PlanCompareOptions{}) {
+		t.Errorf("expected identical plans, got %s and %s", first, second)
+	}
+}
@@ -0,0 +1,188 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// preparedStatementCache holds the statements prepared for
+// WithPreparedStatements, keyed by query text.
+type preparedStatementCache struct {
+	stmts sync.Map // string -> *sql.Stmt
+}
+
+// get returns the statement for query, preparing it on db on first use.
+func (c *preparedStatementCache) get(ctx context.Context, db *sql.DB, query string) (*sql.Stmt, error) {
+	if stmt, ok := c.stmts.Load(query); ok {
+		return stmt.(*sql.Stmt), nil
+	}
+
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if actual, loaded := c.stmts.LoadOrStore(query, stmt); loaded {
+		// Another goroutine prepared the same query first.
+		_ = stmt.Close()
+		return actual.(*sql.Stmt), nil
+	}
+	return stmt, nil
+}
+
+// flush closes and forgets every cached statement, returning the first
+// error encountered.
+func (c *preparedStatementCache) flush() error {
+	var firstErr error
+	c.stmts.Range(func(key, value interface{}) bool {
+		c.stmts.Delete(key)
+		if err := value.(*sql.Stmt).Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		return true
+	})
+	return firstErr
+}
+
+// FlushPreparedStatements closes every statement prepared by
+// WithPreparedStatements. Call it after schema changes that invalidate cached
+// plans, such as altering a column type.
+func (a *PostgreSQLAdapter) FlushPreparedStatements() error {
+	return a.stmtCache.flush()
+}
+
+// preparedQueryContext is queryContext for the statements of Fetch: with
+// prepared statements enabled and outside a transaction, the statement is
+// prepared once and reused.
+func (a *PostgreSQLAdapter) preparedQueryContext(ctx context.Context, name, query string, args ...interface{}) (*sql.Rows, error) {
+	if !a.preparedStatements || a.txFromContext(ctx) != nil || a.schemaRouter != nil {
+		return a.queryContext(ctx, name, query, args...)
+	}
+
+	start := time.Now()
+	stmt, err := a.stmtCache.get(ctx, a.db, a.annotate(query, name))
+	if err != nil {
+		a.logQuery(query, len(args), time.Since(start), nil, err)
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, args...)
+	a.logQuery(query, len(args), time.Since(start), nil, err)
+	return rows, err
+}
+
+// preparedExecContext is execContext for the statements of Update and Delete,
+// reusing a prepared statement like preparedQueryContext.
+func (a *PostgreSQLAdapter) preparedExecContext(ctx context.Context, name, query string, args ...interface{}) (sql.Result, error) {
+	if !a.preparedStatements || a.txFromContext(ctx) != nil || a.schemaRouter != nil {
+		return a.execContext(ctx, name, query, args...)
+	}
+
+	start := time.Now()
+	stmt, err := a.stmtCache.get(ctx, a.db, a.annotate(query, name))
+	if err != nil {
+		a.logQuery(query, len(args), time.Since(start), nil, err)
+		return nil, err
+	}
+	result, err := stmt.ExecContext(ctx, args...)
+	a.logQuery(query, len(args), time.Since(start), result, err)
+	return result, err
+}
+
+// PreparedQuery is a statement prepared once with PrepareStatement, so that
+// PostgreSQL plans it once and reuses the plan on every execution.
+type PreparedQuery struct {
+	adapter   *PostgreSQLAdapter
+	conn      *sql.Conn
+	stmt      *sql.Stmt
+	statement string
+}
+
+// PrepareStatement prepares the statement of op, written with {param}
+// placeholders like any operation statement. Close the PreparedQuery when it
+// is no longer needed.
+//
+// As everywhere else, braces are taken for placeholders even inside string
+// literals, so a JSON literal such as '{"a": 1}' must be built with
+// jsonb_build_object or passed as a parameter instead.
+//
+// With a schema router the statement is prepared for the schema routed to
+// by ctx, on a connection it keeps pinned until Close; such a PreparedQuery
+// must not be used concurrently.
+func (a *PostgreSQLAdapter) PrepareStatement(ctx context.Context, op *adapter.Operation) (*PreparedQuery, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	query := a.annotate(replaceNamedParams(op.Statement), operationName(op, "prepared"))
+	if a.schemaRouter == nil {
+		stmt, err := a.db.PrepareContext(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("postgresql: prepare failed: %w", err)
+		}
+		return &PreparedQuery{adapter: a, stmt: stmt, statement: op.Statement}, nil
+	}
+
+	conn, err := a.conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to pin connection: %w", err)
+	}
+	stmt, err := conn.PrepareContext(ctx, query)
+	if err != nil {
+		_ = a.releaseConn(conn)
+		return nil, fmt.Errorf("postgresql: prepare failed: %w", err)
+	}
+	return &PreparedQuery{adapter: a, conn: conn, stmt: stmt, statement: op.Statement}, nil
+}
+
+// Query runs the prepared statement with params bound to its placeholders,
+// inside the transaction carried by ctx if there is one.
+func (p *PreparedQuery) Query(ctx context.Context, params map[string]interface{}) (*sql.Rows, error) {
+	args, err := extractArgs(p.statement, params)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := p.stmt
+	if tx := p.adapter.txFromContext(ctx); tx != nil {
+		// Closed by database/sql when the transaction ends.
+		stmt = tx.tx.StmtContext(ctx, stmt)
+	}
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: query failed: %w", err)
+	}
+	return rows, nil
+}
+
+// Close releases the prepared statement and any connection it pins.
+func (p *PreparedQuery) Close() error {
+	err := p.stmt.Close()
+	if p.conn != nil {
+		if releaseErr := p.adapter.releaseConn(p.conn); err == nil {
+			err = releaseErr
+		}
+	}
+	return err
+}
+
+// FetchPrepared runs prepared with params and returns its rows as maps,
+// decoded exactly as Fetch decodes them. Unlike Fetch it returns an empty
+// result rather than adapter.ErrNotFound when no row matches.
+func (a *PostgreSQLAdapter) FetchPrepared(ctx context.Context, prepared *PreparedQuery, params map[string]interface{}) ([]interface{}, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	rows, err := prepared.Query(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	return a.scanRows(rows)
+}
@@ -0,0 +1,92 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestWithPreparedStatements(t *testing.T) {
+	if NewPostgreSQLAdapter().preparedStatements {
+		t.Error("expected prepared statements to be disabled by default")
+	}
+	if !NewPostgreSQLAdapter(WithPreparedStatements(true)).preparedStatements {
+		t.Error("expected prepared statements to be enabled")
+	}
+}
+
+func TestPostgreSQLAdapter_FlushPreparedStatementsEmpty(t *testing.T) {
+	a := NewPostgreSQLAdapter(WithPreparedStatements(true))
+	if err := a.FlushPreparedStatements(); err != nil {
+		t.Errorf("unexpected error flushing an empty cache: %v", err)
+	}
+}
+
+func TestPostgreSQLAdapter_PreparedStatementCache(t *testing.T) {
+	a := newIntegrationAdapter(t, WithPreparedStatements(true))
+	ctx := context.Background()
+	op := &adapter.Operation{Statement: "SELECT {n}::int AS n", Multi: true}
+
+	for i := 0; i < 3; i++ {
+		rows, err := a.Fetch(ctx, op, map[string]interface{}{"n": i})
+		if err != nil {
+			t.Fatalf("fetch %d failed: %v", i, err)
+		}
+		if n := rows[0].(map[string]interface{})["n"].(int64); n != int64(i) {
+			t.Errorf("fetch %d: expected %d, got %d", i, i, n)
+		}
+	}
+
+	cached := 0
+	a.stmtCache.stmts.Range(func(_, _ interface{}) bool { cached++; return true })
+	if cached != 1 {
+		t.Errorf("expected 1 cached statement, got %d", cached)
+	}
+
+	if err := a.FlushPreparedStatements(); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	if _, ok := a.stmtCache.stmts.Load("SELECT $1::int AS n"); ok {
+		t.Error("expected the cache to be empty after flush")
+	}
+}
+
+func TestPostgreSQLAdapter_PrepareStatementWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if _, err := a.PrepareStatement(context.Background(), &adapter.Operation{Statement: "SELECT 1"}); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+	if _, err := a.FetchPrepared(context.Background(), &PreparedQuery{}, nil); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_FetchPrepared(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	prepared, err := a.PrepareStatement(ctx, &adapter.Operation{Statement: "SELECT {n}::int AS n, jsonb_build_object('a', 1) AS doc"})
+	if err != nil {
+		t.Fatalf("prepare failed: %v", err)
+	}
+	t.Cleanup(func() { _ = prepared.Close() })
+
+	for i := 0; i < 3; i++ {
+		rows, err := a.FetchPrepared(ctx, prepared, map[string]interface{}{"n": i})
+		if err != nil {
+			t.Fatalf("fetch %d failed: %v", i, err)
+		}
+		row := rows[0].(map[string]interface{})
+		if n := row["n"].(int64); n != int64(i) {
+			t.Errorf("fetch %d: expected %d, got %d", i, i, n)
+		}
+		if _, ok := row["doc"].(map[string]interface{}); !ok {
+			t.Errorf("fetch %d: expected decoded JSON, got %T", i, row["doc"])
+		}
+	}
+
+	if _, err := a.FetchPrepared(ctx, prepared, nil); err == nil {
+		t.Error("expected error for a missing parameter, got nil")
+	}
+}
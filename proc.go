@@ -0,0 +1,151 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// procedureVersion is the first server version (server_version_num) with
+// procedures and CALL.
+const procedureVersion = 110000
+
+// ExecuteProc runs the function or procedure name with the in values bound
+// by parameter name, and returns the out values of the single row it
+// produces, keyed by name. name may be schema-qualified.
+//
+// Functions are run as SELECT * FROM name(p => $1, ...), procedures
+// (PostgreSQL 11 and later) as CALL name(p => $1, ...). A procedure's OUT
+// and INOUT parameters must be listed in out; those not also in in are
+// passed as NULL, as CALL requires. An empty out returns every column of
+// the row. A routine that returns no row yields an empty map.
+func (a *PostgreSQLAdapter) ExecuteProc(ctx context.Context, name string, in map[string]interface{}, out []string) (map[string]interface{}, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+	if err := validateIdentifier(name); err != nil {
+		return nil, err
+	}
+
+	procedure, err := a.isProcedure(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	query, args, err := procQuery(name, in, out, procedure)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := a.queryContext(ctx, "execute_proc", query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to execute %s: %w", name, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	results, err := a.scanRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(results) == 0:
+		return map[string]interface{}{}, nil
+	case len(results) > 1:
+		return nil, fmt.Errorf("postgresql: %s returned %d rows, expected one", name, len(results))
+	}
+
+	row := results[0].(map[string]interface{})
+	if len(out) == 0 {
+		return row, nil
+	}
+	values := make(map[string]interface{}, len(out))
+	for _, param := range out {
+		value, ok := row[param]
+		if !ok {
+			return nil, fmt.Errorf("postgresql: %s has no output named %s", name, param)
+		}
+		values[param] = value
+	}
+	return values, nil
+}
+
+// isProcedure reports whether name resolves to a procedure rather than a
+// function. Servers before PostgreSQL 11 only have functions.
+func (a *PostgreSQLAdapter) isProcedure(ctx context.Context, name string) (bool, error) {
+	version, err := a.ServerVersion(ctx)
+	if err != nil {
+		return false, err
+	}
+	if version < procedureVersion {
+		return false, nil
+	}
+
+	schema, proc := "", name
+	if s, p, ok := strings.Cut(name, "."); ok {
+		schema, proc = s, p
+	}
+
+	query := `SELECT p.prokind = 'p'
+		FROM pg_proc p
+		JOIN pg_namespace n ON n.oid = p.pronamespace
+		WHERE p.proname = $2 AND CASE WHEN $1 = '' THEN pg_function_is_visible(p.oid) ELSE n.nspname = $1 END
+		LIMIT 1`
+
+	var procedure bool
+	err = a.queryRowContext(ctx, "execute_proc", query, schema, proc).Scan(&procedure)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("postgresql: function or procedure %s does not exist", name)
+	}
+	if err != nil {
+		return false, fmt.Errorf("postgresql: failed to look up %s: %w", name, err)
+	}
+	return procedure, nil
+}
+
+// procQuery builds the statement run by ExecuteProc, binding in by name in
+// sorted order.
+func procQuery(name string, in map[string]interface{}, out []string, procedure bool) (string, []interface{}, error) {
+	params := make([]string, 0, len(in))
+	for param := range in {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+
+	var args []interface{}
+	var bound []string
+	for _, param := range params {
+		if err := validateParamName(param); err != nil {
+			return "", nil, err
+		}
+		arg, err := bindArg(in[param])
+		if err != nil {
+			return "", nil, fmt.Errorf("postgresql: parameter %s: %w", param, err)
+		}
+		args = append(args, arg)
+		bound = append(bound, fmt.Sprintf("%s => $%d", QuoteIdentifier(param), len(args)))
+	}
+
+	if !procedure {
+		return fmt.Sprintf("SELECT * FROM %s(%s)", QuoteIdentifier(name), strings.Join(bound, ", ")), args, nil
+	}
+
+	for _, param := range out {
+		if err := validateParamName(param); err != nil {
+			return "", nil, err
+		}
+		if _, ok := in[param]; !ok {
+			bound = append(bound, QuoteIdentifier(param)+" => NULL")
+		}
+	}
+	return fmt.Sprintf("CALL %s(%s)", QuoteIdentifier(name), strings.Join(bound, ", ")), args, nil
+}
+
+// validateParamName rejects parameter names that are not plain identifiers.
+func validateParamName(name string) error {
+	if strings.Contains(name, ".") {
+		return fmt.Errorf("postgresql: invalid parameter name %q", name)
+	}
+	return validateIdentifier(name)
+}
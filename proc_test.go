@@ -0,0 +1,103 @@
+package postgresql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestProcQuery(t *testing.T) {
+	tests := []struct {
+		name         string
+		in           map[string]interface{}
+		out          []string
+		procedure    bool
+		expected     string
+		expectedArgs []interface{}
+		expectErr    bool
+	}{
+		{
+			name:         "function",
+			in:           map[string]interface{}{"b": 2, "a": 1},
+			out:          []string{"total"},
+			expected:     `SELECT * FROM "billing"."add"("a" => $1, "b" => $2)`,
+			expectedArgs: []interface{}{1, 2},
+		},
+		{
+			name:         "procedure with out parameters",
+			in:           map[string]interface{}{"a": 1, "acc": 0},
+			out:          []string{"total", "acc"},
+			procedure:    true,
+			expected:     `CALL "billing"."add"("a" => $1, "acc" => $2, "total" => NULL)`,
+			expectedArgs: []interface{}{1, 0},
+		},
+		{
+			name:      "no arguments",
+			procedure: true,
+			expected:  `CALL "billing"."add"()`,
+		},
+		{
+			name:      "invalid parameter",
+			in:        map[string]interface{}{"a); DROP TABLE x; --": 1},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := procQuery("billing.add", tt.in, tt.out, tt.procedure)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got %q", query)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if query != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, query)
+			}
+			if !reflect.DeepEqual(args, tt.expectedArgs) {
+				t.Errorf("expected args %v, got %v", tt.expectedArgs, args)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_ExecuteProcWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if _, err := a.ExecuteProc(context.Background(), "add", nil, nil); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_ExecuteProc(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, `
+		CREATE FUNCTION proc_test_add(a int, b int, OUT total int) AS 'SELECT a + b' LANGUAGE sql;
+		CREATE PROCEDURE proc_test_double(a int, INOUT result int) LANGUAGE plpgsql AS 'BEGIN result := a * 2; END'`); err != nil {
+		t.Fatalf("failed to create routines: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = a.db.Exec("DROP FUNCTION proc_test_add; DROP PROCEDURE proc_test_double")
+	})
+
+	out, err := a.ExecuteProc(ctx, "proc_test_add", map[string]interface{}{"a": 2, "b": 3}, []string{"total"})
+	if err != nil {
+		t.Fatalf("function failed: %v", err)
+	}
+	if out["total"] != int64(5) {
+		t.Errorf("expected total 5, got %v", out)
+	}
+
+	out, err = a.ExecuteProc(ctx, "proc_test_double", map[string]interface{}{"a": 4}, []string{"result"})
+	if err != nil {
+		t.Fatalf("procedure failed: %v", err)
+	}
+	if out["result"] != int64(8) {
+		t.Errorf("expected result 8, got %v", out)
+	}
+}
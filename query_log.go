@@ -0,0 +1,87 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// WithLogger logs every statement the adapter sends to l: at DEBUG level
+// with the query, params_count, duration_ms and, for statements that return
+// no rows, rows_affected attributes, and at ERROR level with the error when
+// the statement fails. Parameter values are never logged.
+func WithLogger(l *slog.Logger) Option {
+	return func(a *PostgreSQLAdapter) {
+		a.logger = l
+	}
+}
+
+// WithSlowQueryThreshold logs statements that take longer than d at WARN
+// level with the full statement, to the logger set by WithLogger or to the
+// default slog logger. Zero disables slow query logging.
+func WithSlowQueryThreshold(d time.Duration) Option {
+	return func(a *PostgreSQLAdapter) {
+		a.slowQueryThreshold = d
+	}
+}
+
+// queryLogging reports whether statements are logged at all.
+func (a *PostgreSQLAdapter) queryLogging() bool {
+	return a.logger != nil || a.slowQueryThreshold > 0
+}
+
+// logQuery logs a statement that took elapsed to run. result is nil for
+// statements that return rows.
+func (a *PostgreSQLAdapter) logQuery(query string, params int, elapsed time.Duration, result sql.Result, err error) {
+	if !a.queryLogging() {
+		return
+	}
+
+	attrs := []any{
+		"query", query,
+		"params_count", params,
+		"duration_ms", float64(elapsed.Microseconds()) / 1000,
+	}
+	if result != nil && err == nil {
+		if n, rowsErr := result.RowsAffected(); rowsErr == nil {
+			attrs = append(attrs, "rows_affected", n)
+		}
+	}
+
+	logger := a.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	switch {
+	case err != nil:
+		if a.logger != nil {
+			logger.Error("postgresql: query failed", append(attrs, "error", err)...)
+		}
+	case a.slowQueryThreshold > 0 && elapsed > a.slowQueryThreshold:
+		logger.Warn("postgresql: slow query", attrs...)
+	case a.logger != nil:
+		logger.Debug("postgresql: query", attrs...)
+	}
+}
+
+// loggedRow logs the statement of a queryRowContext call once its result
+// is scanned, since *sql.Row only reports errors from Scan.
+type loggedRow struct {
+	adapter *PostgreSQLAdapter
+	row     *sql.Row
+	query   string
+	params  int
+	start   time.Time
+}
+
+// Scan scans the row and logs the statement.
+func (r *loggedRow) Scan(dest ...interface{}) error {
+	err := r.row.Scan(dest...)
+	logErr := err
+	if errors.Is(err, sql.ErrNoRows) {
+		logErr = nil
+	}
+	r.adapter.logQuery(r.query, r.params, time.Since(r.start), nil, logErr)
+	return err
+}
@@ -0,0 +1,103 @@
+package postgresql
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// fakeResult is a sql.Result reporting a fixed number of affected rows.
+type fakeResult int64
+
+func (r fakeResult) LastInsertId() (int64, error) { return 0, errors.New("not supported") }
+func (r fakeResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+func TestLogQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		logger    bool
+		threshold time.Duration
+		elapsed   time.Duration
+		err       error
+		expected  []string
+	}{
+		{
+			name:     "debug",
+			logger:   true,
+			elapsed:  time.Millisecond,
+			expected: []string{"level=DEBUG", `query="UPDATE users SET name = $1"`, "params_count=1", "duration_ms=1", "rows_affected=3"},
+		},
+		{
+			name:     "error",
+			logger:   true,
+			err:      errors.New("boom"),
+			expected: []string{"level=ERROR", "error=boom"},
+		},
+		{
+			name:      "slow",
+			logger:    true,
+			threshold: time.Millisecond,
+			elapsed:   2 * time.Millisecond,
+			expected:  []string{"level=WARN", "postgresql: slow query", `query="UPDATE users SET name = $1"`},
+		},
+		{
+			name:      "fast",
+			threshold: time.Second,
+			elapsed:   time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			opts := []Option{WithSlowQueryThreshold(tt.threshold)}
+			if tt.logger {
+				opts = append(opts, WithLogger(logger))
+			}
+			a := NewPostgreSQLAdapter(opts...)
+
+			a.logQuery("UPDATE users SET name = $1", 1, tt.elapsed, fakeResult(3), tt.err)
+
+			out := buf.String()
+			if len(tt.expected) == 0 && out != "" {
+				t.Errorf("expected no output, got %q", out)
+			}
+			for _, want := range tt.expected {
+				if !strings.Contains(out, want) {
+					t.Errorf("expected %q in %q", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestQueryLogging(t *testing.T) {
+	if NewPostgreSQLAdapter().queryLogging() {
+		t.Error("expected logging to be disabled by default")
+	}
+	if !NewPostgreSQLAdapter(WithSlowQueryThreshold(time.Second)).queryLogging() {
+		t.Error("expected logging to be enabled with a slow query threshold")
+	}
+}
+
+func TestPostgreSQLAdapter_QueryLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	a := newIntegrationAdapter(t, WithLogger(logger))
+	ctx := context.Background()
+
+	op := &adapter.Operation{Statement: "SELECT {n}::int AS n"}
+	if _, err := a.Fetch(ctx, op, map[string]interface{}{"n": 1}); err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `query="SELECT $1::int AS n"`) {
+		t.Errorf("expected query to be logged, got %q", buf.String())
+	}
+}
@@ -0,0 +1,91 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// QueryTemplate builds SQL whose shape depends on its parameters, such as
+// optional WHERE filters, using text/template syntax:
+//
+//	SELECT * FROM users WHERE active = {active}
+//	{{if .email}}AND email = {email}{{end}}
+//
+// Templates may only decide which SQL text is emitted; values always reach
+// the server through {param} placeholders. Actions that print a value, like
+// {{.email}}, are rejected when the template is parsed so a template can
+// never interpolate caller input into the SQL string.
+type QueryTemplate struct {
+	tmpl *template.Template
+}
+
+// NewQueryTemplate parses and validates text as a query template.
+func NewQueryTemplate(name, text string) (*QueryTemplate, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: invalid query template %s: %w", name, err)
+	}
+
+	if err := checkTemplateNodes(tmpl.Tree.Root); err != nil {
+		return nil, fmt.Errorf("postgresql: invalid query template %s: %w", name, err)
+	}
+
+	return &QueryTemplate{tmpl: tmpl}, nil
+}
+
+// Render executes the template against params and returns the resulting
+// PostgreSQL query with its positional arguments.
+func (q *QueryTemplate) Render(params map[string]interface{}) (string, []interface{}, error) {
+	var buf strings.Builder
+	if err := q.tmpl.Execute(&buf, params); err != nil {
+		return "", nil, fmt.Errorf("postgresql: failed to render query template %s: %w", q.tmpl.Name(), err)
+	}
+
+	rendered := buf.String()
+	args, err := extractArgs(rendered, params)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return replaceNamedParams(rendered), args, nil
+}
+
+// checkTemplateNodes rejects any node that would write a value into the
+// rendered SQL rather than choosing between fixed pieces of text.
+func checkTemplateNodes(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkTemplateNodes(child); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		// Variable declarations ({{$x := .y}}) print nothing.
+		if len(n.Pipe.Decl) == 0 {
+			return fmt.Errorf("action %s interpolates a value into SQL; use a {param} placeholder instead", n)
+		}
+	case *parse.IfNode:
+		return checkBranchNodes(n.List, n.ElseList)
+	case *parse.RangeNode:
+		return checkBranchNodes(n.List, n.ElseList)
+	case *parse.WithNode:
+		return checkBranchNodes(n.List, n.ElseList)
+	case *parse.TemplateNode:
+		return fmt.Errorf("nested template %q is not supported", n.Name)
+	}
+	return nil
+}
+
+// checkBranchNodes validates the bodies of an if, range or with block.
+func checkBranchNodes(list, elseList *parse.ListNode) error {
+	if err := checkTemplateNodes(list); err != nil {
+		return err
+	}
+	return checkTemplateNodes(elseList)
+}
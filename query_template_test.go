@@ -0,0 +1,95 @@
+package postgresql
+
+import "testing"
+
+func TestQueryTemplate_Render(t *testing.T) {
+	qt, err := NewQueryTemplate("users",
+		"SELECT * FROM users WHERE active = {active}{{if .email}} AND email = {email}{{end}}{{if .name}} AND name = {name}{{end}}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		params        map[string]interface{}
+		expectedQuery string
+		expectedArgs  []interface{}
+	}{
+		{
+			name:          "no optional filters",
+			params:        map[string]interface{}{"active": true},
+			expectedQuery: "SELECT * FROM users WHERE active = $1",
+			expectedArgs:  []interface{}{true},
+		},
+		{
+			name:          "one optional filter",
+			params:        map[string]interface{}{"active": true, "name": "Alice"},
+			expectedQuery: "SELECT * FROM users WHERE active = $1 AND name = $2",
+			expectedArgs:  []interface{}{true, "Alice"},
+		},
+		{
+			name:          "all filters",
+			params:        map[string]interface{}{"active": false, "email": "a@example.com", "name": "Alice"},
+			expectedQuery: "SELECT * FROM users WHERE active = $1 AND email = $2 AND name = $3",
+			expectedArgs:  []interface{}{false, "a@example.com", "Alice"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := qt.Render(tt.params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("expected %q, got %q", tt.expectedQuery, query)
+			}
+			if len(args) != len(tt.expectedArgs) {
+				t.Fatalf("expected %d args, got %d", len(tt.expectedArgs), len(args))
+			}
+			for i, exp := range tt.expectedArgs {
+				if args[i] != exp {
+					t.Errorf("arg %d: expected %v, got %v", i, exp, args[i])
+				}
+			}
+		})
+	}
+}
+
+func TestQueryTemplate_MissingParameter(t *testing.T) {
+	qt, err := NewQueryTemplate("users", "SELECT * FROM users WHERE id = {id}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := qt.Render(map[string]interface{}{}); err == nil {
+		t.Error("expected error for missing parameter, got nil")
+	}
+}
+
+func TestNewQueryTemplate_RejectsInterpolation(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"top level", "SELECT * FROM users WHERE name = '{{.name}}'"},
+		{"inside if", "SELECT * FROM users{{if .name}} WHERE name = '{{.name}}'{{end}}"},
+		{"inside else", "SELECT * FROM users{{if .id}} WHERE id = {id}{{else}} LIMIT {{.limit}}{{end}}"},
+		{"inside range", "SELECT 1{{range .cols}}, {{.}}{{end}}"},
+		{"nested template", `{{define "x"}}1{{end}}SELECT {{template "x"}}`},
+		{"syntax error", "SELECT * FROM users{{if .id}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewQueryTemplate("bad", tt.text); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestNewQueryTemplate_AllowsDeclarations(t *testing.T) {
+	if _, err := NewQueryTemplate("ok", "{{$f := .filter}}SELECT 1{{if $f}} WHERE x = {filter}{{end}}"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
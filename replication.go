@@ -0,0 +1,234 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// replicaLagPollInterval is how often WaitForReplicaLag re-reads the lag.
+const replicaLagPollInterval = 100 * time.Millisecond
+
+// EmitReplicationMessage writes an application-defined message into the WAL
+// with pg_logical_emit_message so logical decoding consumers receive it
+// alongside row changes. A transactional message is only decoded if the
+// surrounding transaction commits; a non-transactional one is decoded
+// immediately. The returned value is the LSN at which the message was
+// written.
+func (a *PostgreSQLAdapter) EmitReplicationMessage(ctx context.Context, prefix string, message []byte, transactional bool) (int64, error) {
+	if a.db == nil {
+		return 0, ErrNotConnected
+	}
+
+	var lsn string
+	query := "SELECT pg_logical_emit_message($1::boolean, $2, $3::bytea)::text"
+	if err := a.queryRowContext(ctx, "emit_replication_message", query, transactional, prefix, message).Scan(&lsn); err != nil {
+		return 0, fmt.Errorf("postgresql: failed to emit replication message: %w", err)
+	}
+
+	return parseLSN(lsn)
+}
+
+// RawChange is one change decoded from a logical replication slot by
+// its output plugin.
+type RawChange struct {
+	// LSN is the WAL position of the change.
+	LSN int64
+	// XID is the ID of the transaction that made the change.
+	XID uint32
+	// Data is the change in the output plugin's format.
+	Data string
+}
+
+// PeekChanges returns up to limit changes pending in the logical
+// replication slot slotName, using pg_logical_slot_peek_changes. Unlike
+// StreamLogical, it does not consume them: the slot keeps its position, so
+// the same changes are returned again by the next call. This makes it
+// suited to testing and debugging change data capture. A limit of zero or
+// less returns every pending change. The slot must use a textual output
+// plugin such as test_decoding or wal2json.
+func (a *PostgreSQLAdapter) PeekChanges(ctx context.Context, slotName string, limit int) ([]RawChange, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	var upTo interface{}
+	if limit > 0 {
+		upTo = limit
+	}
+	query := "SELECT lsn::text, xid::text, data FROM pg_logical_slot_peek_changes($1, NULL, $2)"
+	rows, err := a.queryContext(ctx, "peek_changes", query, slotName, upTo)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to peek changes: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	changes := []RawChange{}
+	for rows.Next() {
+		var lsn, xid string
+		var c RawChange
+		if err := rows.Scan(&lsn, &xid, &c.Data); err != nil {
+			return nil, fmt.Errorf("postgresql: failed to scan change: %w", err)
+		}
+		if c.LSN, err = parseLSN(lsn); err != nil {
+			return nil, err
+		}
+		n, err := strconv.ParseUint(xid, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("postgresql: invalid transaction ID %q", xid)
+		}
+		c.XID = uint32(n)
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgresql: failed to peek changes: %w", err)
+	}
+	return changes, nil
+}
+
+// MisconfiguredSetting is a server setting that does not meet a requirement.
+type MisconfiguredSetting struct {
+	Name     string
+	Value    string
+	Required string
+}
+
+// ReplicationConfigError reports the server settings that prevent logical
+// replication. They can only be changed in postgresql.conf or with ALTER
+// SYSTEM, followed by a server restart.
+type ReplicationConfigError struct {
+	Settings []MisconfiguredSetting
+}
+
+// Error lists every misconfigured setting.
+func (e *ReplicationConfigError) Error() string {
+	parts := make([]string, len(e.Settings))
+	for i, s := range e.Settings {
+		parts[i] = fmt.Sprintf("%s = %s (need %s)", s.Name, s.Value, s.Required)
+	}
+	return "postgresql: server not configured for logical replication: " + strings.Join(parts, "; ")
+}
+
+// CheckLogicalReplicationPrerequisites verifies that the server is set up for
+// logical replication: wal_level must be logical, and max_wal_senders and
+// max_replication_slots must be positive. Unmet requirements are reported as
+// a *ReplicationConfigError.
+func (a *PostgreSQLAdapter) CheckLogicalReplicationPrerequisites(ctx context.Context) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	var walLevel, walSenders, replicationSlots string
+	query := `SELECT current_setting('wal_level'),
+		current_setting('max_wal_senders'),
+		current_setting('max_replication_slots')`
+	if err := a.queryRowContext(ctx, "check_replication_prerequisites", query).Scan(&walLevel, &walSenders, &replicationSlots); err != nil {
+		return fmt.Errorf("postgresql: failed to read replication settings: %w", err)
+	}
+
+	return checkReplicationSettings(walLevel, walSenders, replicationSlots)
+}
+
+// checkReplicationSettings validates the values read by
+// CheckLogicalReplicationPrerequisites.
+func checkReplicationSettings(walLevel, walSenders, replicationSlots string) error {
+	var bad []MisconfiguredSetting
+	if walLevel != "logical" {
+		bad = append(bad, MisconfiguredSetting{Name: "wal_level", Value: walLevel, Required: "logical"})
+	}
+	if n, err := strconv.Atoi(walSenders); err != nil || n <= 0 {
+		bad = append(bad, MisconfiguredSetting{Name: "max_wal_senders", Value: walSenders, Required: "> 0"})
+	}
+	if n, err := strconv.Atoi(replicationSlots); err != nil || n <= 0 {
+		bad = append(bad, MisconfiguredSetting{Name: "max_replication_slots", Value: replicationSlots, Required: "> 0"})
+	}
+
+	if len(bad) > 0 {
+		return &ReplicationConfigError{Settings: bad}
+	}
+	return nil
+}
+
+// parseLSN converts the textual pg_lsn form "XXX/YYY" (two hexadecimal
+// halves) into its 64-bit position.
+func parseLSN(lsn string) (int64, error) {
+	hi, lo, ok := strings.Cut(lsn, "/")
+	if !ok {
+		return 0, fmt.Errorf("postgresql: invalid LSN %q", lsn)
+	}
+
+	high, err := strconv.ParseUint(hi, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("postgresql: invalid LSN %q: %w", lsn, err)
+	}
+	low, err := strconv.ParseUint(lo, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("postgresql: invalid LSN %q: %w", lsn, err)
+	}
+
+	return int64(high<<32 | low), nil
+}
+
+// Checkpoint makes recent writes durable and visible where tests look for
+// them. On a primary it runs CHECKPOINT, which needs superuser or, from
+// PostgreSQL 15, the pg_checkpoint role. On a standby, where CHECKPOINT
+// only creates a restartpoint, it resumes WAL replay with
+// pg_wal_replay_resume in case replay was paused.
+func (a *PostgreSQLAdapter) Checkpoint(ctx context.Context) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	var inRecovery bool
+	if err := a.queryRowContext(ctx, "checkpoint", "SELECT pg_is_in_recovery()").Scan(&inRecovery); err != nil {
+		return fmt.Errorf("postgresql: failed to read recovery state: %w", err)
+	}
+
+	query := "CHECKPOINT"
+	if inRecovery {
+		query = "SELECT pg_wal_replay_resume()"
+	}
+	if _, err := a.execContext(ctx, "checkpoint", query); err != nil {
+		return fmt.Errorf("postgresql: checkpoint failed: %w", err)
+	}
+	return nil
+}
+
+// WaitForReplicaLag polls pg_stat_replication on the primary until every
+// connected standby has replayed WAL to within maxLagBytes of the current
+// position, or fails once timeout has passed. A standby that has not
+// reported a replay position yet counts as fully lagging. With no standby
+// connected it returns immediately.
+func (a *PostgreSQLAdapter) WaitForReplicaLag(ctx context.Context, maxLagBytes int64, timeout time.Duration) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	query := `SELECT coalesce(max(pg_wal_lsn_diff(pg_current_wal_lsn(), coalesce(replay_lsn, '0/0'))), 0)::bigint
+		FROM pg_stat_replication`
+
+	ticker := time.NewTicker(replicaLagPollInterval)
+	defer ticker.Stop()
+
+	for {
+		var lag int64
+		err := a.queryRowContext(ctx, "wait_for_replica_lag", query).Scan(&lag)
+		if err != nil && ctx.Err() == nil {
+			return fmt.Errorf("postgresql: failed to read replica lag: %w", err)
+		}
+		if err == nil && lag <= maxLagBytes {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("postgresql: replicas still %d bytes behind after %s: %w", lag, timeout, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
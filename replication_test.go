@@ -0,0 +1,142 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLSN(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  int64
+		expectErr bool
+	}{
+		{input: "0/0", expected: 0},
+		{input: "0/16B3748", expected: 0x16B3748},
+		{input: "1/0", expected: 1 << 32},
+		{input: "A/FF", expected: 0xA<<32 | 0xFF},
+		{input: "16B3748", expectErr: true},
+		{input: "G/1", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		result, err := parseLSN(tt.input)
+		if tt.expectErr {
+			if err == nil {
+				t.Errorf("parseLSN(%q): expected error, got nil", tt.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseLSN(%q): unexpected error: %v", tt.input, err)
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("parseLSN(%q): expected %d, got %d", tt.input, tt.expected, result)
+		}
+	}
+}
+
+func TestPostgreSQLAdapter_EmitReplicationMessageWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if _, err := a.EmitReplicationMessage(context.Background(), "app", []byte("hello"), true); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestCheckReplicationSettings(t *testing.T) {
+	if err := checkReplicationSettings("logical", "10", "10"); err != nil {
+		t.Errorf("expected valid settings, got %v", err)
+	}
+
+	err := checkReplicationSettings("replica", "0", "10")
+	var cfgErr *ReplicationConfigError
+	if !errors.As(err, &cfgErr) {
+		t.Fatalf("expected *ReplicationConfigError, got %v", err)
+	}
+
+	expected := []MisconfiguredSetting{
+		{Name: "wal_level", Value: "replica", Required: "logical"},
+		{Name: "max_wal_senders", Value: "0", Required: "> 0"},
+	}
+	if !reflect.DeepEqual(cfgErr.Settings, expected) {
+		t.Errorf("expected %+v, got %+v", expected, cfgErr.Settings)
+	}
+
+	msg := "postgresql: server not configured for logical replication: wal_level = replica (need logical); max_wal_senders = 0 (need > 0)"
+	if err.Error() != msg {
+		t.Errorf("expected %q, got %q", msg, err.Error())
+	}
+}
+
+func TestPostgreSQLAdapter_CheckLogicalReplicationPrerequisitesWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if err := a.CheckLogicalReplicationPrerequisites(context.Background()); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_CheckpointWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if err := a.Checkpoint(context.Background()); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+	if err := a.WaitForReplicaLag(context.Background(), 0, time.Second); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_WaitForReplicaLag(t *testing.T) {
+	a := newIntegrationAdapter(t)
+
+	// The test server has no standbys, so there is no lag to wait for.
+	if err := a.WaitForReplicaLag(context.Background(), 0, time.Second); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPostgreSQLAdapter_PeekChangesWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if _, err := a.PeekChanges(context.Background(), "app_slot", 10); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_PeekChanges(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if err := a.CheckLogicalReplicationPrerequisites(ctx); err != nil {
+		t.Skipf("server not configured for logical replication: %v", err)
+	}
+	if _, err := a.db.ExecContext(ctx, "SELECT pg_create_logical_replication_slot('peek_changes_test', 'test_decoding')"); err != nil {
+		t.Skipf("failed to create replication slot: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("SELECT pg_drop_replication_slot('peek_changes_test')") })
+
+	for _, msg := range []string{"first", "second"} {
+		if _, err := a.EmitReplicationMessage(ctx, "peek_test", []byte(msg), false); err != nil {
+			t.Fatalf("failed to emit message: %v", err)
+		}
+	}
+
+	changes, err := a.PeekChanges(ctx, "peek_changes_test", 1)
+	if err != nil {
+		t.Fatalf("peek failed: %v", err)
+	}
+	if len(changes) != 1 || !strings.Contains(changes[0].Data, "first") {
+		t.Fatalf("expected the first message, got %+v", changes)
+	}
+
+	again, err := a.PeekChanges(ctx, "peek_changes_test", 0)
+	if err != nil {
+		t.Fatalf("peek failed: %v", err)
+	}
+	if len(again) != 2 || again[0] != changes[0] {
+		t.Errorf("expected peeking to leave both messages in the slot, got %+v", again)
+	}
+}
@@ -0,0 +1,125 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// tablesampleMinRows is the estimated table size from which FetchRandom
+// samples with TABLESAMPLE rather than sorting every row.
+const tablesampleMinRows = 100000
+
+// tablesampleOversample is how many times more rows than requested
+// TABLESAMPLE aims to pick, so that the sample rarely comes up short.
+const tablesampleOversample = 3
+
+// ApproxRowCount returns the planner's estimate of the number of rows in
+// table, from pg_class.reltuples, without scanning it. The estimate is
+// refreshed by VACUUM, ANALYZE and CREATE INDEX; a table never analyzed
+// yields -1. table may be schema-qualified.
+func (a *PostgreSQLAdapter) ApproxRowCount(ctx context.Context, table string) (int64, error) {
+	if a.db == nil {
+		return 0, ErrNotConnected
+	}
+	if err := validateIdentifier(table); err != nil {
+		return 0, err
+	}
+
+	var count int64
+	query := "SELECT reltuples::bigint FROM pg_class WHERE oid = $1::regclass"
+	if err := a.queryRowContext(ctx, "approx_row_count", query, QuoteIdentifier(table)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("postgresql: failed to estimate rows of %s: %w", table, err)
+	}
+	return count, nil
+}
+
+// FetchRandom returns up to n rows picked at random from the results of
+// op's statement with params bound, by sorting them on random().
+//
+// When op.Statement is a bare table name, tables estimated (see
+// ApproxRowCount) at 100,000 rows or more are first sampled with
+// TABLESAMPLE BERNOULLI, which reads every page but avoids sorting the
+// whole table. The sample aims at three times n rows, so it yields fewer
+// than n only rarely. Set UseTablesample in the operation options of ctx
+// to force either approach; TABLESAMPLE needs a bare table name.
+func (a *PostgreSQLAdapter) FetchRandom(ctx context.Context, op *adapter.Operation, params map[string]interface{}, n int) ([]interface{}, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+	if n < 1 {
+		return nil, fmt.Errorf("postgresql: invalid sample size %d", n)
+	}
+
+	isTable := validateIdentifier(op.Statement) == nil
+	useTablesample := OperationOptionsFromContext(ctx).UseTablesample
+	if useTablesample != nil && *useTablesample && !isTable {
+		return nil, fmt.Errorf("postgresql: TABLESAMPLE needs a table name, got %q", op.Statement)
+	}
+
+	var query string
+	var args []interface{}
+	if isTable {
+		percent := 100.0
+		if useTablesample == nil || *useTablesample {
+			rows, err := a.ApproxRowCount(ctx, op.Statement)
+			if err != nil {
+				return nil, err
+			}
+			if rows >= tablesampleMinRows || (useTablesample != nil && rows > 0) {
+				percent = samplePercent(n, rows)
+			}
+		}
+		query, args = randomTableQuery(op.Statement, percent, n)
+	} else {
+		var err error
+		if args, err = extractArgs(op.Statement, params); err != nil {
+			return nil, err
+		}
+		query = randomQuery(replaceNamedParams(op.Statement), len(args))
+		args = append(args, n)
+	}
+
+	rows, err := a.queryContext(ctx, operationName(op, "fetch_random"), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: query failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	results, err := a.scanRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []interface{}{}
+	}
+	return results, nil
+}
+
+// samplePercent returns the TABLESAMPLE percentage that picks about
+// tablesampleOversample times n of rows, capped at 100.
+func samplePercent(n int, rows int64) float64 {
+	if rows <= 0 {
+		return 100
+	}
+	return math.Min(100, float64(n)*tablesampleOversample*100/float64(rows))
+}
+
+// randomTableQuery selects n random rows of table, sampling percent of it
+// first unless percent is 100.
+func randomTableQuery(table string, percent float64, n int) (string, []interface{}) {
+	if percent >= 100 {
+		return fmt.Sprintf("SELECT * FROM %s ORDER BY random() LIMIT $1", QuoteIdentifier(table)), []interface{}{n}
+	}
+	return fmt.Sprintf("SELECT * FROM %s TABLESAMPLE BERNOULLI ($1) ORDER BY random() LIMIT $2", QuoteIdentifier(table)),
+		[]interface{}{percent, n}
+}
+
+// randomQuery wraps query, which takes argCount parameters, so it returns
+// a random selection of its rows limited by one more parameter.
+func randomQuery(query string, argCount int) string {
+	return fmt.Sprintf("SELECT * FROM (%s) AS _random ORDER BY random() LIMIT $%d", strings.TrimRight(strings.TrimSpace(query), ";"), argCount+1)
+}
@@ -0,0 +1,102 @@
+package postgresql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestSamplePercent(t *testing.T) {
+	tests := []struct {
+		n        int
+		rows     int64
+		expected float64
+	}{
+		{n: 10, rows: 1000000, expected: 0.003},
+		{n: 10, rows: 20, expected: 100},
+		{n: 10, rows: -1, expected: 100},
+	}
+
+	for _, tt := range tests {
+		if result := samplePercent(tt.n, tt.rows); result != tt.expected {
+			t.Errorf("samplePercent(%d, %d): expected %v, got %v", tt.n, tt.rows, tt.expected, result)
+		}
+	}
+}
+
+func TestRandomTableQuery(t *testing.T) {
+	query, args := randomTableQuery("app.events", 100, 5)
+	if expected := `SELECT * FROM "app"."events" ORDER BY random() LIMIT $1`; query != expected {
+		t.Errorf("expected %q, got %q", expected, query)
+	}
+	if !reflect.DeepEqual(args, []interface{}{5}) {
+		t.Errorf("unexpected args: %v", args)
+	}
+
+	query, args = randomTableQuery("events", 0.5, 5)
+	if expected := `SELECT * FROM "events" TABLESAMPLE BERNOULLI ($1) ORDER BY random() LIMIT $2`; query != expected {
+		t.Errorf("expected %q, got %q", expected, query)
+	}
+	if !reflect.DeepEqual(args, []interface{}{0.5, 5}) {
+		t.Errorf("unexpected args: %v", args)
+	}
+}
+
+func TestRandomQuery(t *testing.T) {
+	expected := "SELECT * FROM (SELECT * FROM users WHERE active = $1) AS _random ORDER BY random() LIMIT $2"
+	if result := randomQuery("SELECT * FROM users WHERE active = $1;", 1); result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestPostgreSQLAdapter_FetchRandomWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	ctx := context.Background()
+	op := &adapter.Operation{Statement: "users"}
+
+	if _, err := a.FetchRandom(ctx, op, nil, 1); err == nil {
+		t.Error("FetchRandom: expected error when not connected, got nil")
+	}
+	if _, err := a.ApproxRowCount(ctx, "users"); err == nil {
+		t.Error("ApproxRowCount: expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_FetchRandom(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, `CREATE TABLE random_test AS SELECT g AS id FROM generate_series(1, 1000) g;
+		ANALYZE random_test`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE random_test") })
+
+	count, err := a.ApproxRowCount(ctx, "random_test")
+	if err != nil {
+		t.Fatalf("ApproxRowCount failed: %v", err)
+	}
+	if count != 1000 {
+		t.Errorf("expected an estimate of 1000 rows, got %d", count)
+	}
+
+	queryOp := &adapter.Operation{Statement: "SELECT id FROM random_test WHERE id <= {max}"}
+	results, err := a.FetchRandom(ctx, queryOp, map[string]interface{}{"max": 100}, 10)
+	if err != nil {
+		t.Fatalf("FetchRandom failed: %v", err)
+	}
+	if len(results) != 10 {
+		t.Errorf("expected 10 rows, got %d", len(results))
+	}
+
+	useTablesample := true
+	sampleCtx := WithOperationOptions(ctx, OperationOptions{UseTablesample: &useTablesample})
+	if _, err := a.FetchRandom(sampleCtx, &adapter.Operation{Statement: "random_test"}, nil, 10); err != nil {
+		t.Fatalf("FetchRandom with TABLESAMPLE failed: %v", err)
+	}
+	if _, err := a.FetchRandom(sampleCtx, queryOp, map[string]interface{}{"max": 100}, 10); err == nil {
+		t.Error("expected error forcing TABLESAMPLE on a query, got nil")
+	}
+}
@@ -0,0 +1,151 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// SchemaDiffKind classifies a single difference reported by SchemaDiff.
+type SchemaDiffKind string
+
+// Kinds of differences reported by SchemaDiff.
+const (
+	DiffTableOnlyInA  SchemaDiffKind = "table_only_in_a"
+	DiffTableOnlyInB  SchemaDiffKind = "table_only_in_b"
+	DiffColumnOnlyInA SchemaDiffKind = "column_only_in_a"
+	DiffColumnOnlyInB SchemaDiffKind = "column_only_in_b"
+	DiffTypeMismatch  SchemaDiffKind = "type_mismatch"
+)
+
+// SchemaDiffEntry describes one difference between two schemas.
+// Column is empty for table-level differences; TypeA and TypeB are only
+// set when the column exists on the corresponding side.
+type SchemaDiffEntry struct {
+	Kind   SchemaDiffKind
+	Table  string
+	Column string
+	TypeA  string
+	TypeB  string
+}
+
+// String renders the entry in a human-readable form.
+func (e SchemaDiffEntry) String() string {
+	switch e.Kind {
+	case DiffTableOnlyInA, DiffTableOnlyInB:
+		return fmt.Sprintf("%s: %s", e.Kind, e.Table)
+	case DiffTypeMismatch:
+		return fmt.Sprintf("%s: %s.%s (%s vs %s)", e.Kind, e.Table, e.Column, e.TypeA, e.TypeB)
+	default:
+		return fmt.Sprintf("%s: %s.%s", e.Kind, e.Table, e.Column)
+	}
+}
+
+// schemaColumns maps table name to column name to data type.
+type schemaColumns map[string]map[string]string
+
+// SchemaDiff compares the columns of every table in schemaA and schemaB using
+// information_schema.columns and returns the differences: tables or columns
+// present on only one side, and columns whose data types differ.
+func (a *PostgreSQLAdapter) SchemaDiff(ctx context.Context, schemaA, schemaB string) ([]SchemaDiffEntry, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	colsA, err := a.loadSchemaColumns(ctx, schemaA)
+	if err != nil {
+		return nil, err
+	}
+	colsB, err := a.loadSchemaColumns(ctx, schemaB)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffSchemaColumns(colsA, colsB), nil
+}
+
+// loadSchemaColumns reads the column definitions of every table in schema.
+func (a *PostgreSQLAdapter) loadSchemaColumns(ctx context.Context, schema string) (schemaColumns, error) {
+	query := `SELECT table_name, column_name,
+		CASE WHEN data_type IN ('USER-DEFINED', 'ARRAY') THEN udt_name ELSE data_type END
+		FROM information_schema.columns
+		WHERE table_schema = $1`
+
+	rows, err := a.queryContext(ctx, "schema_diff", query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to read columns of schema %s: %w", schema, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	cols := make(schemaColumns)
+	for rows.Next() {
+		var table, column, dataType string
+		if err := rows.Scan(&table, &column, &dataType); err != nil {
+			return nil, fmt.Errorf("postgresql: scan failed: %w", err)
+		}
+		if cols[table] == nil {
+			cols[table] = make(map[string]string)
+		}
+		cols[table][column] = dataType
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgresql: rows iteration failed: %w", err)
+	}
+
+	return cols, nil
+}
+
+// diffSchemaColumns returns the differences between a and b, ordered by
+// table and column name so results are stable.
+func diffSchemaColumns(a, b schemaColumns) []SchemaDiffEntry {
+	var diffs []SchemaDiffEntry
+
+	for _, table := range sortedKeys(a, b) {
+		colsA, inA := a[table]
+		colsB, inB := b[table]
+
+		switch {
+		case !inB:
+			diffs = append(diffs, SchemaDiffEntry{Kind: DiffTableOnlyInA, Table: table})
+			continue
+		case !inA:
+			diffs = append(diffs, SchemaDiffEntry{Kind: DiffTableOnlyInB, Table: table})
+			continue
+		}
+
+		for _, column := range sortedKeys(colsA, colsB) {
+			typeA, inA := colsA[column]
+			typeB, inB := colsB[column]
+
+			switch {
+			case !inB:
+				diffs = append(diffs, SchemaDiffEntry{Kind: DiffColumnOnlyInA, Table: table, Column: column, TypeA: typeA})
+			case !inA:
+				diffs = append(diffs, SchemaDiffEntry{Kind: DiffColumnOnlyInB, Table: table, Column: column, TypeB: typeB})
+			case typeA != typeB:
+				diffs = append(diffs, SchemaDiffEntry{Kind: DiffTypeMismatch, Table: table, Column: column, TypeA: typeA, TypeB: typeB})
+			}
+		}
+	}
+
+	return diffs
+}
+
+// sortedKeys returns the union of the keys of a and b in ascending order.
+func sortedKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]struct{}, len(a)+len(b))
+	for k := range a {
+		seen[k] = struct{}{}
+	}
+	for k := range b {
+		seen[k] = struct{}{}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,172 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// schemaNamePattern is the set of schema names a schema router may return.
+var schemaNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// WithSchemaRouter routes every statement to the schema returned by fn for
+// the statement's context, for multi-tenant applications that keep one
+// schema per tenant:
+//
+//	a := postgresql.NewPostgreSQLAdapter(postgresql.WithSchemaRouter(
+//	    func(ctx context.Context) string { return tenantFromContext(ctx) },
+//	))
+//
+// Outside a transaction each statement pins a pooled connection and runs
+// SET search_path on it first, which costs an extra round trip; a
+// transaction sets it once with SET LOCAL when it begins. Pinned sessions,
+// such as those of BeginImplicit, advisory locks, COPY and batches, are
+// routed the same way. An empty schema restores the server's default
+// search_path. Schema names must match [a-zA-Z_][a-zA-Z0-9_]*; anything
+// else fails the statement. Prepared statement caching is bypassed while a
+// router is set.
+//
+// A routed connection has its search_path reset before the pool hands it
+// out again. On a pool passed to WithDB this only holds for statements that
+// return no rows: a connection that served a routed query keeps its
+// search_path until the next routed statement sets it.
+func WithSchemaRouter(fn func(ctx context.Context) string) Option {
+	return func(a *PostgreSQLAdapter) {
+		a.schemaRouter = fn
+	}
+}
+
+// searchPathStatement returns the SET statement selecting the schema routed
+// to by ctx. local scopes it to the current transaction.
+func (a *PostgreSQLAdapter) searchPathStatement(ctx context.Context, local bool) (string, error) {
+	schema := a.schemaRouter(ctx)
+
+	set := "SET "
+	if local {
+		set = "SET LOCAL "
+	}
+	if schema == "" {
+		return set + "search_path TO DEFAULT", nil
+	}
+	if !schemaNamePattern.MatchString(schema) {
+		return "", fmt.Errorf("postgresql: invalid schema name %q", schema)
+	}
+	return set + "search_path = " + QuoteIdentifier(schema), nil
+}
+
+// routedConn pins a pooled connection and points its search_path at the
+// schema routed to by ctx. It returns nil when no router is set or ctx
+// carries a transaction, which was routed when it began.
+func (a *PostgreSQLAdapter) routedConn(ctx context.Context) (*sql.Conn, error) {
+	if a.schemaRouter == nil || a.txFromContext(ctx) != nil {
+		return nil, nil
+	}
+
+	return a.conn(ctx)
+}
+
+// conn pins a pooled connection for statements that need a session of their
+// own, pointing its search_path at the schema routed to by ctx when a schema
+// router is set. Give it back with releaseConn.
+func (a *PostgreSQLAdapter) conn(ctx context.Context) (*sql.Conn, error) {
+	if a.schemaRouter == nil {
+		return a.db.Conn(ctx)
+	}
+
+	setPath, err := a.searchPathStatement(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := a.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.ExecContext(ctx, setPath); err != nil {
+		_ = a.releaseConn(conn)
+		return nil, fmt.Errorf("postgresql: failed to set search_path: %w", err)
+	}
+	return conn, nil
+}
+
+// releaseConn returns a connection pinned with conn to the pool, first
+// resetting the search_path a schema router may have set, so that the next
+// user of the connection does not inherit another tenant's schema. A
+// connection that cannot be reset is discarded instead.
+func (a *PostgreSQLAdapter) releaseConn(conn *sql.Conn) error {
+	if a.schemaRouter != nil {
+		if _, err := conn.ExecContext(context.Background(), "RESET search_path"); err != nil {
+			_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+			return fmt.Errorf("postgresql: failed to reset search_path: %w", err)
+		}
+	}
+	return conn.Close()
+}
+
+// releaseAfterRows returns conn to the pool once the rows read from it are
+// closed. sql.Conn.Close waits for open rows, so it runs in the background,
+// and the search_path cannot be reset before it; instead the connection is
+// marked so that the pool resets it before handing it out again.
+func releaseAfterRows(conn *sql.Conn) {
+	_ = conn.Raw(func(driverConn interface{}) error {
+		markSearchPathSet(driverConn)
+		return nil
+	})
+	go func() { _ = conn.Close() }()
+}
+
+// searchPathSetKey is the pgconn custom data key marking a pgx connection
+// whose search_path was set by a schema router.
+const searchPathSetKey = "postgresql.search_path_set"
+
+// markSearchPathSet flags driverConn for a search_path reset when the pool
+// next reuses it. Only connections opened by the adapter itself can be
+// flagged; those of a pool passed to WithDB are left as they are.
+func markSearchPathSet(driverConn interface{}) {
+	switch c := driverConn.(type) {
+	case *pqCountedConn:
+		c.searchPathSet.Store(true)
+	case *stdlib.Conn:
+		c.Conn().PgConn().CustomData()[searchPathSetKey] = true
+	}
+}
+
+// resetPgxSearchPath resets the search_path of a pgx connection flagged by
+// markSearchPathSet.
+func resetPgxSearchPath(ctx context.Context, conn *pgx.Conn) error {
+	data := conn.PgConn().CustomData()
+	if set, _ := data[searchPathSetKey].(bool); !set {
+		return nil
+	}
+	if _, err := conn.Exec(ctx, "RESET search_path"); err != nil {
+		return driver.ErrBadConn
+	}
+	delete(data, searchPathSetKey)
+	return nil
+}
+
+// beginTx starts a transaction on the pool, routed to the schema for ctx
+// when a schema router is set.
+func (a *PostgreSQLAdapter) beginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	tx, err := a.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if a.schemaRouter == nil {
+		return tx, nil
+	}
+
+	setPath, err := a.searchPathStatement(ctx, true)
+	if err == nil {
+		_, err = tx.ExecContext(ctx, setPath)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	return tx, nil
+}
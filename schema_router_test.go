@@ -0,0 +1,162 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+type tenantKey struct{}
+
+func tenantRouter(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+func TestSearchPathStatement(t *testing.T) {
+	a := NewPostgreSQLAdapter(WithSchemaRouter(tenantRouter))
+
+	tests := []struct {
+		name      string
+		schema    string
+		local     bool
+		expected  string
+		expectErr bool
+	}{
+		{"schema", "tenant_a", false, `SET search_path = "tenant_a"`, false},
+		{"local", "tenant_a", true, `SET LOCAL search_path = "tenant_a"`, false},
+		{"default", "", false, "SET search_path TO DEFAULT", false},
+		{"injection", "a; DROP TABLE users", false, "", true},
+		{"leading digit", "1tenant", false, "", true},
+		{"qualified", "public.users", false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), tenantKey{}, tt.schema)
+			result, err := a.searchPathStatement(ctx, tt.local)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got %q", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_SchemaRouterInvalidSchema(t *testing.T) {
+	a := NewPostgreSQLAdapter(WithSchemaRouter(tenantRouter))
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1")
+	if err != nil {
+		t.Fatalf("failed to open pool: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	a.db = db
+
+	// The schema is rejected before a connection is requested.
+	ctx := context.WithValue(context.Background(), tenantKey{}, "x; --")
+	var one int
+	if err := a.queryRowContext(ctx, "test", "SELECT 1").Scan(&one); err == nil {
+		t.Error("expected error for invalid schema, got nil")
+	}
+	if _, err := a.execContext(ctx, "test", "SELECT 1"); err == nil {
+		t.Error("expected error for invalid schema, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_SchemaRouter(t *testing.T) {
+	a := newIntegrationAdapter(t, WithSchemaRouter(tenantRouter))
+	ctx := context.Background()
+
+	for _, schema := range []string{"tenant_a", "tenant_b"} {
+		setup := "CREATE SCHEMA " + schema + "; CREATE TABLE " + schema + ".users (id int, name text); " +
+			"INSERT INTO " + schema + ".users VALUES (1, '" + schema + "')"
+		if _, err := a.db.ExecContext(ctx, setup); err != nil {
+			t.Fatalf("failed to create schema %s: %v", schema, err)
+		}
+		t.Cleanup(func() { _, _ = a.db.Exec("DROP SCHEMA " + schema + " CASCADE") })
+	}
+
+	op := &adapter.Operation{Statement: "SELECT name FROM users WHERE id = {id}"}
+	for _, schema := range []string{"tenant_a", "tenant_b", "tenant_a"} {
+		tenantCtx := context.WithValue(ctx, tenantKey{}, schema)
+		results, err := a.Fetch(tenantCtx, op, map[string]interface{}{"id": 1})
+		if err != nil {
+			t.Fatalf("fetch for %s failed: %v", schema, err)
+		}
+		if len(results) != 1 || results[0].(map[string]interface{})["name"] != schema {
+			t.Errorf("expected the row of %s, got %v", schema, results)
+		}
+	}
+
+	tenantCtx := context.WithValue(ctx, tenantKey{}, "tenant_b")
+	tx, err := a.BeginTx(tenantCtx, nil)
+	if err != nil {
+		t.Fatalf("begin failed: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var name string
+	if err := a.queryRowContext(WithTx(ctx, tx), "test", "SELECT name FROM users").Scan(&name); err != nil {
+		t.Fatalf("query in transaction failed: %v", err)
+	}
+	if name != "tenant_b" {
+		t.Errorf("expected transaction to read tenant_b, got %q", name)
+	}
+}
+
+func TestPostgreSQLAdapter_SchemaRouterResetsSearchPath(t *testing.T) {
+	for _, driverName := range []string{DriverPQ, DriverPGX} {
+		t.Run(driverName, func(t *testing.T) {
+			a := newIntegrationAdapter(t, WithDriver(driverName), WithSchemaRouter(tenantRouter))
+			db, err := a.openDB()
+			if err != nil {
+				t.Fatalf("failed to open pool: %v", err)
+			}
+			defer func() { _ = db.Close() }()
+			db.SetMaxOpenConns(1)
+
+			var defaultPath string
+			if err := db.QueryRow("SHOW search_path").Scan(&defaultPath); err != nil {
+				t.Fatalf("query failed: %v", err)
+			}
+
+			pooled := WithDB(db, WithDriver(driverName), WithSchemaRouter(tenantRouter))
+			ctx := context.WithValue(context.Background(), tenantKey{}, "tenant_a")
+			var path string
+			if err := pooled.queryRowContext(ctx, "test", "SHOW search_path").Scan(&path); err != nil {
+				t.Fatalf("routed query failed: %v", err)
+			}
+			if path != `"tenant_a"` && path != "tenant_a" {
+				t.Fatalf("expected the routed schema, got %q", path)
+			}
+			if _, err := pooled.execContext(ctx, "test", "SELECT 1"); err != nil {
+				t.Fatalf("routed exec failed: %v", err)
+			}
+			implicit, err := pooled.BeginImplicit(ctx)
+			if err != nil {
+				t.Fatalf("begin implicit failed: %v", err)
+			}
+			if err := implicit.Close(); err != nil {
+				t.Fatalf("close failed: %v", err)
+			}
+
+			// A statement that bypasses the router must see the default.
+			if err := db.QueryRow("SHOW search_path").Scan(&path); err != nil {
+				t.Fatalf("query failed: %v", err)
+			}
+			if path != defaultPath {
+				t.Errorf("expected the default search_path %q after routed calls, got %q", defaultPath, path)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDiffSchemaColumns(t *testing.T) {
+	a := schemaColumns{
+		"users": {"id": "integer", "name": "text", "legacy": "text"},
+		"audit": {"id": "bigint"},
+	}
+	b := schemaColumns{
+		"users":  {"id": "bigint", "name": "text", "email": "text"},
+		"orders": {"id": "integer"},
+	}
+
+	expected := []SchemaDiffEntry{
+		{Kind: DiffTableOnlyInA, Table: "audit"},
+		{Kind: DiffTableOnlyInB, Table: "orders"},
+		{Kind: DiffColumnOnlyInB, Table: "users", Column: "email", TypeB: "text"},
+		{Kind: DiffTypeMismatch, Table: "users", Column: "id", TypeA: "integer", TypeB: "bigint"},
+		{Kind: DiffColumnOnlyInA, Table: "users", Column: "legacy", TypeA: "text"},
+	}
+
+	result := diffSchemaColumns(a, b)
+	if len(result) != len(expected) {
+		t.Fatalf("expected %d diffs, got %d: %v", len(expected), len(result), result)
+	}
+	for i, exp := range expected {
+		if result[i] != exp {
+			t.Errorf("diff %d: expected %+v, got %+v", i, exp, result[i])
+		}
+	}
+}
+
+func TestDiffSchemaColumns_Identical(t *testing.T) {
+	cols := schemaColumns{"users": {"id": "integer"}}
+	if diffs := diffSchemaColumns(cols, cols); len(diffs) != 0 {
+		t.Errorf("expected no diffs, got %v", diffs)
+	}
+}
+
+func TestSchemaDiffEntry_String(t *testing.T) {
+	tests := []struct {
+		entry    SchemaDiffEntry
+		expected string
+	}{
+		{SchemaDiffEntry{Kind: DiffTableOnlyInA, Table: "audit"}, "table_only_in_a: audit"},
+		{SchemaDiffEntry{Kind: DiffColumnOnlyInB, Table: "users", Column: "email"}, "column_only_in_b: users.email"},
+		{SchemaDiffEntry{Kind: DiffTypeMismatch, Table: "users", Column: "id", TypeA: "integer", TypeB: "bigint"}, "type_mismatch: users.id (integer vs bigint)"},
+	}
+
+	for _, tt := range tests {
+		if result := tt.entry.String(); result != tt.expected {
+			t.Errorf("expected %q, got %q", tt.expected, result)
+		}
+	}
+}
+
+func TestPostgreSQLAdapter_SchemaDiffWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if _, err := a.SchemaDiff(context.Background(), "public", "staging"); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
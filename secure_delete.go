@@ -0,0 +1,156 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// deleteStatement splits "DELETE FROM <table> WHERE <condition>".
+var deleteStatement = regexp.MustCompile(`(?is)^\s*DELETE\s+FROM\s+(\S+)\s+(WHERE\s.+)$`)
+
+// whereReference matches the string literals, placeholders and names of a
+// WHERE clause; only the names are taken as column references.
+var whereReference = regexp.MustCompile(`'(?:[^']|'')*'|\{[^}]*\}|` + sqlName)
+
+// SecureDelete deletes rows like Delete, but first overwrites every text,
+// varchar, char and bytea column of the matching rows: text columns are
+// emptied and bytea columns are filled with random bytes. Both steps run in
+// one transaction. op.Statement must have the form
+// "DELETE FROM <table> WHERE <condition>", or "DELETE FROM <table>" with
+// the WHERE clause added on the key columns of op.Identifier as for Delete.
+//
+// Columns that identify the rows are left intact so the DELETE still finds
+// them: the key columns, the columns named in the WHERE clause, and the
+// columns covered by a primary key, unique, foreign key, check or exclusion
+// constraint or a unique index.
+//
+// Because of MVCC, an UPDATE writes a new row version rather than modifying
+// the old one in place, so the original values stay in the dead tuple until
+// VACUUM reclaims it. SecureDelete narrows what a later reader of the table
+// can recover, but only a VACUUM of the table guarantees the data is gone
+// from the heap; WAL archives and backups are not affected at all.
+func (a *PostgreSQLAdapter) SecureDelete(ctx context.Context, op *adapter.Operation, identifiers []interface{}) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	m := deleteStatement.FindStringSubmatch(keyedStatement(op.Statement, op.Identifier))
+	if m == nil {
+		return fmt.Errorf("postgresql: secure delete requires a DELETE FROM ... WHERE ... statement")
+	}
+	table, where := m[1], m[2]
+
+	keep := referencedColumns(where)
+	for _, key := range op.Identifier {
+		for column := range referencedColumns(key.DataField) {
+			keep[column] = true
+		}
+	}
+
+	assignments, err := a.overwriteAssignments(ctx, table, keep)
+	if err != nil {
+		return err
+	}
+
+	tx, err := a.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	txCtx := WithTx(ctx, tx)
+
+	if len(assignments) > 0 {
+		overwrite := fmt.Sprintf("UPDATE %s SET %s %s", table, strings.Join(assignments, ", "), where)
+		pgQuery := replaceNamedParams(overwrite)
+		for _, id := range identifiers {
+			params, err := identifierParams(id, op.Identifier)
+			if err != nil {
+				return err
+			}
+			args, err := extractArgs(overwrite, params)
+			if err != nil {
+				return err
+			}
+			if _, err := a.execContext(txCtx, operationName(op, "delete"), pgQuery, args...); err != nil {
+				return fmt.Errorf("postgresql: secure delete overwrite failed: %w", err)
+			}
+		}
+	}
+
+	if err := a.Delete(txCtx, op, identifiers); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// overwriteAssignments returns the SET assignments that blank out every
+// text-like and bytea column of table, except the columns in keep and those
+// covered by a constraint or unique index.
+func (a *PostgreSQLAdapter) overwriteAssignments(ctx context.Context, table string, keep map[string]bool) ([]string, error) {
+	query := `SELECT attname, atttypid = 'bytea'::regtype
+		FROM pg_attribute a
+		WHERE attrelid = $1::regclass AND attnum > 0 AND NOT attisdropped
+		AND atttypid IN ('text'::regtype, 'varchar'::regtype, 'bpchar'::regtype, 'bytea'::regtype)
+		AND NOT EXISTS (SELECT 1 FROM pg_constraint c
+			WHERE c.conrelid = a.attrelid AND a.attnum = ANY(c.conkey)
+			AND c.contype IN ('p', 'u', 'f', 'c', 'x'))
+		AND NOT EXISTS (SELECT 1 FROM pg_index i
+			WHERE i.indrelid = a.attrelid AND i.indisunique AND a.attnum = ANY(i.indkey))
+		ORDER BY attnum`
+
+	rows, err := a.queryContext(ctx, "secure_delete", query, table)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to read columns of %s: %w", table, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var assignments []string
+	for rows.Next() {
+		var column string
+		var isBytea bool
+		if err := rows.Scan(&column, &isBytea); err != nil {
+			return nil, fmt.Errorf("postgresql: scan failed: %w", err)
+		}
+		if !keep[column] {
+			assignments = append(assignments, overwriteAssignment(column, isBytea))
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgresql: rows iteration failed: %w", err)
+	}
+
+	return assignments, nil
+}
+
+// overwriteAssignment renders the SET assignment that blanks out column.
+func overwriteAssignment(column string, isBytea bool) string {
+	if isBytea {
+		return fmt.Sprintf("%s = decode(md5(random()::text), 'hex')", pq.QuoteIdentifier(column))
+	}
+	return fmt.Sprintf("%s = ''", pq.QuoteIdentifier(column))
+}
+
+// referencedColumns returns the names of the columns a WHERE clause may
+// refer to, as stored in the catalog: unquoted names are folded to lower
+// case and quoted ones are unquoted. Keywords and table names are returned
+// too, which only keeps a column that would otherwise be overwritten.
+func referencedColumns(where string) map[string]bool {
+	columns := map[string]bool{}
+	for _, name := range whereReference.FindAllString(where, -1) {
+		switch name[0] {
+		case '\'', '{':
+		case '"':
+			columns[strings.ReplaceAll(name[1:len(name)-1], `""`, `"`)] = true
+		default:
+			columns[strings.ToLower(name)] = true
+		}
+	}
+	return columns
+}
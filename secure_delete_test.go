@@ -0,0 +1,128 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestDeleteStatement(t *testing.T) {
+	m := deleteStatement.FindStringSubmatch("DELETE FROM app.users\n WHERE id = {id}")
+	if m == nil {
+		t.Fatal("expected statement to match")
+	}
+	if m[1] != "app.users" || m[2] != "WHERE id = {id}" {
+		t.Errorf("unexpected split: table=%q where=%q", m[1], m[2])
+	}
+
+	for _, stmt := range []string{"DELETE FROM users", "UPDATE users SET name = '' WHERE id = {id}"} {
+		if deleteStatement.MatchString(stmt) {
+			t.Errorf("expected %q not to match", stmt)
+		}
+	}
+}
+
+func TestOverwriteAssignment(t *testing.T) {
+	if result := overwriteAssignment("notes", false); result != `"notes" = ''` {
+		t.Errorf("unexpected text assignment: %s", result)
+	}
+	if result := overwriteAssignment("secret", true); result != `"secret" = decode(md5(random()::text), 'hex')` {
+		t.Errorf("unexpected bytea assignment: %s", result)
+	}
+}
+
+func TestReferencedColumns(t *testing.T) {
+	columns := referencedColumns(`WHERE Slug = {slug} AND "Tenant""Id" = 'x = y' AND t.email IS NOT NULL`)
+	for _, column := range []string{"slug", `Tenant"Id`, "t", "email"} {
+		if !columns[column] {
+			t.Errorf("expected %q to be referenced, got %v", column, columns)
+		}
+	}
+	for _, column := range []string{"Slug", "x", "y", "{slug}"} {
+		if columns[column] {
+			t.Errorf("expected %q not to be referenced, got %v", column, columns)
+		}
+	}
+}
+
+func TestPostgreSQLAdapter_SecureDeleteWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	op := &adapter.Operation{Statement: "DELETE FROM users WHERE id = {id}"}
+	if err := a.SecureDelete(context.Background(), op, []interface{}{1}); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_SecureDelete(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	// A trigger records what each row held when it was deleted, after the
+	// overwrite.
+	if _, err := a.db.ExecContext(ctx, `CREATE TABLE secure_delete_test (slug varchar(32) PRIMARY KEY, email text UNIQUE, notes text, secret bytea);
+		CREATE TABLE secure_delete_audit (slug varchar(32), email text, notes text, secret bytea);
+		CREATE FUNCTION secure_delete_audit() RETURNS trigger LANGUAGE plpgsql AS $$
+		BEGIN
+			INSERT INTO secure_delete_audit VALUES (OLD.slug, OLD.email, OLD.notes, OLD.secret);
+			RETURN OLD;
+		END $$;
+		CREATE TRIGGER secure_delete_audit BEFORE DELETE ON secure_delete_test
+			FOR EACH ROW EXECUTE FUNCTION secure_delete_audit();
+		INSERT INTO secure_delete_test VALUES
+			('alice', 'alice@example.com', 'private', 'key1'),
+			('bob', 'bob@example.com', 'private', 'key2'),
+			('carol', 'carol@example.com', 'private', 'key3')`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = a.db.Exec("DROP TABLE secure_delete_test, secure_delete_audit; DROP FUNCTION secure_delete_audit()")
+	})
+
+	op := &adapter.Operation{
+		Statement:  "DELETE FROM secure_delete_test",
+		Identifier: []adapter.PropertyMapping{{ObjectField: "Slug", DataField: "slug"}},
+	}
+	if err := a.SecureDelete(ctx, op, []interface{}{"alice", "bob"}); err != nil {
+		t.Fatalf("secure delete failed: %v", err)
+	}
+
+	var remaining int
+	if err := a.db.QueryRowContext(ctx, "SELECT count(*) FROM secure_delete_test WHERE slug = 'carol'").Scan(&remaining); err != nil {
+		t.Fatalf("count failed: %v", err)
+	}
+	if remaining != 1 {
+		t.Errorf("expected carol to remain, got %d rows", remaining)
+	}
+
+	rows, err := a.db.QueryContext(ctx, "SELECT slug, email, notes, secret FROM secure_delete_audit ORDER BY slug")
+	if err != nil {
+		t.Fatalf("audit query failed: %v", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var deleted []string
+	for rows.Next() {
+		var slug, email, notes string
+		var secret []byte
+		if err := rows.Scan(&slug, &email, &notes, &secret); err != nil {
+			t.Fatalf("scan failed: %v", err)
+		}
+		deleted = append(deleted, slug)
+		if email != slug+"@example.com" {
+			t.Errorf("%s: expected the unique email to be kept, got %q", slug, email)
+		}
+		if notes != "" {
+			t.Errorf("%s: expected notes to be emptied, got %q", slug, notes)
+		}
+		if len(secret) != 16 {
+			t.Errorf("%s: expected secret to be overwritten with random bytes, got %q", slug, secret)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("rows iteration failed: %v", err)
+	}
+	if len(deleted) != 2 {
+		t.Errorf("expected alice and bob to be deleted, got %v", deleted)
+	}
+}
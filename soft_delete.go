@@ -0,0 +1,110 @@
+package postgresql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SoftDeleteOption makes Delete mark rows as deleted instead of removing
+// them. Attach it to a call through OperationOptions.SoftDelete:
+//
+//	ctx = postgresql.WithOperationOptions(ctx, postgresql.OperationOptions{
+//	    SoftDelete: &postgresql.SoftDeleteOption{Column: "deleted_at"},
+//	})
+//
+// A delete statement of the form
+//
+//	DELETE FROM users WHERE id = {id}
+//
+// is then run as
+//
+//	UPDATE users SET "deleted_at" = now() WHERE (id = {id}) AND "deleted_at" IS NULL
+//
+// so deleting a row twice reports adapter.ErrNotFound, as a hard delete
+// would. Set OperationOptions.ExcludeSoftDeleted on fetches to skip the
+// marked rows.
+type SoftDeleteOption struct {
+	// Column marks deleted rows; NULL means the row is live.
+	Column string
+	// DeletedAtValue is stored in Column by Delete. nil stores now().
+	DeletedAtValue interface{}
+}
+
+// softDeleteParam is the statement parameter carrying DeletedAtValue. The
+// leading underscores keep it clear of the operation's own parameters.
+const softDeleteParam = "__soft_delete_value"
+
+var (
+	deleteStatementPattern = regexp.MustCompile(`(?is)^\s*DELETE\s+FROM\s+(\S+)\s+WHERE\s+(.*?)(\s+RETURNING\s+.*)?$`)
+	// fetchTailKeyword matches the clauses that may follow a WHERE clause.
+	fetchTailKeyword = regexp.MustCompile(`(?i)\b(GROUP\s+BY|HAVING|WINDOW|ORDER\s+BY|LIMIT|OFFSET|FETCH|FOR\s+(UPDATE|NO\s+KEY\s+UPDATE|SHARE|KEY\s+SHARE))\b`)
+	fromKeyword      = regexp.MustCompile(`(?i)\bFROM\b`)
+)
+
+// statement rewrites the named DELETE statement into the UPDATE that marks
+// the matched rows.
+func (o *SoftDeleteOption) statement(deleteStatement string) (string, error) {
+	if o.Column == "" {
+		return "", fmt.Errorf("postgresql: soft delete requires a column")
+	}
+	m := deleteStatementPattern.FindStringSubmatch(strings.TrimRight(strings.TrimSpace(deleteStatement), ";"))
+	if m == nil {
+		return "", fmt.Errorf("postgresql: soft delete requires a DELETE FROM ... WHERE statement")
+	}
+
+	value := "now()"
+	if o.DeletedAtValue != nil {
+		value = "{" + softDeleteParam + "}"
+	}
+	column := QuoteIdentifier(o.Column)
+	return fmt.Sprintf("UPDATE %s SET %s = %s WHERE (%s) AND %s IS NULL%s",
+		m[1], column, value, strings.TrimSpace(m[2]), column, m[3]), nil
+}
+
+// params adds DeletedAtValue to the parameters of one Delete identifier.
+func (o *SoftDeleteOption) params(params map[string]interface{}) map[string]interface{} {
+	if o.DeletedAtValue == nil {
+		return params
+	}
+
+	withValue := make(map[string]interface{}, len(params)+1)
+	for k, v := range params {
+		withValue[k] = v
+	}
+	withValue[softDeleteParam] = o.DeletedAtValue
+	return withValue
+}
+
+// excludeSoftDeleted restricts a SELECT statement to rows whose column is
+// NULL, adding to its outermost WHERE clause or creating one before any
+// GROUP BY, ORDER BY, LIMIT or locking clause. Keywords inside parentheses
+// belong to subqueries and are skipped; a UNION should filter each branch
+// explicitly instead.
+func excludeSoftDeleted(statement, column string) string {
+	statement = strings.TrimRight(strings.TrimSpace(statement), ";")
+	condition := QuoteIdentifier(column) + " IS NULL"
+
+	where := lastMatch(whereKeyword, statement)
+	start := 0
+	if where != nil {
+		start = where[1]
+	} else if from := lastMatch(fromKeyword, statement); from != nil {
+		start = from[1]
+	}
+
+	end := len(statement)
+	if tail := firstMatch(fetchTailKeyword, statement[start:]); tail != nil {
+		end = start + tail[0]
+	}
+	rest := ""
+	if end < len(statement) {
+		rest = " " + statement[end:]
+	}
+
+	if where != nil {
+		return fmt.Sprintf("%s (%s) AND %s%s",
+			statement[:where[1]], strings.TrimSpace(statement[where[1]:end]), condition, rest)
+	}
+	return fmt.Sprintf("%s WHERE %s%s", strings.TrimRight(statement[:end], " \t\r\n"), condition, rest)
+}
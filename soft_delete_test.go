@@ -0,0 +1,161 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestSoftDeleteOption_Statement(t *testing.T) {
+	tests := []struct {
+		name      string
+		opt       SoftDeleteOption
+		statement string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:      "now",
+			opt:       SoftDeleteOption{Column: "deleted_at"},
+			statement: "DELETE FROM users WHERE id = {id}",
+			expected:  `UPDATE users SET "deleted_at" = now() WHERE (id = {id}) AND "deleted_at" IS NULL`,
+		},
+		{
+			name:      "value and returning",
+			opt:       SoftDeleteOption{Column: "deleted_at", DeletedAtValue: time.Unix(0, 0)},
+			statement: "delete from public.users where id = {id} or email = {email} returning id;",
+			expected: `UPDATE public.users SET "deleted_at" = {__soft_delete_value} ` +
+				`WHERE (id = {id} or email = {email}) AND "deleted_at" IS NULL returning id`,
+		},
+		{
+			name:      "no where",
+			opt:       SoftDeleteOption{Column: "deleted_at"},
+			statement: "DELETE FROM users",
+			expectErr: true,
+		},
+		{
+			name:      "no column",
+			opt:       SoftDeleteOption{},
+			statement: "DELETE FROM users WHERE id = {id}",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.opt.statement(tt.statement)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got %q", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestSoftDeleteOption_Params(t *testing.T) {
+	params := map[string]interface{}{"id": 1}
+
+	opt := &SoftDeleteOption{Column: "deleted_at"}
+	if result := opt.params(params); len(result) != 1 {
+		t.Errorf("expected params unchanged without a value, got %v", result)
+	}
+
+	opt.DeletedAtValue = true
+	result := opt.params(params)
+	if result[softDeleteParam] != true || result["id"] != 1 {
+		t.Errorf("expected value added to params, got %v", result)
+	}
+	if len(params) != 1 {
+		t.Error("expected caller params to be left untouched")
+	}
+}
+
+func TestExcludeSoftDeleted(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		expected  string
+	}{
+		{
+			"no where",
+			"SELECT * FROM users",
+			`SELECT * FROM users WHERE "deleted_at" IS NULL`,
+		},
+		{
+			"where",
+			"SELECT * FROM users WHERE id = {id} OR email = {email}",
+			`SELECT * FROM users WHERE (id = {id} OR email = {email}) AND "deleted_at" IS NULL`,
+		},
+		{
+			"order and limit",
+			"SELECT * FROM users WHERE active ORDER BY name LIMIT {limit};",
+			`SELECT * FROM users WHERE (active) AND "deleted_at" IS NULL ORDER BY name LIMIT {limit}`,
+		},
+		{
+			"no where with order",
+			"SELECT * FROM users ORDER BY name",
+			`SELECT * FROM users WHERE "deleted_at" IS NULL ORDER BY name`,
+		},
+		{
+			"subquery",
+			"SELECT * FROM users WHERE id IN (SELECT user_id FROM orders WHERE total > {min} LIMIT 5) FOR UPDATE",
+			`SELECT * FROM users WHERE (id IN (SELECT user_id FROM orders WHERE total > {min} LIMIT 5)) AND "deleted_at" IS NULL FOR UPDATE`,
+		},
+		{
+			"keyword in literal",
+			"SELECT * FROM notes WHERE body <> 'ORDER BY'",
+			`SELECT * FROM notes WHERE (body <> 'ORDER BY') AND "deleted_at" IS NULL`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := excludeSoftDeleted(tt.statement, "deleted_at"); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_SoftDelete(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, `CREATE TABLE soft_delete_test (id int PRIMARY KEY, deleted_at timestamptz);
+		INSERT INTO soft_delete_test (id) VALUES (1), (2)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE soft_delete_test") })
+
+	ctx = WithOperationOptions(ctx, OperationOptions{
+		SoftDelete:         &SoftDeleteOption{Column: "deleted_at"},
+		ExcludeSoftDeleted: true,
+	})
+	del := &adapter.Operation{Statement: "DELETE FROM soft_delete_test WHERE id = {id}"}
+	if err := a.Delete(ctx, del, []interface{}{1}); err != nil {
+		t.Fatalf("soft delete failed: %v", err)
+	}
+	if err := a.Delete(ctx, del, []interface{}{1}); !errors.Is(err, adapter.ErrNotFound) {
+		t.Errorf("expected ErrNotFound deleting twice, got %v", err)
+	}
+
+	fetch := &adapter.Operation{Statement: "SELECT id FROM soft_delete_test ORDER BY id", Multi: true}
+	results, err := a.Fetch(ctx, fetch, nil)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("expected only the live row, got %v", results)
+	}
+}
@@ -0,0 +1,263 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// First releases with the statistics views read here.
+const (
+	// walStatsVersion is PostgreSQL 14, which added pg_stat_wal.
+	walStatsVersion = 140000
+	// ioStatsVersion is PostgreSQL 16, which added pg_stat_io.
+	ioStatsVersion = 160000
+	// walIOVersion is PostgreSQL 18, which moved WAL write and sync
+	// counters from pg_stat_wal to pg_stat_io.
+	walIOVersion = 180000
+)
+
+// BloatInfo summarises the physical state of a table as measured by
+// pgstattuple.
+type BloatInfo struct {
+	// TableLen is the size of the table in bytes.
+	TableLen int64
+	// DeadTupleCount is the number of dead row versions awaiting VACUUM.
+	DeadTupleCount int64
+	// DeadTuplePercent is the share of the table, in percent, taken by dead
+	// row versions.
+	DeadTuplePercent float64
+	// FreePct is the share of the table, in percent, that is free space.
+	FreePct float64
+}
+
+// TableBloat measures dead tuples and free space in schema.table with the
+// pgstattuple extension, which must be installed in the database. It reads
+// the whole table, so it is best run off-peak on large tables.
+func (a *PostgreSQLAdapter) TableBloat(ctx context.Context, schema, table string) (*BloatInfo, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	if err := a.requireExtension(ctx, "pgstattuple"); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT table_len, dead_tuple_count, dead_tuple_percent, free_percent
+		FROM pgstattuple(quote_ident($1) || '.' || quote_ident($2))`
+
+	var info BloatInfo
+	if err := a.queryRowContext(ctx, "table_bloat", query, schema, table).Scan(
+		&info.TableLen, &info.DeadTupleCount, &info.DeadTuplePercent, &info.FreePct); err != nil {
+		return nil, fmt.Errorf("postgresql: failed to measure bloat of %s.%s: %w", schema, table, err)
+	}
+
+	return &info, nil
+}
+
+// requireExtension returns ErrExtensionNotInstalled unless the named
+// extension is installed in the current database.
+func (a *PostgreSQLAdapter) requireExtension(ctx context.Context, name string) error {
+	var installed bool
+	query := "SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = $1)"
+	if err := a.queryRowContext(ctx, "require_extension", query, name).Scan(&installed); err != nil {
+		return fmt.Errorf("postgresql: failed to check for extension %s: %w", name, err)
+	}
+	if !installed {
+		return fmt.Errorf("%w: %s", ErrExtensionNotInstalled, name)
+	}
+	return nil
+}
+
+// ServerVersion returns the server version as reported by
+// server_version_num, e.g. 160002 for 16.2.
+func (a *PostgreSQLAdapter) ServerVersion(ctx context.Context) (int, error) {
+	if a.db == nil {
+		return 0, ErrNotConnected
+	}
+
+	var version string
+	if err := a.queryRowContext(ctx, "server_version", "SHOW server_version_num").Scan(&version); err != nil {
+		return 0, fmt.Errorf("postgresql: failed to read server version: %w", err)
+	}
+	n, err := strconv.Atoi(version)
+	if err != nil {
+		return 0, fmt.Errorf("postgresql: invalid server_version_num %q", version)
+	}
+	return n, nil
+}
+
+// requireVersion returns the server version, or ErrUnsupportedVersion
+// unless it is at least minVersion, in server_version_num form.
+func (a *PostgreSQLAdapter) requireVersion(ctx context.Context, minVersion int, feature string) (int, error) {
+	version, err := a.ServerVersion(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if version < minVersion {
+		return 0, fmt.Errorf("%w: %s needs server_version_num %d, server has %d", ErrUnsupportedVersion, feature, minVersion, version)
+	}
+	return version, nil
+}
+
+// IOStat is one row of pg_stat_io: the I/O done by one kind of backend on
+// one kind of object in one context since statistics were last reset.
+// Operations that cannot happen for the combination are reported as 0.
+type IOStat struct {
+	// BackendType is the kind of process, e.g. "client backend" or
+	// "checkpointer".
+	BackendType string
+	// Object is the target of the I/O: "relation" or "temp relation".
+	Object string
+	// Context is "normal", "vacuum", "bulkread" or "bulkwrite".
+	Context string
+
+	Reads  int64
+	Writes int64
+	// ReadTime and WriteTime are only collected with track_io_timing on.
+	ReadTime  time.Duration
+	WriteTime time.Duration
+
+	// Evictions counts buffers evicted to make room for new data.
+	Evictions int64
+	// Reuses counts ring buffers reused in the bulk contexts.
+	Reuses int64
+}
+
+// IOStats returns the rows of pg_stat_io, available from PostgreSQL 16.
+// Older servers yield ErrUnsupportedVersion.
+func (a *PostgreSQLAdapter) IOStats(ctx context.Context) ([]IOStat, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	if _, err := a.requireVersion(ctx, ioStatsVersion, "pg_stat_io"); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT backend_type, object, context,
+			coalesce(reads, 0), coalesce(read_time, 0), coalesce(writes, 0), coalesce(write_time, 0),
+			coalesce(evictions, 0), coalesce(reuses, 0)
+		FROM pg_stat_io
+		ORDER BY backend_type, object, context`
+
+	rows, err := a.queryContext(ctx, "io_stats", query)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to read pg_stat_io: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var stats []IOStat
+	for rows.Next() {
+		var stat IOStat
+		var readMs, writeMs float64
+		if err := rows.Scan(&stat.BackendType, &stat.Object, &stat.Context,
+			&stat.Reads, &readMs, &stat.Writes, &writeMs, &stat.Evictions, &stat.Reuses); err != nil {
+			return nil, fmt.Errorf("postgresql: scan failed: %w", err)
+		}
+		stat.ReadTime = millisecondsToDuration(readMs)
+		stat.WriteTime = millisecondsToDuration(writeMs)
+		stats = append(stats, stat)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgresql: rows iteration failed: %w", err)
+	}
+	return stats, nil
+}
+
+// millisecondsToDuration converts the fractional milliseconds used by the
+// statistics views.
+func millisecondsToDuration(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// WALStat holds the cluster-wide WAL activity counters of pg_stat_wal since
+// statistics were last reset.
+type WALStat struct {
+	// WALRecords is the number of WAL records generated.
+	WALRecords int64
+	// WALFPIs is the number of full page images generated. A high share
+	// points at checkpoints that are too frequent.
+	WALFPIs int64
+	// WALBytes is the amount of WAL generated, in bytes.
+	WALBytes int64
+	// WALBuffersFull counts writes forced because the WAL buffers were
+	// full; if it keeps growing, raise wal_buffers.
+	WALBuffersFull int64
+	// WALWrite and WALSync count writes and syncs of WAL to disk.
+	WALWrite int64
+	WALSync  int64
+	// WALWriteTime and WALSyncTime are only collected with
+	// track_wal_io_timing on.
+	WALWriteTime time.Duration
+	WALSyncTime  time.Duration
+}
+
+// WALStats returns the counters of pg_stat_wal, available from
+// PostgreSQL 14. Older servers yield ErrUnsupportedVersion. From
+// PostgreSQL 18 the write and sync counters are read from the WAL rows of
+// pg_stat_io, where that release moved them.
+func (a *PostgreSQLAdapter) WALStats(ctx context.Context) (*WALStat, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	version, err := a.requireVersion(ctx, walStatsVersion, "pg_stat_wal")
+	if err != nil {
+		return nil, err
+	}
+
+	var stat WALStat
+	var writeMs, syncMs float64
+	if err := a.queryRowContext(ctx, "wal_stats", walStatsQuery(version)).Scan(
+		&stat.WALRecords, &stat.WALFPIs, &stat.WALBytes, &stat.WALBuffersFull,
+		&stat.WALWrite, &stat.WALSync, &writeMs, &syncMs); err != nil {
+		return nil, fmt.Errorf("postgresql: failed to read pg_stat_wal: %w", err)
+	}
+	stat.WALWriteTime = millisecondsToDuration(writeMs)
+	stat.WALSyncTime = millisecondsToDuration(syncMs)
+
+	return &stat, nil
+}
+
+// walStatsQuery returns the WALStats query for a server of version.
+func walStatsQuery(version int) string {
+	if version >= walIOVersion {
+		return `SELECT w.wal_records, w.wal_fpi, w.wal_bytes::bigint, w.wal_buffers_full,
+				coalesce(io.writes, 0), coalesce(io.fsyncs, 0), coalesce(io.write_time, 0), coalesce(io.fsync_time, 0)
+			FROM pg_stat_wal w
+			CROSS JOIN (SELECT sum(writes)::bigint AS writes, sum(fsyncs)::bigint AS fsyncs,
+					sum(write_time) AS write_time, sum(fsync_time) AS fsync_time
+				FROM pg_stat_io WHERE object = 'wal') io`
+	}
+	return `SELECT wal_records, wal_fpi, wal_bytes::bigint, wal_buffers_full,
+			wal_write, wal_sync, wal_write_time, wal_sync_time
+		FROM pg_stat_wal`
+}
+
+// BufferCacheHitRatio returns the share of shared_buffers, between 0 and 1,
+// that currently holds a page, as seen by the pg_buffercache extension,
+// which must be installed in the database. A ratio that stays near 1 under
+// normal load suggests the working set does not fit and shared_buffers may
+// be worth raising; one that stays low suggests it is oversized. Reading
+// pg_buffercache briefly locks each buffer, so avoid polling it rapidly.
+func (a *PostgreSQLAdapter) BufferCacheHitRatio(ctx context.Context) (float64, error) {
+	if a.db == nil {
+		return 0, ErrNotConnected
+	}
+
+	if err := a.requireExtension(ctx, "pg_buffercache"); err != nil {
+		return 0, err
+	}
+
+	query := `SELECT coalesce(count(*) FILTER (WHERE relfilenode IS NOT NULL)::float8 / nullif(count(*), 0), 0)
+		FROM pg_buffercache`
+
+	var ratio float64
+	if err := a.queryRowContext(ctx, "buffer_cache_hit_ratio", query).Scan(&ratio); err != nil {
+		return 0, fmt.Errorf("postgresql: failed to read pg_buffercache: %w", err)
+	}
+	return ratio, nil
+}
@@ -0,0 +1,142 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPostgreSQLAdapter_TableBloatWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if _, err := a.TableBloat(context.Background(), "public", "users"); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_TableBloat(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, "CREATE EXTENSION IF NOT EXISTS pgstattuple"); err != nil {
+		t.Skipf("pgstattuple not available: %v", err)
+	}
+	if _, err := a.db.ExecContext(ctx, `CREATE TABLE bloat_test AS SELECT g AS id FROM generate_series(1, 1000) g;
+		DELETE FROM bloat_test WHERE id % 2 = 0`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE bloat_test") })
+
+	info, err := a.TableBloat(ctx, "public", "bloat_test")
+	if err != nil {
+		t.Fatalf("bloat failed: %v", err)
+	}
+	if info.TableLen <= 0 || info.DeadTupleCount != 500 {
+		t.Errorf("expected a non-empty table with 500 dead tuples, got %+v", info)
+	}
+}
+
+func TestMillisecondsToDuration(t *testing.T) {
+	if d := millisecondsToDuration(1.5); d != 1500*time.Microsecond {
+		t.Errorf("expected 1.5ms, got %s", d)
+	}
+}
+
+func TestPostgreSQLAdapter_IOStatsWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if _, err := a.IOStats(context.Background()); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+	if _, err := a.ServerVersion(context.Background()); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_IOStats(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	version, err := a.ServerVersion(ctx)
+	if err != nil {
+		t.Fatalf("server version failed: %v", err)
+	}
+
+	stats, err := a.IOStats(ctx)
+	if version < ioStatsVersion {
+		if !errors.Is(err, ErrUnsupportedVersion) {
+			t.Errorf("expected ErrUnsupportedVersion on %d, got %v", version, err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("io stats failed: %v", err)
+	}
+	if len(stats) == 0 {
+		t.Error("expected pg_stat_io rows, got none")
+	}
+}
+
+func TestWALStatsQuery(t *testing.T) {
+	if query := walStatsQuery(170000); !strings.Contains(query, "wal_write_time") || strings.Contains(query, "pg_stat_io") {
+		t.Errorf("expected pg_stat_wal timings before PostgreSQL 18, got %q", query)
+	}
+	if query := walStatsQuery(180000); !strings.Contains(query, "pg_stat_io") || strings.Contains(query, "wal_write_time") {
+		t.Errorf("expected pg_stat_io timings from PostgreSQL 18, got %q", query)
+	}
+}
+
+func TestPostgreSQLAdapter_WALStatsWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if _, err := a.WALStats(context.Background()); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_WALStats(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	version, err := a.ServerVersion(ctx)
+	if err != nil {
+		t.Fatalf("server version failed: %v", err)
+	}
+
+	stat, err := a.WALStats(ctx)
+	if version < walStatsVersion {
+		if !errors.Is(err, ErrUnsupportedVersion) {
+			t.Errorf("expected ErrUnsupportedVersion on %d, got %v", version, err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("wal stats failed: %v", err)
+	}
+	if stat.WALRecords < 0 || stat.WALBytes < 0 {
+		t.Errorf("unexpected counters: %+v", stat)
+	}
+}
+
+func TestPostgreSQLAdapter_BufferCacheHitRatioWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if _, err := a.BufferCacheHitRatio(context.Background()); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_BufferCacheHitRatio(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, "CREATE EXTENSION IF NOT EXISTS pg_buffercache"); err != nil {
+		t.Skipf("pg_buffercache not available: %v", err)
+	}
+
+	ratio, err := a.BufferCacheHitRatio(ctx)
+	if err != nil {
+		t.Fatalf("BufferCacheHitRatio failed: %v", err)
+	}
+	if ratio <= 0 || ratio > 1 {
+		t.Errorf("expected a ratio in (0, 1], got %v", ratio)
+	}
+}
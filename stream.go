@@ -0,0 +1,221 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// FetchEach runs the fetch operation op and calls fn for each row as it is
+// read, so large result sets are processed without holding them in memory.
+// If fn returns an error the cursor is closed and that error is returned.
+func (a *PostgreSQLAdapter) FetchEach(ctx context.Context, op *adapter.Operation, params map[string]interface{}, fn func(map[string]interface{}) error) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	rows, err := a.fetchRows(ctx, op, params)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	return a.eachRow(rows, fn)
+}
+
+// streamCursorSeq numbers the cursors FetchStream declares, so that streams
+// sharing a transaction do not clash.
+var streamCursorSeq atomic.Int64
+
+// FetchStream runs the fetch operation op through a server-side cursor and
+// sends its rows on the first channel in pages of pageSize, so that neither
+// the client nor the server holds the whole result set. The cursor is
+// declared in the transaction carried by ctx (see WithTx), or in one the
+// adapter opens and commits once the rows are exhausted. The
+// ExcludeSoftDeleted and LockMode operation options apply as for Fetch.
+//
+// The page channel is closed after the last page. A failure, including ctx
+// being cancelled, ends the stream and is then sent on the error channel,
+// which is closed once the stream is done; a nil receive from it means every
+// row was delivered. Callers must keep receiving pages until the page
+// channel closes or cancel ctx.
+func (a *PostgreSQLAdapter) FetchStream(ctx context.Context, op *adapter.Operation, params map[string]interface{}, pageSize int) (<-chan []map[string]interface{}, <-chan error) {
+	pages := make(chan []map[string]interface{})
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(pages)
+		if err := a.fetchStream(ctx, op, params, pageSize, pages); err != nil {
+			errs <- err
+		}
+	}()
+
+	return pages, errs
+}
+
+// fetchStream does the work of FetchStream, sending pages on pages.
+func (a *PostgreSQLAdapter) fetchStream(ctx context.Context, op *adapter.Operation, params map[string]interface{}, pageSize int, pages chan<- []map[string]interface{}) (err error) {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+	if pageSize < 1 {
+		return fmt.Errorf("postgresql: page size must be at least 1, got %d", pageSize)
+	}
+
+	query, err := fetchStatement(ctx, op.Statement)
+	if err != nil {
+		return err
+	}
+	query, err = lockStatement(query, OperationOptionsFromContext(ctx).LockMode)
+	if err != nil {
+		return err
+	}
+
+	args, err := extractArgs(query, params)
+	if err != nil {
+		return err
+	}
+
+	var tx *sql.Tx
+	owned := false
+	if ptx := a.txFromContext(ctx); ptx != nil {
+		tx = ptx.tx
+	} else {
+		if tx, err = a.beginTx(ctx, nil); err != nil {
+			return fmt.Errorf("postgresql: failed to begin transaction: %w", err)
+		}
+		owned = true
+		defer func() {
+			if err != nil {
+				_ = tx.Rollback()
+			}
+		}()
+	}
+
+	cursor := fmt.Sprintf("fetch_stream_%d", streamCursorSeq.Add(1))
+	declare := fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s", cursor, replaceNamedParams(query))
+	if _, err := tx.ExecContext(ctx, declare, args...); err != nil {
+		return fmt.Errorf("postgresql: failed to declare cursor: %w", err)
+	}
+	if !owned {
+		// The caller's transaction outlives the stream, so a cursor left
+		// open by a failure would hold its portal and locks until then.
+		defer func() {
+			if err != nil {
+				_, _ = tx.ExecContext(context.Background(), "CLOSE "+cursor)
+			}
+		}()
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM %s", pageSize, cursor)
+	for {
+		page, err := a.fetchCursorPage(ctx, tx, fetch)
+		if err != nil {
+			return err
+		}
+		if len(page) > 0 {
+			select {
+			case pages <- page:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if len(page) < pageSize {
+			break
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "CLOSE "+cursor); err != nil {
+		return fmt.Errorf("postgresql: failed to close cursor: %w", err)
+	}
+	if owned {
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("postgresql: failed to commit transaction: %w", err)
+		}
+	}
+	return nil
+}
+
+// fetchCursorPage runs the FETCH statement fetch in tx and returns its rows.
+func (a *PostgreSQLAdapter) fetchCursorPage(ctx context.Context, tx *sql.Tx, fetch string) ([]map[string]interface{}, error) {
+	rows, err := tx.QueryContext(ctx, fetch)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to fetch from cursor: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	results, err := a.scanRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	return rowMaps(results)
+}
+
+// FetchStreamParallel runs the fetch operation op and hands each row to fn on
+// one of concurrency worker goroutines, which suits slow, I/O-bound
+// callbacks. Rows are read only as fast as the workers take them, so at most
+// concurrency rows are held in memory at once.
+//
+// fn is called concurrently and rows are not processed in result order. The
+// first error returned by fn stops the stream; rows already handed to other
+// workers still finish, and that error is returned once every worker is done.
+func (a *PostgreSQLAdapter) FetchStreamParallel(ctx context.Context, op *adapter.Operation, params map[string]interface{}, concurrency int, fn func(map[string]interface{}) error) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+	if concurrency < 1 {
+		return fmt.Errorf("postgresql: concurrency must be at least 1, got %d", concurrency)
+	}
+
+	rows, err := a.fetchRows(ctx, op, params)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		failOnce sync.Once
+		fnErr    error
+	)
+	jobs := make(chan map[string]interface{})
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for row := range jobs {
+				if err := fn(row); err != nil {
+					failOnce.Do(func() {
+						fnErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+	scanErr := a.eachRow(rows, func(row map[string]interface{}) error {
+		select {
+		case jobs <- row:
+			return nil
+		case <-workCtx.Done():
+			return workCtx.Err()
+		}
+	})
+	close(jobs)
+	wg.Wait()
+
+	if fnErr != nil {
+		return fnErr
+	}
+	return scanErr
+}
@@ -0,0 +1,213 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestPostgreSQLAdapter_FetchEachWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	op := &adapter.Operation{Statement: "SELECT 1"}
+	err := a.FetchEach(context.Background(), op, nil, func(map[string]interface{}) error { return nil })
+	if err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_FetchEach(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+	op := &adapter.Operation{Statement: "SELECT g AS n FROM generate_series(1, 100) g"}
+
+	var seen []int64
+	stop := errors.New("stop")
+	err := a.FetchEach(ctx, op, nil, func(row map[string]interface{}) error {
+		seen = append(seen, row["n"].(int64))
+		if len(seen) == 3 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("expected callback error, got %v", err)
+	}
+	if len(seen) != 3 || seen[0] != 1 || seen[2] != 3 {
+		t.Errorf("expected rows 1..3 before stopping, got %v", seen)
+	}
+}
+
+func TestPostgreSQLAdapter_FetchStreamParallelWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	op := &adapter.Operation{Statement: "SELECT 1"}
+	err := a.FetchStreamParallel(context.Background(), op, nil, 4, func(map[string]interface{}) error { return nil })
+	if err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_FetchStreamParallel(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+	op := &adapter.Operation{Statement: "SELECT g AS n FROM generate_series(1, {max}) g"}
+
+	if err := a.FetchStreamParallel(ctx, op, nil, 0, nil); err == nil {
+		t.Error("expected error for zero concurrency, got nil")
+	}
+
+	var sum int64
+	err := a.FetchStreamParallel(ctx, op, map[string]interface{}{"max": 1000}, 8, func(row map[string]interface{}) error {
+		atomic.AddInt64(&sum, row["n"].(int64))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+	if sum != 500500 {
+		t.Errorf("expected sum 500500, got %d", sum)
+	}
+
+	stop := errors.New("stop")
+	var calls int64
+	err = a.FetchStreamParallel(ctx, op, map[string]interface{}{"max": 100000}, 4, func(map[string]interface{}) error {
+		if atomic.AddInt64(&calls, 1) == 10 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("expected callback error, got %v", err)
+	}
+	if calls >= 100000 {
+		t.Errorf("expected the stream to stop early, got %d calls", calls)
+	}
+}
+
+func TestPostgreSQLAdapter_FetchStreamWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	op := &adapter.Operation{Statement: "SELECT 1"}
+	pages, errs := a.FetchStream(context.Background(), op, nil, 10)
+	for range pages {
+		t.Error("expected no pages when not connected")
+	}
+	if err := <-errs; !errors.Is(err, ErrNotConnected) {
+		t.Errorf("expected ErrNotConnected, got %v", err)
+	}
+}
+
+func TestPostgreSQLAdapter_FetchStream(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+	op := &adapter.Operation{Statement: "SELECT g AS n FROM generate_series(1, {max}) g ORDER BY g"}
+
+	tests := []struct {
+		name     string
+		max      int
+		pageSize int
+		pages    []int
+	}{
+		{"partial last page", 25, 10, []int{10, 10, 5}},
+		{"exact pages", 20, 10, []int{10, 10}},
+		{"no rows", 0, 10, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pages, errs := a.FetchStream(ctx, op, map[string]interface{}{"max": tt.max}, tt.pageSize)
+
+			var sizes []int
+			next := int64(1)
+			for page := range pages {
+				sizes = append(sizes, len(page))
+				for _, row := range page {
+					if row["n"] != next {
+						t.Fatalf("expected row %d, got %v", next, row["n"])
+					}
+					next++
+				}
+			}
+			if err := <-errs; err != nil {
+				t.Fatalf("stream failed: %v", err)
+			}
+			if len(sizes) != len(tt.pages) {
+				t.Fatalf("expected pages of %v rows, got %v", tt.pages, sizes)
+			}
+			for i := range sizes {
+				if sizes[i] != tt.pages[i] {
+					t.Errorf("expected pages of %v rows, got %v", tt.pages, sizes)
+				}
+			}
+		})
+	}
+
+	_, errs := a.FetchStream(ctx, op, map[string]interface{}{"max": 1}, 0)
+	if err := <-errs; err == nil {
+		t.Error("expected error for a zero page size, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_FetchStreamInTx(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, `CREATE TABLE fetch_stream_test (id int PRIMARY KEY, deleted_at timestamptz);
+		INSERT INTO fetch_stream_test VALUES (1, NULL), (2, now()), (3, NULL), (4, NULL)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE fetch_stream_test") })
+
+	tx, err := a.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("begin failed: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	op := &adapter.Operation{Statement: "SELECT id FROM fetch_stream_test ORDER BY id"}
+	streamCtx := WithOperationOptions(WithTx(ctx, tx), OperationOptions{
+		SoftDelete:         &SoftDeleteOption{Column: "deleted_at"},
+		ExcludeSoftDeleted: true,
+		LockMode:           LockForUpdate,
+	})
+
+	var ids []interface{}
+	pages, errs := a.FetchStream(streamCtx, op, nil, 2)
+	for page := range pages {
+		for _, row := range page {
+			ids = append(ids, row["id"])
+		}
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+	if len(ids) != 3 || ids[0] != int64(1) || ids[1] != int64(3) || ids[2] != int64(4) {
+		t.Errorf("expected the live rows 1, 3 and 4, got %v", ids)
+	}
+	if _, err := a.db.ExecContext(ctx, "SELECT id FROM fetch_stream_test WHERE id = 3 FOR UPDATE NOWAIT"); err == nil {
+		t.Error("expected the streamed row to be locked by the transaction")
+	}
+
+	// A stream given up part way closes its cursor, leaving the
+	// transaction usable without it.
+	cancelCtx, cancel := context.WithCancel(WithTx(ctx, tx))
+	pages, errs = a.FetchStream(cancelCtx, op, nil, 1)
+	<-pages
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	for range pages {
+	}
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	var cursors int
+	if err := tx.tx.QueryRowContext(ctx, "SELECT count(*) FROM pg_cursors").Scan(&cursors); err != nil {
+		t.Fatalf("failed to count cursors: %v", err)
+	}
+	if cursors != 0 {
+		t.Errorf("expected no open cursors, got %d", cursors)
+	}
+}
@@ -0,0 +1,168 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LikeOptions selects what CreateTableLike copies from the source table
+// besides column names, types and NOT NULL constraints. Combine the flags
+// with |; the zero value copies everything (INCLUDING ALL).
+type LikeOptions uint
+
+// Flags for LikeOptions, each mapping to an INCLUDING clause.
+const (
+	LikeConstraints LikeOptions = 1 << iota
+	LikeDefaults
+	LikeIdentity
+	LikeIndexes
+	LikeStatistics
+	LikeStorage
+	LikeComments
+)
+
+// likeOptionNames lists the INCLUDING keyword of each flag in a fixed order.
+var likeOptionNames = []struct {
+	flag LikeOptions
+	name string
+}{
+	{LikeConstraints, "CONSTRAINTS"},
+	{LikeDefaults, "DEFAULTS"},
+	{LikeIdentity, "IDENTITY"},
+	{LikeIndexes, "INDEXES"},
+	{LikeStatistics, "STATISTICS"},
+	{LikeStorage, "STORAGE"},
+	{LikeComments, "COMMENTS"},
+}
+
+// CreateTableLike creates the table dest with the structure of source, as
+// CREATE TABLE dest (LIKE source INCLUDING ...). Either name may be
+// schema-qualified. Rows are not copied.
+func (a *PostgreSQLAdapter) CreateTableLike(ctx context.Context, source, dest string, opts LikeOptions) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	if _, err := a.execContext(ctx, "create_table_like", createTableLikeQuery(source, dest, opts)); err != nil {
+		return fmt.Errorf("postgresql: failed to create table %s like %s: %w", dest, source, err)
+	}
+	return nil
+}
+
+// createTableLikeQuery builds the statement run by CreateTableLike.
+func createTableLikeQuery(source, dest string, opts LikeOptions) string {
+	including := " INCLUDING ALL"
+	if opts != 0 {
+		including = ""
+		for _, opt := range likeOptionNames {
+			if opts&opt.flag != 0 {
+				including += " INCLUDING " + opt.name
+			}
+		}
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (LIKE %s%s)", QuoteIdentifier(dest), QuoteIdentifier(source), including)
+}
+
+// ColumnSpec describes a column added by AddColumn.
+type ColumnSpec struct {
+	// Name is the column name.
+	Name string
+	// DataType is a built-in PostgreSQL type, optionally with a length or
+	// precision modifier and array brackets: "varchar(255)", "numeric(10,2)",
+	// "text[]". Other types are rejected.
+	DataType string
+	// Nullable allows NULL; otherwise the column is NOT NULL, which needs a
+	// Default when the table already has rows.
+	Nullable bool
+	// Default is a SQL expression for the column default, such as "0" or
+	// "now()". It is inserted as is and must not come from untrusted input.
+	Default string
+}
+
+// allowedColumnTypes is the allowlist of types accepted in ColumnSpec.
+var allowedColumnTypes = map[string]bool{
+	"smallint": true, "integer": true, "int": true, "bigint": true,
+	"int2": true, "int4": true, "int8": true,
+	"real": true, "double precision": true, "float4": true, "float8": true,
+	"numeric": true, "decimal": true, "money": true,
+	"boolean": true, "bool": true,
+	"text": true, "varchar": true, "character varying": true, "char": true, "character": true,
+	"bytea": true, "uuid": true, "json": true, "jsonb": true, "xml": true,
+	"date": true, "time": true, "timetz": true, "timestamp": true, "timestamptz": true,
+	"timestamp with time zone": true, "timestamp without time zone": true, "interval": true,
+	"inet": true, "cidr": true, "macaddr": true, "tsvector": true,
+}
+
+// columnTypePattern splits a data type into its name, an optional
+// (n) or (p,s) modifier and optional array brackets.
+var columnTypePattern = regexp.MustCompile(`^([a-z][a-z0-9 ]*?)\s*(\(\s*\d+\s*(?:,\s*\d+\s*)?\))?((?:\[\])*)$`)
+
+// AddColumn adds col to schema.table unless a column of that name already
+// exists.
+func (a *PostgreSQLAdapter) AddColumn(ctx context.Context, schema, table string, col ColumnSpec) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	query, err := addColumnQuery(schema, table, col)
+	if err != nil {
+		return err
+	}
+	if _, err := a.execContext(ctx, "add_column", query); err != nil {
+		return fmt.Errorf("postgresql: failed to add column %s to %s.%s: %w", col.Name, schema, table, err)
+	}
+	return nil
+}
+
+// DropColumn removes column from schema.table if it exists.
+func (a *PostgreSQLAdapter) DropColumn(ctx context.Context, schema, table, column string) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	if _, err := a.execContext(ctx, "drop_column", dropColumnQuery(schema, table, column)); err != nil {
+		return fmt.Errorf("postgresql: failed to drop column %s from %s.%s: %w", column, schema, table, err)
+	}
+	return nil
+}
+
+// addColumnQuery builds the statement run by AddColumn.
+func addColumnQuery(schema, table string, col ColumnSpec) (string, error) {
+	if col.Name == "" {
+		return "", fmt.Errorf("postgresql: column name is required")
+	}
+	dataType, err := normalizeColumnType(col.DataType)
+	if err != nil {
+		return "", err
+	}
+
+	query := fmt.Sprintf("ALTER TABLE %s.%s ADD COLUMN IF NOT EXISTS %s %s",
+		QuoteIdentifier(schema), QuoteIdentifier(table), QuoteIdentifier(col.Name), dataType)
+	if !col.Nullable {
+		query += " NOT NULL"
+	}
+	if col.Default != "" {
+		query += " DEFAULT " + col.Default
+	}
+	return query, nil
+}
+
+// dropColumnQuery builds the statement run by DropColumn.
+func dropColumnQuery(schema, table, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s.%s DROP COLUMN IF EXISTS %s",
+		QuoteIdentifier(schema), QuoteIdentifier(table), QuoteIdentifier(column))
+}
+
+// normalizeColumnType checks dataType against allowedColumnTypes and returns
+// it lower-cased with redundant spaces removed.
+func normalizeColumnType(dataType string) (string, error) {
+	normalized := strings.Join(strings.Fields(strings.ToLower(dataType)), " ")
+	m := columnTypePattern.FindStringSubmatch(normalized)
+	if m == nil || !allowedColumnTypes[m[1]] {
+		return "", fmt.Errorf("postgresql: unsupported column type %q", dataType)
+	}
+	return m[1] + strings.ReplaceAll(m[2], " ", "") + m[3], nil
+}
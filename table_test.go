@@ -0,0 +1,107 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCreateTableLikeQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     LikeOptions
+		expected string
+	}{
+		{"all", 0, `CREATE TABLE "users_copy" (LIKE "public"."users" INCLUDING ALL)`},
+		{"defaults", LikeDefaults, `CREATE TABLE "users_copy" (LIKE "public"."users" INCLUDING DEFAULTS)`},
+		{
+			"several",
+			LikeIndexes | LikeConstraints | LikeComments,
+			`CREATE TABLE "users_copy" (LIKE "public"."users" INCLUDING CONSTRAINTS INCLUDING INDEXES INCLUDING COMMENTS)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := createTableLikeQuery("public.users", "users_copy", tt.opts); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_CreateTableLikeWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if err := a.CreateTableLike(context.Background(), "users", "users_copy", 0); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestAddColumnQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		col       ColumnSpec
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "nullable",
+			col:      ColumnSpec{Name: "nickname", DataType: "text", Nullable: true},
+			expected: `ALTER TABLE "public"."users" ADD COLUMN IF NOT EXISTS "nickname" text`,
+		},
+		{
+			name:     "not null with default",
+			col:      ColumnSpec{Name: "visits", DataType: "INTEGER", Default: "0"},
+			expected: `ALTER TABLE "public"."users" ADD COLUMN IF NOT EXISTS "visits" integer NOT NULL DEFAULT 0`,
+		},
+		{
+			name:     "modifier and array",
+			col:      ColumnSpec{Name: "prices", DataType: "numeric( 10, 2 )[]", Nullable: true},
+			expected: `ALTER TABLE "public"."users" ADD COLUMN IF NOT EXISTS "prices" numeric(10,2)[]`,
+		},
+		{
+			name:     "multi-word type",
+			col:      ColumnSpec{Name: "seen_at", DataType: "timestamp  with time zone", Nullable: true},
+			expected: `ALTER TABLE "public"."users" ADD COLUMN IF NOT EXISTS "seen_at" timestamp with time zone`,
+		},
+		{name: "unknown type", col: ColumnSpec{Name: "x", DataType: "my_type"}, expectErr: true},
+		{name: "injection", col: ColumnSpec{Name: "x", DataType: "text; DROP TABLE users"}, expectErr: true},
+		{name: "missing name", col: ColumnSpec{DataType: "text"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := addColumnQuery("public", "users", tt.col)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got %q", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestDropColumnQuery(t *testing.T) {
+	expected := `ALTER TABLE "public"."users" DROP COLUMN IF EXISTS "nickname"`
+	if result := dropColumnQuery("public", "users", "nickname"); result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestPostgreSQLAdapter_AlterColumnsWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	ctx := context.Background()
+
+	if err := a.AddColumn(ctx, "public", "users", ColumnSpec{Name: "x", DataType: "text"}); err == nil {
+		t.Error("AddColumn: expected error when not connected, got nil")
+	}
+	if err := a.DropColumn(ctx, "public", "users", "x"); err == nil {
+		t.Error("DropColumn: expected error when not connected, got nil")
+	}
+}
@@ -0,0 +1,105 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// WithTimestampColumns makes Insert set the createdAt and updatedAt columns
+// and Update set the updatedAt column to the current UTC time, unless the
+// object already holds a value for them. Either name may be empty to leave
+// that column alone.
+//
+// The columns need no mapping in op.Properties; when they have one, its
+// object field is used. Update adds "updatedAt = {field}" to the SET list
+// of statements that do not bind the field themselves. The values are
+// written back into the object maps, so callers see the stored times.
+func WithTimestampColumns(createdAt, updatedAt string) Option {
+	return func(a *PostgreSQLAdapter) {
+		a.createdAtColumn = createdAt
+		a.updatedAtColumn = updatedAt
+	}
+}
+
+// timestampInsert returns op with mappings for the timestamp columns added
+// and sets them on objects that lack them. op is returned as is when no
+// timestamp columns are configured.
+func (a *PostgreSQLAdapter) timestampInsert(op *adapter.Operation, objects []interface{}) *adapter.Operation {
+	if a.createdAtColumn == "" && a.updatedAtColumn == "" {
+		return op
+	}
+
+	now := time.Now().UTC()
+	stamped := *op
+	stamped.Properties = append([]adapter.PropertyMapping{}, op.Properties...)
+	for _, column := range []string{a.createdAtColumn, a.updatedAtColumn} {
+		if column == "" {
+			continue
+		}
+		field, mapped := timestampField(stamped.Properties, column)
+		if !mapped {
+			stamped.Properties = append(stamped.Properties, adapter.PropertyMapping{ObjectField: field, DataField: column})
+		}
+		setTimestamps(objects, field, now)
+	}
+	return &stamped
+}
+
+// timestampUpdate returns statement with the updatedAt column added to its
+// SET list and sets the column's field on objects that lack it. Statements
+// other than UPDATE are returned unchanged.
+func (a *PostgreSQLAdapter) timestampUpdate(op *adapter.Operation, statement string, objects []interface{}) string {
+	if a.updatedAtColumn == "" || statementOperation(statement, "") != "UPDATE" {
+		return statement
+	}
+
+	field, _ := timestampField(op.Properties, a.updatedAtColumn)
+	setTimestamps(objects, field, time.Now().UTC())
+	if strings.Contains(statement, "{"+field+"}") {
+		return statement
+	}
+	return addSetAssignment(statement, fmt.Sprintf("%s = {%s}", QuoteIdentifier(a.updatedAtColumn), field))
+}
+
+// timestampField returns the object field mapped to column, or the column
+// name itself when props has no mapping for it.
+func timestampField(props []adapter.PropertyMapping, column string) (string, bool) {
+	for _, prop := range props {
+		if prop.DataField == column {
+			return prop.ObjectField, true
+		}
+	}
+	return column, false
+}
+
+// setTimestamps sets field to now on every object that has no value for it.
+func setTimestamps(objects []interface{}, field string, now time.Time) {
+	for _, objInterface := range objects {
+		obj := objInterface.(map[string]interface{})
+		if _, ok := obj[field]; !ok {
+			obj[field] = now
+		}
+	}
+}
+
+// addSetAssignment appends assignment to the SET list of an UPDATE
+// statement, before its FROM, WHERE or RETURNING clause.
+func addSetAssignment(statement, assignment string) string {
+	statement = strings.TrimRight(strings.TrimSpace(statement), ";")
+	end := len(statement)
+	if loc := firstMatch(whereKeyword, statement); loc != nil {
+		end = loc[0]
+	} else if loc := lastMatch(returningKeyword, statement); loc != nil {
+		end = loc[0]
+	}
+
+	rest := ""
+	if end < len(statement) {
+		rest = " " + statement[end:]
+	}
+	set, from := splitUpdateFrom(statement[:end])
+	return set + ", " + assignment + from + rest
+}
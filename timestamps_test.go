@@ -0,0 +1,166 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestWithTimestampColumns(t *testing.T) {
+	a := NewPostgreSQLAdapter(WithTimestampColumns("created_at", "updated_at"))
+	if a.createdAtColumn != "created_at" || a.updatedAtColumn != "updated_at" {
+		t.Errorf("unexpected columns %q, %q", a.createdAtColumn, a.updatedAtColumn)
+	}
+}
+
+func TestAddSetAssignment(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		expected  string
+	}{
+		{
+			name:      "where",
+			statement: "UPDATE users SET name = {name} WHERE id = {id}",
+			expected:  `UPDATE users SET name = {name}, "updated_at" = {updated_at} WHERE id = {id}`,
+		},
+		{
+			name:      "returning",
+			statement: "UPDATE users SET name = {name} RETURNING id;",
+			expected:  `UPDATE users SET name = {name}, "updated_at" = {updated_at} RETURNING id`,
+		},
+		{
+			name:      "from",
+			statement: "UPDATE t SET a = o.a FROM o WHERE t.id = o.id",
+			expected:  `UPDATE t SET a = o.a, "updated_at" = {updated_at} FROM o WHERE t.id = o.id`,
+		},
+		{
+			name:      "from without where",
+			statement: "UPDATE t SET a = (SELECT max(a) FROM o) FROM o RETURNING t.id",
+			expected:  `UPDATE t SET a = (SELECT max(a) FROM o), "updated_at" = {updated_at} FROM o RETURNING t.id`,
+		},
+		{
+			name:      "no where",
+			statement: "UPDATE users SET name = {name};",
+			expected:  `UPDATE users SET name = {name}, "updated_at" = {updated_at}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := addSetAssignment(tt.statement, `"updated_at" = {updated_at}`)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestTimestampInsert(t *testing.T) {
+	a := NewPostgreSQLAdapter(WithTimestampColumns("created_at", "updated_at"))
+	given := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	op := &adapter.Operation{
+		Statement:  "users",
+		Properties: []adapter.PropertyMapping{{ObjectField: "Created", DataField: "created_at"}},
+	}
+	objects := []interface{}{
+		map[string]interface{}{"Created": given},
+		map[string]interface{}{},
+	}
+
+	stamped := a.timestampInsert(op, objects)
+	if len(op.Properties) != 1 {
+		t.Errorf("expected op to be left unchanged, got %d properties", len(op.Properties))
+	}
+	if len(stamped.Properties) != 2 || stamped.Properties[1].DataField != "updated_at" {
+		t.Fatalf("expected updated_at mapping to be added, got %+v", stamped.Properties)
+	}
+
+	first := objects[0].(map[string]interface{})
+	if first["Created"] != given {
+		t.Errorf("expected existing value to be kept, got %v", first["Created"])
+	}
+	second := objects[1].(map[string]interface{})
+	for _, field := range []string{"Created", "updated_at"} {
+		ts, ok := second[field].(time.Time)
+		if !ok || ts.Location() != time.UTC {
+			t.Errorf("expected UTC time for %s, got %v", field, second[field])
+		}
+	}
+
+	if plain := NewPostgreSQLAdapter().timestampInsert(op, objects); plain != op {
+		t.Error("expected op to be returned as is without timestamp columns")
+	}
+}
+
+func TestTimestampUpdate(t *testing.T) {
+	a := NewPostgreSQLAdapter(WithTimestampColumns("created_at", "updated_at"))
+	op := &adapter.Operation{}
+
+	tests := []struct {
+		name      string
+		statement string
+		expected  string
+	}{
+		{
+			name:      "added",
+			statement: "UPDATE users SET name = {name} WHERE id = {id}",
+			expected:  `UPDATE users SET name = {name}, "updated_at" = {updated_at} WHERE id = {id}`,
+		},
+		{
+			name:      "already bound",
+			statement: "UPDATE users SET updated_at = {updated_at} WHERE id = {id}",
+			expected:  "UPDATE users SET updated_at = {updated_at} WHERE id = {id}",
+		},
+		{
+			name:      "not an update",
+			statement: "SELECT touch_user({id})",
+			expected:  "SELECT touch_user({id})",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := a.timestampUpdate(op, tt.statement, nil); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_InsertTimestamps(t *testing.T) {
+	a := newIntegrationAdapter(t, WithTimestampColumns("created_at", "updated_at"))
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, `CREATE TABLE timestamps_test (
+		id int PRIMARY KEY, name text, created_at timestamptz, updated_at timestamptz)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE timestamps_test") })
+
+	insertOp := &adapter.Operation{
+		Statement: "timestamps_test",
+		Properties: []adapter.PropertyMapping{
+			{ObjectField: "id", DataField: "id"},
+			{ObjectField: "name", DataField: "name"},
+		},
+	}
+	if err := a.Insert(ctx, insertOp, []interface{}{map[string]interface{}{"id": 1, "name": "a"}}); err != nil {
+		t.Fatalf("insert failed: %v", err)
+	}
+
+	updateOp := &adapter.Operation{Statement: "UPDATE timestamps_test SET name = {name} WHERE id = {id}"}
+	if err := a.Update(ctx, updateOp, []interface{}{map[string]interface{}{"id": 1, "name": "b"}}); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+
+	var created, updated time.Time
+	if err := a.db.QueryRowContext(ctx, "SELECT created_at, updated_at FROM timestamps_test").Scan(&created, &updated); err != nil {
+		t.Fatalf("select failed: %v", err)
+	}
+	if created.IsZero() || updated.Before(created) {
+		t.Errorf("unexpected timestamps: created %v, updated %v", created, updated)
+	}
+}
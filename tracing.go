@@ -0,0 +1,92 @@
+package postgresql
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans of this package.
+const tracerName = "github.com/toutaio/toutago-datamapper-postgres"
+
+// WithTracerProvider makes Fetch, Insert, Update, Delete, Execute and the
+// savepoint statements of transactions record their spans with tp. Without
+// it they use the global provider from otel.GetTracerProvider, which does
+// nothing until the application installs one.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(a *PostgreSQLAdapter) {
+		a.tracerProvider = tp
+	}
+}
+
+// instrument starts tracing one call of method on statement and returns
+// the context to run it with and a function to call with its outcome, which
+// ends the span and records metrics.
+func (a *PostgreSQLAdapter) instrument(ctx context.Context, method, statement string) (context.Context, func(error)) {
+	ctx, span := a.startSpan(ctx, method, statement)
+	metrics, start := a.metrics, time.Now()
+	return ctx, func(err error) {
+		endSpan(span, err)
+		if metrics != nil {
+			metrics.observe(method, statement, time.Since(start), err)
+		}
+	}
+}
+
+// startSpan starts the client span "postgresql.<method>" for a statement,
+// as a child of the span in ctx.
+func (a *PostgreSQLAdapter) startSpan(ctx context.Context, method, statement string) (context.Context, trace.Span) {
+	tp := a.tracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+
+	return tp.Tracer(tracerName).Start(ctx, "postgresql."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.statement", statement),
+			attribute.String("db.operation", statementOperation(statement, method)),
+			attribute.String("db.name", a.dbName),
+			attribute.String("db.user", a.dbUser),
+		))
+}
+
+// endSpan records err, if any, on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// statementOperation returns the leading SQL keyword of statement, such as
+// SELECT or INSERT, or fallback when statement is not SQL, e.g. the bare
+// table name some operations take.
+func statementOperation(statement, fallback string) string {
+	fields := strings.Fields(statement)
+	if len(fields) < 2 {
+		return fallback
+	}
+	return strings.ToUpper(fields[0])
+}
+
+// traceIdentity returns the database and user that config connects to, for
+// the db.name and db.user span attributes.
+func traceIdentity(config map[string]interface{}) (database, user string) {
+	if connURL := getStringConfig(config, ConfigConnectionURL, ""); connURL != "" {
+		u, err := url.Parse(connURL)
+		if err != nil {
+			return "", ""
+		}
+		return strings.TrimPrefix(u.Path, "/"), u.User.Username()
+	}
+	return getStringConfig(config, ConfigDatabase, ""), getStringConfig(config, ConfigUser, "postgres")
+}
@@ -0,0 +1,115 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordingSpan keeps what the adapter reports on a span.
+type recordingSpan struct {
+	noop.Span
+	name   string
+	attrs  map[attribute.Key]string
+	status codes.Code
+	errs   []error
+	ended  bool
+}
+
+func (s *recordingSpan) RecordError(err error, _ ...trace.EventOption) { s.errs = append(s.errs, err) }
+func (s *recordingSpan) SetStatus(code codes.Code, _ string)           { s.status = code }
+func (s *recordingSpan) End(...trace.SpanEndOption)                    { s.ended = true }
+
+type recordingTracer struct {
+	noop.Tracer
+	spans *[]*recordingSpan
+}
+
+func (t recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	span := &recordingSpan{name: name, attrs: map[attribute.Key]string{}}
+	config := trace.NewSpanStartConfig(opts...)
+	for _, kv := range config.Attributes() {
+		span.attrs[kv.Key] = kv.Value.AsString()
+	}
+	*t.spans = append(*t.spans, span)
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+type recordingProvider struct {
+	noop.TracerProvider
+	spans []*recordingSpan
+}
+
+func (p *recordingProvider) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return recordingTracer{spans: &p.spans}
+}
+
+func TestPostgreSQLAdapter_TracingSpans(t *testing.T) {
+	tp := &recordingProvider{}
+	a := NewPostgreSQLAdapter(WithTracerProvider(tp))
+	a.dbName, a.dbUser = "app", "app_user"
+	ctx := context.Background()
+
+	op := &adapter.Operation{Statement: "SELECT * FROM users WHERE id = {id}"}
+	_, _ = a.Fetch(ctx, op, map[string]interface{}{"id": 1})
+	_ = a.Insert(ctx, &adapter.Operation{Statement: "INSERT INTO users (id) VALUES ({id})"}, nil)
+	_ = a.Update(ctx, &adapter.Operation{Statement: "UPDATE users SET name = {name}"}, nil)
+	_ = a.Delete(ctx, &adapter.Operation{Statement: "DELETE FROM users WHERE id = {id}"}, nil)
+	_, _ = a.Execute(ctx, &adapter.Action{Statement: "SELECT 1"}, nil)
+
+	expected := []struct{ name, operation string }{
+		{"postgresql.fetch", "SELECT"},
+		{"postgresql.insert", "INSERT"},
+		{"postgresql.update", "UPDATE"},
+		{"postgresql.delete", "DELETE"},
+		{"postgresql.execute", "SELECT"},
+	}
+	if len(tp.spans) != len(expected) {
+		t.Fatalf("expected %d spans, got %d", len(expected), len(tp.spans))
+	}
+	for i, exp := range expected {
+		span := tp.spans[i]
+		if span.name != exp.name || span.attrs["db.operation"] != exp.operation {
+			t.Errorf("span %d: expected %s/%s, got %s/%s", i, exp.name, exp.operation, span.name, span.attrs["db.operation"])
+		}
+		if span.attrs["db.name"] != "app" || span.attrs["db.user"] != "app_user" || span.attrs["db.statement"] == "" {
+			t.Errorf("span %d: unexpected attributes %v", i, span.attrs)
+		}
+		// Not connected, so every call fails.
+		if !span.ended || span.status != codes.Error || len(span.errs) != 1 {
+			t.Errorf("span %d: expected an ended span with a recorded error, got %+v", i, span)
+		}
+	}
+}
+
+func TestStatementOperation(t *testing.T) {
+	tests := []struct {
+		statement string
+		expected  string
+	}{
+		{"select * from users", "SELECT"},
+		{"  WITH x AS (SELECT 1) SELECT * FROM x", "WITH"},
+		{"users", "fallback"},
+		{"", "fallback"},
+	}
+
+	for _, tt := range tests {
+		if result := statementOperation(tt.statement, "fallback"); result != tt.expected {
+			t.Errorf("statementOperation(%q): expected %q, got %q", tt.statement, tt.expected, result)
+		}
+	}
+}
+
+func TestTraceIdentity(t *testing.T) {
+	if db, user := traceIdentity(map[string]interface{}{ConfigDatabase: "app", ConfigUser: "svc"}); db != "app" || user != "svc" {
+		t.Errorf("expected app/svc, got %s/%s", db, user)
+	}
+	if db, user := traceIdentity(map[string]interface{}{ConfigConnectionURL: "postgres://svc:secret@db:5432/app"}); db != "app" || user != "svc" {
+		t.Errorf("expected app/svc from URL, got %s/%s", db, user)
+	}
+}
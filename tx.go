@@ -0,0 +1,183 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// PostgreSQLTx is a database transaction, or a savepoint nested inside one.
+//
+// Adapter methods join the transaction when they are called with a context
+// returned by WithTx, so several Insert, Update and Delete calls can be made
+// atomic:
+//
+//	tx, err := a.BeginTx(ctx, nil)
+//	if err != nil {
+//	    return err
+//	}
+//	defer tx.Rollback()
+//
+//	txCtx := postgresql.WithTx(ctx, tx)
+//	if err := a.Insert(txCtx, insertOp, orders); err != nil {
+//	    return err
+//	}
+//	if err := a.Update(txCtx, updateOp, stock); err != nil {
+//	    return err
+//	}
+//	return tx.Commit()
+//
+// Calling BeginTx with a context that already carries a transaction starts a
+// nested transaction backed by a SAVEPOINT instead of a new BEGIN.
+type PostgreSQLTx struct {
+	adapter *PostgreSQLAdapter
+	tx      *sql.Tx
+	// ctx is the context the transaction was begun with, which bounds its
+	// savepoint statements as it bounds the transaction itself.
+	ctx context.Context
+
+	// savepoint is set for nested transactions and names the savepoint
+	// that Commit releases and Rollback rolls back to.
+	savepoint string
+	root      *PostgreSQLTx
+	nested    int
+	done      bool
+}
+
+type txKey struct{}
+
+// WithTx returns a copy of ctx that makes adapter methods run inside tx.
+func WithTx(ctx context.Context, tx *PostgreSQLTx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext returns the transaction carried by ctx, or nil.
+func TxFromContext(ctx context.Context) *PostgreSQLTx {
+	tx, _ := ctx.Value(txKey{}).(*PostgreSQLTx)
+	return tx
+}
+
+// BeginTx starts a transaction. When ctx already carries a transaction of
+// this adapter (see WithTx), a nested transaction is started with SAVEPOINT
+// and opts is ignored, as isolation is fixed by the outer transaction.
+func (a *PostgreSQLAdapter) BeginTx(ctx context.Context, opts *sql.TxOptions) (*PostgreSQLTx, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	if parent := a.txFromContext(ctx); parent != nil {
+		root := parent.root
+		root.nested++
+		name := fmt.Sprintf("nested_%d", root.nested)
+		nested := &PostgreSQLTx{adapter: a, tx: parent.tx, ctx: ctx, savepoint: name, root: root}
+		if err := nested.Savepoint(name); err != nil {
+			return nil, err
+		}
+		return nested, nil
+	}
+
+	tx, err := a.beginTx(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: failed to begin transaction: %w", err)
+	}
+
+	ptx := &PostgreSQLTx{adapter: a, tx: tx, ctx: ctx}
+	ptx.root = ptx
+	return ptx, nil
+}
+
+// Savepoint establishes a savepoint called name within the transaction.
+func (t *PostgreSQLTx) Savepoint(name string) error {
+	return t.exec("SAVEPOINT " + pq.QuoteIdentifier(name))
+}
+
+// RollbackTo undoes everything done since the savepoint called name was
+// established. The savepoint itself remains valid.
+func (t *PostgreSQLTx) RollbackTo(name string) error {
+	return t.exec("ROLLBACK TO SAVEPOINT " + pq.QuoteIdentifier(name))
+}
+
+// ReleaseSavepoint discards the savepoint called name, keeping the changes
+// made since it was established.
+func (t *PostgreSQLTx) ReleaseSavepoint(name string) error {
+	return t.exec("RELEASE SAVEPOINT " + pq.QuoteIdentifier(name))
+}
+
+// Commit commits the transaction. For a nested transaction it releases the
+// savepoint, leaving the outcome to the outer transaction.
+func (t *PostgreSQLTx) Commit() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+
+	if t.savepoint != "" {
+		return t.ReleaseSavepoint(t.savepoint)
+	}
+
+	if err := t.tx.Commit(); err != nil {
+		return fmt.Errorf("postgresql: failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Rollback aborts the transaction. For a nested transaction only the work
+// done since its savepoint is undone. Calling Rollback after Commit returns
+// sql.ErrTxDone, so it is safe to defer.
+func (t *PostgreSQLTx) Rollback() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+
+	if t.savepoint != "" {
+		if err := t.RollbackTo(t.savepoint); err != nil {
+			return err
+		}
+		return t.ReleaseSavepoint(t.savepoint)
+	}
+
+	if err := t.tx.Rollback(); err != nil {
+		return fmt.Errorf("postgresql: failed to roll back transaction: %w", err)
+	}
+	return nil
+}
+
+// exec runs a transaction control statement in the transaction, with the
+// context it was begun with and through the adapter's annotation, logging
+// and metrics like any other statement.
+func (t *PostgreSQLTx) exec(query string) error {
+	ctx, finish := t.adapter.instrument(WithTx(t.ctx, t), "savepoint", query)
+	_, err := t.adapter.execContext(ctx, "savepoint", query)
+	finish(err)
+	if err != nil {
+		return fmt.Errorf("postgresql: %s failed: %w", query, err)
+	}
+	return nil
+}
+
+// txFromContext returns the transaction carried by ctx if it belongs to a.
+func (a *PostgreSQLAdapter) txFromContext(ctx context.Context) *PostgreSQLTx {
+	if tx := TxFromContext(ctx); tx != nil && tx.adapter == a {
+		return tx
+	}
+	return nil
+}
+
+// querier is implemented by *sql.DB, *sql.Tx and *sql.Conn.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// querier returns the transaction carried by ctx, or the connection pool.
+func (a *PostgreSQLAdapter) querier(ctx context.Context) querier {
+	if tx := a.txFromContext(ctx); tx != nil {
+		return tx.tx
+	}
+	return a.db
+}
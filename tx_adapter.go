@@ -0,0 +1,147 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// TransactionalAdapter is implemented by adapters that can group
+// operations in a transaction. The adapter returned by BeginAdapterTx runs
+// every operation inside the transaction; it also implements TxFinisher,
+// through which the transaction is committed or rolled back.
+//
+// The method is not called BeginTx because PostgreSQLAdapter.BeginTx
+// already returns the *PostgreSQLTx used with WithTx.
+type TransactionalAdapter interface {
+	BeginAdapterTx(ctx context.Context, opts *sql.TxOptions) (adapter.Adapter, error)
+}
+
+// TxFinisher ends the transaction of an adapter returned by BeginAdapterTx.
+type TxFinisher interface {
+	Commit() error
+	Rollback() error
+}
+
+var _ TransactionalAdapter = (*PostgreSQLAdapter)(nil)
+
+// txAdapter is an adapter.Adapter whose operations run inside tx.
+type txAdapter struct {
+	adapter *PostgreSQLAdapter
+	tx      *PostgreSQLTx
+}
+
+// BeginAdapterTx starts a transaction like BeginTx and returns an adapter
+// that runs Fetch, Insert, Update, Delete and Execute inside it:
+//
+//	txa, err := a.BeginAdapterTx(ctx, nil)
+//	if err != nil {
+//	    return err
+//	}
+//	defer txa.Close()
+//
+//	if err := txa.Insert(ctx, insertOp, orders); err != nil {
+//	    return err
+//	}
+//	return txa.(postgresql.TxFinisher).Commit()
+//
+// Once the transaction is committed or rolled back, the adapter's methods
+// return ErrTxClosed. Close rolls back a transaction that is still open.
+func (a *PostgreSQLAdapter) BeginAdapterTx(ctx context.Context, opts *sql.TxOptions) (adapter.Adapter, error) {
+	tx, err := a.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &txAdapter{adapter: a, tx: tx}, nil
+}
+
+// txContext returns ctx carrying the transaction, or ErrTxClosed.
+func (t *txAdapter) txContext(ctx context.Context) (context.Context, error) {
+	if t.tx.done {
+		return nil, ErrTxClosed
+	}
+	return WithTx(ctx, t.tx), nil
+}
+
+// Fetch runs Fetch inside the transaction.
+func (t *txAdapter) Fetch(ctx context.Context, op *adapter.Operation, params map[string]interface{}) ([]interface{}, error) {
+	ctx, err := t.txContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return t.adapter.Fetch(ctx, op, params)
+}
+
+// Insert runs Insert inside the transaction.
+func (t *txAdapter) Insert(ctx context.Context, op *adapter.Operation, objects []interface{}) error {
+	ctx, err := t.txContext(ctx)
+	if err != nil {
+		return err
+	}
+	return t.adapter.Insert(ctx, op, objects)
+}
+
+// Update runs Update inside the transaction.
+func (t *txAdapter) Update(ctx context.Context, op *adapter.Operation, objects []interface{}) error {
+	ctx, err := t.txContext(ctx)
+	if err != nil {
+		return err
+	}
+	return t.adapter.Update(ctx, op, objects)
+}
+
+// Delete runs Delete inside the transaction.
+func (t *txAdapter) Delete(ctx context.Context, op *adapter.Operation, identifiers []interface{}) error {
+	ctx, err := t.txContext(ctx)
+	if err != nil {
+		return err
+	}
+	return t.adapter.Delete(ctx, op, identifiers)
+}
+
+// Execute runs Execute inside the transaction.
+func (t *txAdapter) Execute(ctx context.Context, action *adapter.Action, params map[string]interface{}) (interface{}, error) {
+	ctx, err := t.txContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return t.adapter.Execute(ctx, action, params)
+}
+
+// Connect always fails: the transaction uses the connection of the adapter
+// that started it.
+func (t *txAdapter) Connect(context.Context, map[string]interface{}) error {
+	return fmt.Errorf("postgresql: cannot connect a transaction adapter")
+}
+
+// Close rolls back the transaction if it is still open. It does not close
+// the adapter that started it.
+func (t *txAdapter) Close() error {
+	if t.tx.done {
+		return nil
+	}
+	return t.tx.Rollback()
+}
+
+// Name returns the name of the adapter that started the transaction.
+func (t *txAdapter) Name() string {
+	return t.adapter.Name()
+}
+
+// Commit commits the transaction.
+func (t *txAdapter) Commit() error {
+	if t.tx.done {
+		return ErrTxClosed
+	}
+	return t.tx.Commit()
+}
+
+// Rollback rolls back the transaction.
+func (t *txAdapter) Rollback() error {
+	if t.tx.done {
+		return ErrTxClosed
+	}
+	return t.tx.Rollback()
+}
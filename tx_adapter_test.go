@@ -0,0 +1,98 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestPostgreSQLAdapter_BeginAdapterTxWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if _, err := a.BeginAdapterTx(context.Background(), nil); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestTxAdapter_Closed(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	txa := &txAdapter{adapter: a, tx: &PostgreSQLTx{adapter: a, done: true}}
+	ctx := context.Background()
+	op := &adapter.Operation{Statement: "SELECT 1"}
+
+	if _, err := txa.Fetch(ctx, op, nil); !errors.Is(err, ErrTxClosed) {
+		t.Errorf("Fetch: expected ErrTxClosed, got %v", err)
+	}
+	if err := txa.Insert(ctx, op, []interface{}{map[string]interface{}{}}); !errors.Is(err, ErrTxClosed) {
+		t.Errorf("Insert: expected ErrTxClosed, got %v", err)
+	}
+	if err := txa.Update(ctx, op, nil); !errors.Is(err, ErrTxClosed) {
+		t.Errorf("Update: expected ErrTxClosed, got %v", err)
+	}
+	if err := txa.Delete(ctx, op, nil); !errors.Is(err, ErrTxClosed) {
+		t.Errorf("Delete: expected ErrTxClosed, got %v", err)
+	}
+	if _, err := txa.Execute(ctx, &adapter.Action{Statement: "SELECT 1"}, nil); !errors.Is(err, ErrTxClosed) {
+		t.Errorf("Execute: expected ErrTxClosed, got %v", err)
+	}
+	if err := txa.Commit(); !errors.Is(err, ErrTxClosed) {
+		t.Errorf("Commit: expected ErrTxClosed, got %v", err)
+	}
+	if err := txa.Rollback(); !errors.Is(err, ErrTxClosed) {
+		t.Errorf("Rollback: expected ErrTxClosed, got %v", err)
+	}
+	if err := txa.Close(); err != nil {
+		t.Errorf("Close: expected nil, got %v", err)
+	}
+	if err := txa.Connect(ctx, nil); err == nil {
+		t.Error("Connect: expected error, got nil")
+	}
+	if name := txa.Name(); name != "postgresql" {
+		t.Errorf("expected name 'postgresql', got %q", name)
+	}
+}
+
+func TestPostgreSQLAdapter_BeginAdapterTx(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, "CREATE TABLE tx_adapter_test (id int PRIMARY KEY)"); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE tx_adapter_test") })
+
+	op := &adapter.Operation{
+		Statement:  "tx_adapter_test",
+		Properties: []adapter.PropertyMapping{{ObjectField: "id", DataField: "id"}},
+	}
+	for _, commit := range []bool{false, true} {
+		txa, err := a.BeginAdapterTx(ctx, nil)
+		if err != nil {
+			t.Fatalf("begin failed: %v", err)
+		}
+		if err := txa.Insert(ctx, op, []interface{}{map[string]interface{}{"id": 1}}); err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+		finisher := txa.(TxFinisher)
+		if commit {
+			err = finisher.Commit()
+		} else {
+			err = finisher.Rollback()
+		}
+		if err != nil {
+			t.Fatalf("finish failed: %v", err)
+		}
+		if err := txa.Insert(ctx, op, []interface{}{map[string]interface{}{"id": 2}}); !errors.Is(err, ErrTxClosed) {
+			t.Errorf("expected ErrTxClosed after finishing, got %v", err)
+		}
+
+		var count int
+		if err := a.db.QueryRowContext(ctx, "SELECT count(*) FROM tx_adapter_test").Scan(&count); err != nil {
+			t.Fatalf("count failed: %v", err)
+		}
+		if want := map[bool]int{false: 0, true: 1}[commit]; count != want {
+			t.Errorf("commit=%v: expected %d rows, got %d", commit, want, count)
+		}
+	}
+}
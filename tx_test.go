@@ -0,0 +1,132 @@
+package postgresql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestWithTx(t *testing.T) {
+	ctx := context.Background()
+	if tx := TxFromContext(ctx); tx != nil {
+		t.Errorf("expected no transaction, got %v", tx)
+	}
+
+	a := NewPostgreSQLAdapter()
+	tx := &PostgreSQLTx{adapter: a}
+	ctx = WithTx(ctx, tx)
+	if got := TxFromContext(ctx); got != tx {
+		t.Errorf("expected transaction from context, got %v", got)
+	}
+	if got := a.txFromContext(ctx); got != tx {
+		t.Errorf("expected adapter to use its own transaction, got %v", got)
+	}
+
+	other := NewPostgreSQLAdapter()
+	if got := other.txFromContext(ctx); got != nil {
+		t.Errorf("expected another adapter to ignore the transaction, got %v", got)
+	}
+}
+
+func TestPostgreSQLAdapter_BeginTxWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	if _, err := a.BeginTx(context.Background(), nil); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLTx_FinishTwice(t *testing.T) {
+	tx := &PostgreSQLTx{done: true}
+	if err := tx.Commit(); !errors.Is(err, sql.ErrTxDone) {
+		t.Errorf("expected sql.ErrTxDone from Commit, got %v", err)
+	}
+	if err := tx.Rollback(); !errors.Is(err, sql.ErrTxDone) {
+		t.Errorf("expected sql.ErrTxDone from Rollback, got %v", err)
+	}
+}
+
+func TestPostgreSQLTx_NestedSavepoints(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, "CREATE TABLE tx_test (id int PRIMARY KEY)"); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE tx_test") })
+
+	tx, err := a.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin: %v", err)
+	}
+	defer tx.Rollback()
+	txCtx := WithTx(ctx, tx)
+
+	insert := &adapter.Operation{
+		Statement:  "tx_test",
+		Properties: []adapter.PropertyMapping{{ObjectField: "ID", DataField: "id"}},
+	}
+	if err := a.Insert(txCtx, insert, []interface{}{map[string]interface{}{"ID": 1}}); err != nil {
+		t.Fatalf("outer insert failed: %v", err)
+	}
+
+	nested, err := a.BeginTx(txCtx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin nested: %v", err)
+	}
+	if err := a.Insert(WithTx(ctx, nested), insert, []interface{}{map[string]interface{}{"ID": 2}}); err != nil {
+		t.Fatalf("nested insert failed: %v", err)
+	}
+	if err := nested.Rollback(); err != nil {
+		t.Fatalf("nested rollback failed: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+
+	var count int
+	if err := a.db.QueryRowContext(ctx, "SELECT count(*) FROM tx_test").Scan(&count); err != nil {
+		t.Fatalf("count failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected only the outer insert to survive, got %d rows", count)
+	}
+}
+
+func TestPostgreSQLTx_SavepointContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	a := newIntegrationAdapter(t, WithLogger(logger))
+	ctx := context.Background()
+
+	tx, err := a.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := tx.Savepoint("before_import"); err != nil {
+		t.Fatalf("savepoint failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `SAVEPOINT \"before_import\"`) {
+		t.Errorf("expected the savepoint to be logged, got %q", buf.String())
+	}
+
+	// A nested transaction's savepoint statements run with the context it
+	// was begun with.
+	nestedCtx, cancel := context.WithCancel(WithTx(ctx, tx))
+	nested, err := a.BeginTx(nestedCtx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin nested: %v", err)
+	}
+	cancel()
+	if err := nested.Rollback(); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled from the nested rollback, got %v", err)
+	}
+}
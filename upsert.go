@@ -0,0 +1,309 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+// UpsertOptions turns inserts into upserts. Attach it to a call through
+// OperationOptions.Upsert:
+//
+//	ctx = postgresql.WithOperationOptions(ctx, postgresql.OperationOptions{
+//	    Upsert: &postgresql.UpsertOptions{ConflictColumns: []string{"email"}},
+//	})
+//
+// When DoNothing is set and the operation has generated columns, rows
+// skipped because of a conflict return nothing from RETURNING and their
+// generated fields are left unset.
+type UpsertOptions struct {
+	// ConflictColumns is the conflict target, normally the columns of a
+	// unique index. Ignored when Constraint is set.
+	ConflictColumns []string
+
+	// Constraint names a unique or exclusion constraint to use as the
+	// conflict target instead of ConflictColumns.
+	Constraint string
+
+	// DoNothing skips conflicting rows instead of updating them. It is the
+	// only action allowed without a conflict target.
+	DoNothing bool
+
+	// UpdateColumns lists the columns overwritten with the proposed values
+	// on conflict. Empty means every inserted column except ConflictColumns.
+	UpdateColumns []string
+}
+
+// Replace inserts obj into the table named by op.Statement or, when a row
+// with the same key already exists, overwrites every non-key column of that
+// row. The key columns are taken from op.Identifier.
+//
+// This is MySQL's REPLACE semantics expressed as
+// INSERT ... ON CONFLICT (<keys>) DO UPDATE SET col = EXCLUDED.col, which,
+// unlike REPLACE, never deletes the existing row and so keeps foreign keys
+// pointing at it intact.
+func (a *PostgreSQLAdapter) Replace(ctx context.Context, op *adapter.Operation, obj map[string]interface{}) error {
+	if a.db == nil {
+		return ErrNotConnected
+	}
+
+	if len(op.Identifier) == 0 {
+		return fmt.Errorf("postgresql: replace requires identifier columns")
+	}
+
+	props := insertProperties(ctx, op)
+	for _, id := range op.Identifier {
+		if !hasDataField(props, id.DataField) {
+			props = append(props, id)
+		}
+	}
+
+	columns := make([]string, len(props))
+	for i, prop := range props {
+		columns[i] = prop.DataField
+	}
+	values, err := objectArgs(obj, props)
+	if err != nil {
+		return err
+	}
+
+	keyColumns := make([]string, len(op.Identifier))
+	for i, id := range op.Identifier {
+		keyColumns[i] = id.DataField
+	}
+
+	query := buildReplaceQuery(op.Statement, columns, keyColumns)
+	if _, err := a.execContext(ctx, operationName(op, "replace"), query, values...); err != nil {
+		return fmt.Errorf("postgresql: replace failed: %w", err)
+	}
+
+	return nil
+}
+
+// buildReplaceQuery renders an INSERT that overwrites every non-key column
+// on a key conflict. When every column is a key column there is nothing to
+// overwrite and the conflicting row is left as is.
+func buildReplaceQuery(table string, columns, keyColumns []string) string {
+	placeholders := make([]string, len(columns))
+	for i := range columns {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+
+	// A conflict target is always present, so this cannot fail.
+	conflict, _ := onConflictClause(&UpsertOptions{ConflictColumns: keyColumns}, columns)
+
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)%s",
+		QuoteIdentifier(table),
+		strings.Join(columns, ", "),
+		strings.Join(placeholders, ", "),
+		conflict)
+}
+
+// onConflictClause renders the ON CONFLICT clause described by opts for an
+// INSERT of columns, with a leading space. It returns "" when opts is nil.
+func onConflictClause(opts *UpsertOptions, columns []string) (string, error) {
+	if opts == nil {
+		return "", nil
+	}
+
+	var target string
+	switch {
+	case opts.Constraint != "":
+		target = " ON CONSTRAINT " + opts.Constraint
+	case len(opts.ConflictColumns) > 0:
+		target = " (" + strings.Join(opts.ConflictColumns, ", ") + ")"
+	}
+
+	if opts.DoNothing {
+		return " ON CONFLICT" + target + " DO NOTHING", nil
+	}
+
+	if target == "" {
+		return "", fmt.Errorf("postgresql: ON CONFLICT DO UPDATE requires conflict columns or a constraint")
+	}
+
+	updateColumns := opts.UpdateColumns
+	if len(updateColumns) == 0 {
+		for _, col := range columns {
+			if !containsString(opts.ConflictColumns, col) {
+				updateColumns = append(updateColumns, col)
+			}
+		}
+	}
+
+	// Nothing besides the key was inserted, so there is nothing to update.
+	if len(updateColumns) == 0 {
+		return " ON CONFLICT" + target + " DO NOTHING", nil
+	}
+
+	assignments := make([]string, len(updateColumns))
+	for i, col := range updateColumns {
+		assignments[i] = fmt.Sprintf("%s = EXCLUDED.%s", col, col)
+	}
+
+	return " ON CONFLICT" + target + " DO UPDATE SET " + strings.Join(assignments, ", "), nil
+}
+
+// hasDataField reports whether props maps a property to dataField.
+func hasDataField(props []adapter.PropertyMapping, dataField string) bool {
+	for _, prop := range props {
+		if prop.DataField == dataField {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString reports whether s is in list.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Actions reported in UpsertResult.Action.
+const (
+	UpsertInserted = "inserted"
+	UpsertUpdated  = "updated"
+)
+
+// UpsertResult reports what BulkUpsertWithStatus did with one object.
+type UpsertResult struct {
+	// ID is the value of the operation's identifier column for the row, or
+	// a []interface{} of the values in op.Identifier order for composite
+	// keys.
+	ID interface{}
+	// Action is UpsertInserted or UpsertUpdated.
+	Action string
+}
+
+// BulkUpsertWithStatus upserts objects into the table named by op.Statement
+// and reports, for each object in order, whether its row was inserted or
+// an existing row updated. The conflict target is taken from
+// OperationOptions.Upsert when set and from op.Identifier otherwise.
+//
+// The action comes from the system column xmax, which is 0 for a freshly
+// inserted row version and holds the locking transaction id for a row
+// updated by ON CONFLICT DO UPDATE. DO NOTHING is rejected: skipped rows
+// return nothing, so there would be no status to report for them. As with
+// any multi-row upsert, two objects with the same key in one call make
+// PostgreSQL fail the statement.
+func (a *PostgreSQLAdapter) BulkUpsertWithStatus(ctx context.Context, op *adapter.Operation, objects []interface{}) ([]UpsertResult, error) {
+	if a.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	if len(op.Identifier) == 0 {
+		return nil, fmt.Errorf("postgresql: bulk upsert requires identifier columns")
+	}
+	if len(objects) == 0 {
+		return nil, nil
+	}
+
+	props := insertProperties(ctx, op)
+	columns := make([]string, len(props))
+	for i, prop := range props {
+		columns[i] = prop.DataField
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("postgresql: bulk upsert requires at least one column")
+	}
+
+	upsert := OperationOptionsFromContext(ctx).Upsert
+	if upsert == nil {
+		keyColumns := make([]string, len(op.Identifier))
+		for i, id := range op.Identifier {
+			keyColumns[i] = id.DataField
+		}
+		upsert = &UpsertOptions{ConflictColumns: keyColumns}
+	}
+	conflict, err := onConflictClause(upsert, columns)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(conflict, "DO NOTHING") {
+		return nil, fmt.Errorf("postgresql: bulk upsert with status requires ON CONFLICT DO UPDATE")
+	}
+
+	results := make([]UpsertResult, 0, len(objects))
+	chunkSize := maxBindParams / len(columns)
+	for start := 0; start < len(objects); start += chunkSize {
+		end := min(start+chunkSize, len(objects))
+		chunk, err := a.bulkUpsertChunk(ctx, op, props, columns, conflict, objects[start:end])
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, chunk...)
+	}
+	return results, nil
+}
+
+// bulkUpsertChunk runs one multi-row upsert and reads back the key and
+// action of every row in the order the objects were listed.
+func (a *PostgreSQLAdapter) bulkUpsertChunk(ctx context.Context, op *adapter.Operation, props []adapter.PropertyMapping, columns []string, conflict string, objects []interface{}) ([]UpsertResult, error) {
+	valueRows, values, err := multiRowValues(props, objects)
+	if err != nil {
+		return nil, err
+	}
+	query := buildUpsertWithStatusQuery(op, columns, valueRows, conflict)
+
+	rows, err := a.queryContext(ctx, operationName(op, "bulk_upsert"), query, values...)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: bulk upsert failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	results := make([]UpsertResult, 0, len(objects))
+	for rows.Next() {
+		keys := make([]interface{}, len(op.Identifier))
+		dest := make([]interface{}, len(keys)+1)
+		for i := range keys {
+			dest[i] = &keys[i]
+		}
+		var inserted bool
+		dest[len(keys)] = &inserted
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("postgresql: scan failed: %w", err)
+		}
+
+		result := UpsertResult{ID: keys, Action: UpsertUpdated}
+		if len(keys) == 1 {
+			result.ID = keys[0]
+		}
+		if inserted {
+			result.Action = UpsertInserted
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgresql: bulk upsert failed: %w", err)
+	}
+	if len(results) != len(objects) {
+		return nil, fmt.Errorf("postgresql: bulk upsert returned %d rows for %d objects", len(results), len(objects))
+	}
+	return results, nil
+}
+
+// buildUpsertWithStatusQuery renders the multi-row upsert used by
+// BulkUpsertWithStatus, returning the key columns and whether each row was
+// inserted.
+func buildUpsertWithStatusQuery(op *adapter.Operation, columns []string, valueRows, conflict string) string {
+	returning := make([]string, 0, len(op.Identifier)+1)
+	for _, id := range op.Identifier {
+		returning = append(returning, id.DataField)
+	}
+	returning = append(returning, "(xmax = 0) AS inserted")
+
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s%s RETURNING %s",
+		QuoteIdentifier(op.Statement),
+		strings.Join(columns, ", "),
+		valueRows,
+		conflict,
+		strings.Join(returning, ", "))
+}
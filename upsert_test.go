@@ -0,0 +1,166 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/toutaio/toutago-datamapper/adapter"
+)
+
+func TestBuildReplaceQuery(t *testing.T) {
+	tests := []struct {
+		name       string
+		columns    []string
+		keyColumns []string
+		expected   string
+	}{
+		{
+			name:       "single key",
+			columns:    []string{"id", "name", "email"},
+			keyColumns: []string{"id"},
+			expected: `INSERT INTO "users" (id, name, email) VALUES ($1, $2, $3) ` +
+				"ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, email = EXCLUDED.email",
+		},
+		{
+			name:       "composite key",
+			columns:    []string{"tenant_id", "user_id", "role"},
+			keyColumns: []string{"tenant_id", "user_id"},
+			expected: `INSERT INTO "users" (tenant_id, user_id, role) VALUES ($1, $2, $3) ` +
+				"ON CONFLICT (tenant_id, user_id) DO UPDATE SET role = EXCLUDED.role",
+		},
+		{
+			name:       "key columns only",
+			columns:    []string{"id"},
+			keyColumns: []string{"id"},
+			expected:   `INSERT INTO "users" (id) VALUES ($1) ON CONFLICT (id) DO NOTHING`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := buildReplaceQuery("users", tt.columns, tt.keyColumns); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPostgreSQLAdapter_ReplaceWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	op := &adapter.Operation{Statement: "users"}
+	if err := a.Replace(context.Background(), op, map[string]interface{}{"ID": 1}); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestOnConflictClause(t *testing.T) {
+	columns := []string{"email", "name", "visits"}
+
+	tests := []struct {
+		name      string
+		opts      *UpsertOptions
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "no upsert",
+			opts:     nil,
+			expected: "",
+		},
+		{
+			name:     "do nothing without target",
+			opts:     &UpsertOptions{DoNothing: true},
+			expected: " ON CONFLICT DO NOTHING",
+		},
+		{
+			name:     "do nothing on columns",
+			opts:     &UpsertOptions{ConflictColumns: []string{"email"}, DoNothing: true},
+			expected: " ON CONFLICT (email) DO NOTHING",
+		},
+		{
+			name:     "update all non-conflict columns",
+			opts:     &UpsertOptions{ConflictColumns: []string{"email"}},
+			expected: " ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name, visits = EXCLUDED.visits",
+		},
+		{
+			name:     "update selected columns on constraint",
+			opts:     &UpsertOptions{Constraint: "users_email_key", UpdateColumns: []string{"visits"}},
+			expected: " ON CONFLICT ON CONSTRAINT users_email_key DO UPDATE SET visits = EXCLUDED.visits",
+		},
+		{
+			name:      "update without target",
+			opts:      &UpsertOptions{UpdateColumns: []string{"visits"}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := onConflictClause(tt.opts, columns)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestBuildUpsertWithStatusQuery(t *testing.T) {
+	op := &adapter.Operation{
+		Statement:  "users",
+		Identifier: []adapter.PropertyMapping{{ObjectField: "ID", DataField: "id"}},
+	}
+	conflict, err := onConflictClause(&UpsertOptions{ConflictColumns: []string{"id"}}, []string{"id", "name"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `INSERT INTO "users" (id, name) VALUES ($1, $2), ($3, $4) ` +
+		"ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name RETURNING id, (xmax = 0) AS inserted"
+	if result := buildUpsertWithStatusQuery(op, []string{"id", "name"}, "($1, $2), ($3, $4)", conflict); result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestPostgreSQLAdapter_BulkUpsertWithStatusWithoutConnect(t *testing.T) {
+	a := NewPostgreSQLAdapter()
+	op := &adapter.Operation{Statement: "users"}
+	if _, err := a.BulkUpsertWithStatus(context.Background(), op, []interface{}{map[string]interface{}{"ID": 1}}); err == nil {
+		t.Error("expected error when not connected, got nil")
+	}
+}
+
+func TestPostgreSQLAdapter_BulkUpsertWithStatus(t *testing.T) {
+	a := newIntegrationAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.db.ExecContext(ctx, `CREATE TABLE upsert_status_test (id int PRIMARY KEY, name text);
+		INSERT INTO upsert_status_test VALUES (1, 'a')`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = a.db.Exec("DROP TABLE upsert_status_test") })
+
+	op := &adapter.Operation{
+		Statement:  "upsert_status_test",
+		Properties: []adapter.PropertyMapping{{ObjectField: "ID", DataField: "id"}, {ObjectField: "Name", DataField: "name"}},
+		Identifier: []adapter.PropertyMapping{{ObjectField: "ID", DataField: "id"}},
+	}
+	results, err := a.BulkUpsertWithStatus(ctx, op, []interface{}{
+		map[string]interface{}{"ID": 1, "Name": "b"},
+		map[string]interface{}{"ID": 2, "Name": "c"},
+	})
+	if err != nil {
+		t.Fatalf("bulk upsert failed: %v", err)
+	}
+	if len(results) != 2 || results[0].Action != UpsertUpdated || results[1].Action != UpsertInserted {
+		t.Errorf("expected [updated inserted], got %+v", results)
+	}
+}
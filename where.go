@@ -0,0 +1,220 @@
+package postgresql
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// WhereBuilder assembles a parameterised WHERE clause from conditions joined
+// with AND. Column names are validated and quoted; values are always bound
+// as parameters. The first invalid column is reported by Build.
+//
+//	where, args, err := postgresql.NewWhereBuilder().
+//	    Eq("status", "active").
+//	    Gte("created_at", since).
+//	    Build(0)
+type WhereBuilder struct {
+	conds []whereCond
+	args  []interface{}
+	err   error
+}
+
+// whereCond renders one condition, calling next for the placeholder of each
+// of its arguments in the order they were added.
+type whereCond func(next func() string) string
+
+// NewWhereBuilder returns an empty WhereBuilder.
+func NewWhereBuilder() *WhereBuilder {
+	return &WhereBuilder{}
+}
+
+// WhereFromMap builds a WhereBuilder from a column-to-value filter, in column
+// order: nil values become IS NULL, slices become IN, anything else equality.
+func WhereFromMap(filter map[string]interface{}) *WhereBuilder {
+	w := NewWhereBuilder()
+
+	columns := make([]string, 0, len(filter))
+	for column := range filter {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	for _, column := range columns {
+		value := filter[column]
+		rv := reflect.ValueOf(value)
+		switch {
+		case value == nil:
+			w.IsNull(column)
+		case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8:
+			values := make([]interface{}, rv.Len())
+			for i := range values {
+				values[i] = rv.Index(i).Interface()
+			}
+			w.In(column, values)
+		default:
+			w.Eq(column, value)
+		}
+	}
+	return w
+}
+
+// Eq adds column = value.
+func (w *WhereBuilder) Eq(column string, value interface{}) *WhereBuilder {
+	return w.compare(column, "=", value)
+}
+
+// NotEq adds column <> value.
+func (w *WhereBuilder) NotEq(column string, value interface{}) *WhereBuilder {
+	return w.compare(column, "<>", value)
+}
+
+// Gt adds column > value.
+func (w *WhereBuilder) Gt(column string, value interface{}) *WhereBuilder {
+	return w.compare(column, ">", value)
+}
+
+// Gte adds column >= value.
+func (w *WhereBuilder) Gte(column string, value interface{}) *WhereBuilder {
+	return w.compare(column, ">=", value)
+}
+
+// Lt adds column < value.
+func (w *WhereBuilder) Lt(column string, value interface{}) *WhereBuilder {
+	return w.compare(column, "<", value)
+}
+
+// Lte adds column <= value.
+func (w *WhereBuilder) Lte(column string, value interface{}) *WhereBuilder {
+	return w.compare(column, "<=", value)
+}
+
+// In adds column IN (values...). An empty list matches no rows.
+func (w *WhereBuilder) In(column string, values []interface{}) *WhereBuilder {
+	return w.list(column, "IN", "FALSE", values)
+}
+
+// NotIn adds column NOT IN (values...). An empty list matches every row.
+// As in SQL, a NULL among the values makes the condition match no rows.
+func (w *WhereBuilder) NotIn(column string, values []interface{}) *WhereBuilder {
+	return w.list(column, "NOT IN", "TRUE", values)
+}
+
+// Between adds column BETWEEN low AND high, both bounds included.
+func (w *WhereBuilder) Between(column string, low, high interface{}) *WhereBuilder {
+	if !w.checkColumn(column) {
+		return w
+	}
+	w.args = append(w.args, low, high)
+	return w.add(func(next func() string) string {
+		low := next()
+		return fmt.Sprintf("%s BETWEEN %s AND %s", QuoteIdentifier(column), low, next())
+	})
+}
+
+// Like adds column LIKE pattern. In pattern, % matches any run of
+// characters and _ any single character.
+func (w *WhereBuilder) Like(column, pattern string) *WhereBuilder {
+	return w.compare(column, "LIKE", pattern)
+}
+
+// ILike adds column ILIKE pattern, the case-insensitive form of Like.
+func (w *WhereBuilder) ILike(column, pattern string) *WhereBuilder {
+	return w.compare(column, "ILIKE", pattern)
+}
+
+// Regex adds column ~ pattern, matching pattern as a POSIX regular
+// expression anywhere in the value.
+func (w *WhereBuilder) Regex(column, pattern string) *WhereBuilder {
+	return w.compare(column, "~", pattern)
+}
+
+// IsNull adds column IS NULL.
+func (w *WhereBuilder) IsNull(column string) *WhereBuilder {
+	if !w.checkColumn(column) {
+		return w
+	}
+	return w.add(func(func() string) string { return QuoteIdentifier(column) + " IS NULL" })
+}
+
+// IsNotNull adds column IS NOT NULL.
+func (w *WhereBuilder) IsNotNull(column string) *WhereBuilder {
+	if !w.checkColumn(column) {
+		return w
+	}
+	return w.add(func(func() string) string { return QuoteIdentifier(column) + " IS NOT NULL" })
+}
+
+// Build returns the clause, starting with " WHERE ", and its arguments.
+// Placeholders are numbered from argOffset+1 so the clause can follow
+// argOffset parameters already in the statement. With no conditions the
+// clause is empty.
+func (w *WhereBuilder) Build(argOffset int) (string, []interface{}, error) {
+	if w.err != nil {
+		return "", nil, w.err
+	}
+	if len(w.conds) == 0 {
+		return "", nil, nil
+	}
+
+	n := argOffset
+	next := func() string {
+		n++
+		return fmt.Sprintf("$%d", n)
+	}
+
+	conds := make([]string, len(w.conds))
+	for i, cond := range w.conds {
+		conds[i] = cond(next)
+	}
+	return " WHERE " + strings.Join(conds, " AND "), w.args, nil
+}
+
+// compare adds "column op value".
+func (w *WhereBuilder) compare(column, op string, value interface{}) *WhereBuilder {
+	if !w.checkColumn(column) {
+		return w
+	}
+	w.args = append(w.args, value)
+	return w.add(func(next func() string) string {
+		return fmt.Sprintf("%s %s %s", QuoteIdentifier(column), op, next())
+	})
+}
+
+// list adds "column op (values...)", or empty when values is empty.
+func (w *WhereBuilder) list(column, op, empty string, values []interface{}) *WhereBuilder {
+	if !w.checkColumn(column) {
+		return w
+	}
+	if len(values) == 0 {
+		return w.add(func(func() string) string { return empty })
+	}
+
+	w.args = append(w.args, values...)
+	return w.add(func(next func() string) string {
+		placeholders := make([]string, len(values))
+		for i := range values {
+			placeholders[i] = next()
+		}
+		return fmt.Sprintf("%s %s (%s)", QuoteIdentifier(column), op, strings.Join(placeholders, ", "))
+	})
+}
+
+// add appends a condition whose arguments have already been recorded.
+func (w *WhereBuilder) add(cond whereCond) *WhereBuilder {
+	w.conds = append(w.conds, cond)
+	return w
+}
+
+// checkColumn records an error for an unsafe column name and reports whether
+// the column may be used.
+func (w *WhereBuilder) checkColumn(column string) bool {
+	if err := validateIdentifier(column); err != nil {
+		if w.err == nil {
+			w.err = err
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package postgresql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWhereBuilder_Build(t *testing.T) {
+	where, args, err := NewWhereBuilder().
+		Eq("status", "active").
+		Gte("age", 18).
+		In("role", []interface{}{"admin", "staff"}).
+		IsNull("deleted_at").
+		Build(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := ` WHERE "status" = $3 AND "age" >= $4 AND "role" IN ($5, $6) AND "deleted_at" IS NULL`
+	if where != expected {
+		t.Errorf("expected %q, got %q", expected, where)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"active", 18, "admin", "staff"}) {
+		t.Errorf("unexpected args: %v", args)
+	}
+}
+
+func TestWhereBuilder_Operators(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *WhereBuilder
+		expected string
+	}{
+		{"not eq", NewWhereBuilder().NotEq("a", 1), ` WHERE "a" <> $1`},
+		{"gt", NewWhereBuilder().Gt("a", 1), ` WHERE "a" > $1`},
+		{"lt", NewWhereBuilder().Lt("a", 1), ` WHERE "a" < $1`},
+		{"lte", NewWhereBuilder().Lte("a", 1), ` WHERE "a" <= $1`},
+		{"not null", NewWhereBuilder().IsNotNull("a"), ` WHERE "a" IS NOT NULL`},
+		{"empty in", NewWhereBuilder().In("a", nil), ` WHERE FALSE`},
+		{"not in", NewWhereBuilder().NotIn("a", []interface{}{1, 2}), ` WHERE "a" NOT IN ($1, $2)`},
+		{"empty not in", NewWhereBuilder().NotIn("a", nil), ` WHERE TRUE`},
+		{"between", NewWhereBuilder().Between("a", 1, 5), ` WHERE "a" BETWEEN $1 AND $2`},
+		{"like", NewWhereBuilder().Like("a", "x%"), ` WHERE "a" LIKE $1`},
+		{"ilike", NewWhereBuilder().ILike("a", "x%"), ` WHERE "a" ILIKE $1`},
+		{"regex", NewWhereBuilder().Regex("a", "^x"), ` WHERE "a" ~ $1`},
+		{"qualified", NewWhereBuilder().Eq("u.id", 1), ` WHERE "u"."id" = $1`},
+		{"empty", NewWhereBuilder(), ``},
+	}
+
+	for _, tt := range tests {
+		where, _, err := tt.builder.Build(0)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if where != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, where)
+		}
+	}
+}
+
+func TestWhereBuilder_InvalidColumn(t *testing.T) {
+	_, _, err := NewWhereBuilder().Eq("ok", 1).Eq("bad; DROP TABLE users", 2).Build(0)
+	if err == nil {
+		t.Error("expected error for an unsafe column, got nil")
+	}
+
+	bad := "bad; DROP TABLE users"
+	builders := map[string]*WhereBuilder{
+		"not in":  NewWhereBuilder().NotIn(bad, []interface{}{1}),
+		"between": NewWhereBuilder().Between(bad, 1, 2),
+		"like":    NewWhereBuilder().Like(bad, "x"),
+		"ilike":   NewWhereBuilder().ILike(bad, "x"),
+		"regex":   NewWhereBuilder().Regex(bad, "x"),
+	}
+	for name, builder := range builders {
+		if _, _, err := builder.Build(0); err == nil {
+			t.Errorf("%s: expected error for an unsafe column, got nil", name)
+		}
+	}
+}
+
+func TestWhereBuilder_BindsPatterns(t *testing.T) {
+	where, args, err := NewWhereBuilder().
+		Between("age", 18, 65).
+		Like("name", "O'Brien%").
+		Build(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := ` WHERE "age" BETWEEN $1 AND $2 AND "name" LIKE $3`; where != expected {
+		t.Errorf("expected %q, got %q", expected, where)
+	}
+	if !reflect.DeepEqual(args, []interface{}{18, 65, "O'Brien%"}) {
+		t.Errorf("unexpected args: %v", args)
+	}
+}
+
+func TestWhereFromMap(t *testing.T) {
+	where, args, err := WhereFromMap(map[string]interface{}{
+		"status":     "active",
+		"deleted_at": nil,
+		"role":       []string{"admin", "staff"},
+		"avatar":     []byte("png"),
+	}).Build(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := ` WHERE "avatar" = $1 AND "deleted_at" IS NULL AND "role" IN ($2, $3) AND "status" = $4`
+	if where != expected {
+		t.Errorf("expected %q, got %q", expected, where)
+	}
+	if !reflect.DeepEqual(args, []interface{}{[]byte("png"), "admin", "staff", "active"}) {
+		t.Errorf("unexpected args: %v", args)
+	}
+}